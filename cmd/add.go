@@ -4,13 +4,13 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"minepack/core/api"
-	"minepack/core/api/curseforge"
-	"minepack/core/api/modrinth"
 	"minepack/core/project"
 	"minepack/util"
 	"os"
+	"strings"
 
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/huh/spinner"
@@ -19,6 +19,7 @@ import (
 
 // dependency resolution context to track what we're processing
 type depResolutionContext struct {
+	cmdCtx           context.Context // cancelled when the user presses ctrl+c
 	packData         *project.Project
 	chooseDeps       bool
 	processedDeps    map[string]bool // slug/id -> processed to avoid duplicates
@@ -27,8 +28,9 @@ type depResolutionContext struct {
 }
 
 // creates a new dependency resolution context
-func newDepResolutionContext(packData *project.Project, chooseDeps bool) *depResolutionContext {
+func newDepResolutionContext(cmdCtx context.Context, packData *project.Project, chooseDeps bool) *depResolutionContext {
 	return &depResolutionContext{
+		cmdCtx:           cmdCtx,
 		packData:         packData,
 		chooseDeps:       chooseDeps,
 		processedDeps:    make(map[string]bool),
@@ -37,45 +39,24 @@ func newDepResolutionContext(packData *project.Project, chooseDeps bool) *depRes
 	}
 }
 
-// fetches a project and its versions based on the source
-func fetchProjectData(identifier string, packData *project.Project) (*project.ContentData, error) {
+// fetches a project and its newest compatible version from the pack's default source
+func fetchProjectData(ctx context.Context, identifier string, packData *project.Project) (*project.ContentData, error) {
+	provider, err := api.ForPack(*packData)
+	if err != nil {
+		return nil, err
+	}
+
 	var result *project.ContentData
 	var fetchErr error
 
-	err := spinner.New().
+	err = spinner.New().
 		Title(fmt.Sprintf("fetching %s...", identifier)).
 		Type(spinner.Dots).
+		Context(ctx).
 		Action(func() {
-			if packData.DefaultSource == "modrinth" {
-				projectData, err := modrinth.GetProject(identifier)
-				if err != nil || projectData == nil {
-					fetchErr = fmt.Errorf("failed to fetch modrinth project %s: %w", identifier, err)
-					return
-				}
-
-				versions, err := modrinth.GetProjectVersions(identifier, *packData)
-				if err != nil || len(versions) == 0 {
-					fetchErr = fmt.Errorf("no compatible versions found for modrinth project %s", identifier)
-					return
-				}
-
-				contentData := modrinth.ConvertProjectToContentData(projectData, versions[0])
-				result = &contentData
-			} else {
-				projectData, err := curseforge.GetProject(identifier)
-				if err != nil || projectData == nil {
-					fetchErr = fmt.Errorf("failed to fetch curseforge project %s: %w", identifier, err)
-					return
-				}
-
-				versions, err := curseforge.GetProjectVersions(identifier, *packData)
-				if err != nil || len(versions) == 0 {
-					fetchErr = fmt.Errorf("no compatible versions found for curseforge project %s", identifier)
-					return
-				}
-
-				contentData := curseforge.ConvertModToContentData(projectData, &versions[0])
-				result = &contentData
+			result, fetchErr = provider.GetProject(ctx, identifier, *packData)
+			if fetchErr != nil {
+				fetchErr = fmt.Errorf("failed to fetch %s project %s: %w", provider.Name(), identifier, fetchErr)
 			}
 		}).
 		Run()
@@ -129,7 +110,7 @@ func handleIncompatibleDependencies(ctx *depResolutionContext) error {
 		),
 	)
 
-	if err := choiceForm.Run(); err != nil {
+	if err := choiceForm.RunWithContext(ctx.cmdCtx); err != nil {
 		return fmt.Errorf("prompt failed: %w", err)
 	}
 
@@ -163,7 +144,7 @@ func handleIncompatibleDependencies(ctx *depResolutionContext) error {
 			),
 		)
 
-		if err := pickForm.Run(); err != nil {
+		if err := pickForm.RunWithContext(ctx.cmdCtx); err != nil {
 			return fmt.Errorf("prompt failed: %w", err)
 		}
 
@@ -224,7 +205,7 @@ func resolveDependenciesRecursively(ctx *depResolutionContext, contentData *proj
 				),
 			)
 
-			if err := pickForm.Run(); err != nil {
+			if err := pickForm.RunWithContext(ctx.cmdCtx); err != nil {
 				return fmt.Errorf("dependency selection prompt failed: %w", err)
 			}
 
@@ -249,8 +230,15 @@ func resolveDependenciesRecursively(ctx *depResolutionContext, contentData *proj
 		}
 	}
 
+	// look up everything we're about to add in bulk rather than one request per dependency
+	resolved := prefetchDependencies(ctx, depsToProcess)
+
 	// process each dependency
 	for _, dep := range depsToProcess {
+		if err := ctx.cmdCtx.Err(); err != nil {
+			return err
+		}
+
 		key := dep.Slug
 		if key == "" {
 			key = dep.Id
@@ -270,16 +258,23 @@ func resolveDependenciesRecursively(ctx *depResolutionContext, contentData *proj
 		// mark as processed to avoid circular dependencies
 		ctx.processedDeps[key] = true
 
-		// fetch the dependency's data
-		identifier := dep.Slug
-		if identifier == "" {
-			identifier = dep.Id
-		}
+		// fetch the dependency's data unless it was already resolved in bulk
+		depContentData, found := resolved[dep.Id]
+		if !found {
+			identifier := dep.Id
+			if identifier == "" {
+				identifier = dep.Slug
+			}
 
-		depContentData, err := fetchProjectData(identifier, ctx.packData)
-		if err != nil {
-			fmt.Printf(util.FormatError("error fetching dependency %s: %s\n"), dep.Name, err)
-			continue
+			var err error
+			depContentData, err = fetchProjectData(ctx.cmdCtx, identifier, ctx.packData)
+			if err != nil {
+				if ctx.cmdCtx.Err() != nil {
+					return ctx.cmdCtx.Err()
+				}
+				fmt.Printf(util.FormatError("error fetching dependency %s: %s\n"), dep.Name, err)
+				continue
+			}
 		}
 
 		// set up the RequiredBy relationship
@@ -298,6 +293,9 @@ func resolveDependenciesRecursively(ctx *depResolutionContext, contentData *proj
 
 		// add the dependency to the modpack
 		if err := ctx.packData.AddContent(*depContentData); err != nil {
+			if ctx.cmdCtx.Err() != nil {
+				return ctx.cmdCtx.Err()
+			}
 			fmt.Printf(util.FormatError("error adding dependency %s: %s\n"), depContentData.Name, err)
 			continue
 		}
@@ -305,6 +303,9 @@ func resolveDependenciesRecursively(ctx *depResolutionContext, contentData *proj
 
 		// recursively resolve this dependency's dependencies
 		if err := resolveDependenciesRecursively(ctx, depContentData, depth+1); err != nil {
+			if ctx.cmdCtx.Err() != nil {
+				return ctx.cmdCtx.Err()
+			}
 			fmt.Printf(util.FormatError("error resolving sub-dependencies for %s: %s\n"), depContentData.Name, err)
 		}
 	}
@@ -312,6 +313,36 @@ func resolveDependenciesRecursively(ctx *depResolutionContext, contentData *proj
 	return nil
 }
 
+// resolves the dependencies about to be added in bulk, keyed by dependency id. failures are
+// ignored, each dependency is fetched on its own then
+func prefetchDependencies(ctx *depResolutionContext, deps []project.Dependency) map[string]*project.ContentData {
+	var withIds []project.Dependency
+	for _, dep := range deps {
+		if dep.Id != "" {
+			withIds = append(withIds, dep)
+		}
+	}
+	if len(withIds) < 2 {
+		return nil
+	}
+
+	provider, err := api.ForPack(*ctx.packData)
+	if err != nil {
+		return nil
+	}
+
+	var resolved map[string]*project.ContentData
+	spinner.New().
+		Title(fmt.Sprintf("fetching %d dependencies...", len(withIds))).
+		Type(spinner.Dots).
+		Context(ctx.cmdCtx).
+		Action(func() {
+			resolved, _ = provider.ResolveDependencies(ctx.cmdCtx, withIds, *ctx.packData)
+		}).
+		Run()
+	return resolved
+}
+
 // updates an existing dependency's RequiredBy field
 func updateDependencyRequiredBy(ctx *depResolutionContext, dep project.Dependency, requiredBy *project.ContentData) error {
 	depContentData, err := ctx.packData.GetContent(dep.Slug)
@@ -369,9 +400,15 @@ func writeIncompatibleSummary(ctx *depResolutionContext) error {
 
 // addCmd represents the add command
 var addCmd = &cobra.Command{
-	Use:     "add",
-	Short:   "add a mod to your modpack",
-	Long:    `allows you to add mods to your modpack by providing a link, slug, id, or search term.`,
+	Use:   "add",
+	Short: "add a mod to your modpack",
+	Long: `allows you to add mods to your modpack by providing a link, slug, id, or search term.
+
+prefix the query with a source name to add from a specific source, e.g. "github:owner/repo" or
+"curseforge:jei". github content uses the newest release with a jar for your game version and
+loader, use --asset to pick the file by pattern instead ({mc} and {loader} are filled in).
+maven content is added with "maven:https://maven.example.com:group:artifact[:version]", private
+repositories read their credentials from credentials.mp.yaml in the minepack config directory.`,
 	Aliases: []string{"install"},
 	Run: func(cmd *cobra.Command, args []string) {
 		// get current working directory and parse project
@@ -387,21 +424,9 @@ var addCmd = &cobra.Command{
 			return
 		}
 
-		// Get flags for source preference
-		useModrinth, _ := cmd.Flags().GetBool("modrinth")
-		useCurseforge, _ := cmd.Flags().GetBool("curseforge")
-
-		// Validate source flags
-		if useModrinth && useCurseforge {
-			fmt.Println(util.FormatError("cannot specify both --modrinth and --curseforge flags\n"))
-			return
-		}
-
 		// Override packData default source if flags are provided
-		if useModrinth {
-			packData.DefaultSource = "modrinth"
-		} else if useCurseforge {
-			packData.DefaultSource = "curseforge"
+		if !applySourceFlags(cmd, packData) {
+			return
 		}
 
 		// validate arguments
@@ -419,6 +444,11 @@ var addCmd = &cobra.Command{
 			query += arg
 		}
 
+		// an asset pattern picks which release file is used for github content
+		if asset, _ := cmd.Flags().GetString("asset"); asset != "" && !strings.Contains(query, "#") {
+			query += "#" + asset
+		}
+
 		// search for the mod
 		var result *project.ContentData
 		var searchErr error
@@ -426,11 +456,15 @@ var addCmd = &cobra.Command{
 		err = spinner.New().
 			Title("searching for mods...").
 			Type(spinner.Dots).
+			Context(cmd.Context()).
 			Action(func() {
-				result, searchErr = api.SearchAll(query, *packData)
+				result, searchErr = api.SearchAll(cmd.Context(), query, *packData)
 			}).
 			Run()
 
+		if interrupted(cmd, "nothing was added") {
+			return
+		}
 		if err != nil {
 			fmt.Printf(util.FormatError("spinner error: %s"), err)
 			return
@@ -450,7 +484,15 @@ var addCmd = &cobra.Command{
 
 		// set up dependency resolution context
 		chooseDeps, _ := cmd.Flags().GetBool("choose-dependencies")
-		ctx := newDepResolutionContext(packData, chooseDeps)
+		ctx := newDepResolutionContext(cmd.Context(), packData, chooseDeps)
+
+		// the mod and its dependencies are committed together, or not at all if adding them stops part way
+		batch, err := packData.Begin(cmd.Context())
+		if err != nil {
+			fmt.Printf(util.FormatError("error starting changes: %s\n"), err)
+			return
+		}
+		defer batch.Rollback()
 
 		// handle incompatible dependencies first
 		for _, dep := range result.Dependencies {
@@ -460,27 +502,43 @@ var addCmd = &cobra.Command{
 		}
 
 		if err := handleIncompatibleDependencies(ctx); err != nil {
+			if interrupted(cmd, "nothing was added") {
+				return
+			}
 			fmt.Printf(util.FormatError("dependency conflict resolution failed: %s"), err)
 			return
 		}
 
 		// resolve all dependencies recursively
 		if err := resolveDependenciesRecursively(ctx, result, 0); err != nil {
+			if interrupted(cmd, "nothing was added") {
+				return
+			}
 			fmt.Printf(util.FormatError("dependency resolution failed: %s"), err)
 			return
 		}
 
 		// add the main mod
 		if err := packData.AddContent(*result); err != nil {
+			if interrupted(cmd, "nothing was added") {
+				return
+			}
 			fmt.Printf(util.FormatError("error adding mod %s: %s\n"), result.Name, err)
 			return
 		}
-		fmt.Printf(util.FormatSuccess("successfully added mod %s\n"), result.Name)
 
 		// write incompatible dependencies summary
 		if err := writeIncompatibleSummary(ctx); err != nil {
 			fmt.Printf(util.FormatError("failed to write incompatible summary: %s"), err)
 		}
+
+		if err := batch.Commit(fmt.Sprintf("Add content: %s", result.Slug)); err != nil {
+			if interrupted(cmd, "nothing was added") {
+				return
+			}
+			fmt.Printf(util.FormatWarning("warning: failed to commit changes: %s\n"), err)
+		}
+		fmt.Printf(util.FormatSuccess("successfully added mod %s\n"), result.Name)
 	},
 }
 
@@ -500,4 +558,6 @@ func init() {
 	addCmd.Flags().BoolP("choose-dependencies", "d", false, "when enabled, you will manually choose which dependencies to add (if applicable). by default, all required dependencies are added automatically.")
 	addCmd.Flags().Bool("modrinth", false, "search and add mods from Modrinth only")
 	addCmd.Flags().Bool("curseforge", false, "search and add mods from CurseForge only")
+	addCmd.Flags().String("source", "", "search and add mods from a specific source (e.g. modrinth, curseforge)")
+	addCmd.Flags().String("asset", "", "filename pattern of the release asset to use for github content (e.g. \"sodium-{loader}-*+mc{mc}.jar\")")
 }
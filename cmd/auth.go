@@ -0,0 +1,165 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"minepack/core/config"
+	"minepack/util"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+)
+
+// sources that authenticate with a single API key or token
+var authSources = []string{"curseforge", "github", "modrinth"}
+
+// checkAuthSource prints an error and returns false for sources without token support
+func checkAuthSource(source string) bool {
+	for _, s := range authSources {
+		if s == source {
+			return true
+		}
+	}
+	fmt.Printf(util.FormatError("%s has no token to log in with, must be one of %v\n"), source, authSources)
+	if source == "maven" {
+		fmt.Println("maven credentials are set per repository in credentials.mp.yaml")
+	}
+	return false
+}
+
+// maskToken shows just enough of a token to recognise it
+func maskToken(token string) string {
+	if len(token) <= 8 {
+		return strings.Repeat("*", len(token))
+	}
+	return token[:4] + strings.Repeat("*", 8) + token[len(token)-4:]
+}
+
+// authCmd represents the auth command
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "manage API keys and tokens for sources",
+	Long: `manages the credentials minepack sends to each source: a CurseForge API key, a Modrinth
+personal access token (for private and unlisted projects) or a GitHub token (for private repositories
+and higher rate limits).
+
+credentials are looked up in environment variables first (e.g. MINEPACK_CURSEFORGE_TOKEN or
+CURSEFORGE_API_KEY), then credentials.mp.yaml in the minepack config directory, then the OS keyring.`,
+}
+
+// authLoginCmd represents the auth login command
+var authLoginCmd = &cobra.Command{
+	Use:   "login <source>",
+	Short: "save an API key or token for a source",
+	Long:  `saves an API key or token for a source in the OS keyring, or in credentials.mp.yaml when no keyring is available.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		source := args[0]
+		if !checkAuthSource(source) {
+			return
+		}
+
+		token, _ := cmd.Flags().GetString("token")
+		if token == "" {
+			err := huh.NewInput().
+				Title(fmt.Sprintf("%s token", source)).
+				Description("paste the API key or token, it won't be shown").
+				EchoMode(huh.EchoModePassword).
+				Value(&token).
+				Run()
+			if err != nil {
+				fmt.Printf(util.FormatError("prompt failed: %s\n"), err)
+				return
+			}
+		}
+		token = strings.TrimSpace(token)
+		if token == "" {
+			fmt.Println(util.FormatError("no token given"))
+			return
+		}
+
+		origin, err := config.SaveToken(source, token)
+		if err != nil {
+			fmt.Printf(util.FormatError("failed to save token: %s\n"), err)
+			return
+		}
+		fmt.Printf(util.FormatSuccess("saved %s token in the %s\n"), source, origin)
+
+		if current, from := config.Token(source); from == config.OriginEnv && current != token {
+			fmt.Printf(util.FormatWarning("note: a token set in the environment (%s) still takes priority\n"), strings.Join(config.EnvVars(source), " or "))
+		}
+	},
+}
+
+// authLogoutCmd represents the auth logout command
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout <source>",
+	Short: "remove the saved API key or token for a source",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		source := args[0]
+		if !checkAuthSource(source) {
+			return
+		}
+
+		removed, err := config.DeleteToken(source)
+		if err != nil {
+			fmt.Printf(util.FormatError("failed to remove token: %s\n"), err)
+			return
+		}
+		if removed {
+			fmt.Printf(util.FormatSuccess("removed saved %s token\n"), source)
+		} else {
+			fmt.Printf("no saved %s token\n", source)
+		}
+
+		if _, from := config.Token(source); from == config.OriginEnv {
+			fmt.Printf(util.FormatWarning("note: a token is still set in the environment (%s)\n"), strings.Join(config.EnvVars(source), " or "))
+		}
+	},
+}
+
+// authStatusCmd represents the auth status command
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "show which credentials each source uses",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("credentials:")
+		for _, source := range authSources {
+			token, origin := config.Token(source)
+			switch {
+			case origin != config.OriginNone:
+				fmt.Printf("- %s: %s (from %s)\n", source, maskToken(token), origin)
+			case source == "curseforge":
+				fmt.Printf("- %s: using the built-in shared key, which is heavily rate limited\n", source)
+			default:
+				fmt.Printf("- %s: not logged in\n", source)
+			}
+		}
+
+		if creds, err := config.LoadCredentials(); err == nil && len(creds.Maven) > 0 {
+			fmt.Printf("- maven: %d repositories configured\n", len(creds.Maven))
+		}
+
+		if path, err := config.CredentialsPath(); err == nil {
+			fmt.Printf("\ncredentials file: %s\n", path)
+		}
+		if config.KeyringAvailable() {
+			fmt.Println("keyring: available")
+		} else {
+			fmt.Println("keyring: not available, tokens are saved in the credentials file")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authLoginCmd)
+	authCmd.AddCommand(authLogoutCmd)
+	authCmd.AddCommand(authStatusCmd)
+
+	authLoginCmd.Flags().String("token", "", "the token to save, prompted for when not given")
+}
@@ -5,9 +5,19 @@ package cmd
 
 import (
 	"fmt"
+	"math"
+	"minepack/core"
+	"minepack/core/api"
+	"minepack/core/cache"
+	"minepack/core/download"
+	"minepack/core/project"
 	"minepack/util"
+	"net/url"
 	"os"
-	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/huh"
 	"github.com/spf13/cobra"
@@ -27,92 +37,143 @@ func formatCacheSize(bytes int64) string {
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
 
-// getCacheSize calculates the total size of the cache directory
-func getCacheSize(cachePath string) (int64, error) {
-	var size int64
-	err := filepath.Walk(cachePath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+// cacheStats summarises how the download cache is shared between projects
+type cacheStats struct {
+	files        int
+	size         int64
+	projects     int
+	sharedFiles  int
+	savedBytes   int64 // bytes that would have been downloaded again without sharing
+	projectFiles int   // files used by the project in the current directory
+	projectSize  int64
+}
+
+func getCacheStats(c *cache.Cache, projectRoot string) cacheStats {
+	var stats cacheStats
+	projects := make(map[string]bool)
+	for _, entry := range c.Entries() {
+		stats.files++
+		stats.size += entry.Size
+		for _, p := range entry.Projects {
+			projects[p] = true
+			if p == projectRoot {
+				stats.projectFiles++
+				stats.projectSize += entry.Size
+			}
 		}
-		if !info.IsDir() {
-			size += info.Size()
+		if len(entry.Projects) > 1 {
+			stats.sharedFiles++
+			stats.savedBytes += entry.Size * int64(len(entry.Projects)-1)
 		}
-		return nil
-	})
-	return size, err
+	}
+	stats.projects = len(projects)
+	return stats
+}
+
+// printCacheStats prints the location, size and sharing information of the cache
+func printCacheStats(c *cache.Cache, stats cacheStats, projectRoot string) {
+	fmt.Printf("cache information:\n")
+	fmt.Printf("- location: %s\n", c.Root())
+	fmt.Printf("- files: %d\n", stats.files)
+	fmt.Printf("- size: %s\n", formatCacheSize(stats.size))
+	fmt.Printf("- used by: %d projects\n", stats.projects)
+	fmt.Printf("- shared files: %d (%s not downloaded again)\n", stats.sharedFiles, formatCacheSize(stats.savedBytes))
+	if projectRoot != "" {
+		fmt.Printf("- this project: %d files, %s\n", stats.projectFiles, formatCacheSize(stats.projectSize))
+	}
+}
+
+// currentProjectRoot returns the working directory if it holds a project, or "" otherwise
+func currentProjectRoot() string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	if _, err := project.ParseProject(cwd); err != nil {
+		return ""
+	}
+	return cwd
 }
 
-// countCacheFiles counts the number of files in the cache directory
-func countCacheFiles(cachePath string) (int, error) {
-	var count int
-	err := filepath.Walk(cachePath, func(path string, info os.FileInfo, err error) error {
+// parseCacheAge parses durations like 30d, 2w or anything time.ParseDuration accepts
+func parseCacheAge(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	units := map[byte]time.Duration{'d': 24 * time.Hour, 'w': 7 * 24 * time.Hour}
+	if unit, ok := units[value[len(value)-1]]; ok {
+		n, err := strconv.ParseFloat(value[:len(value)-1], 64)
 		if err != nil {
-			return err
+			return 0, fmt.Errorf("invalid age %q", value)
 		}
-		if !info.IsDir() {
-			count++
+		return time.Duration(n * float64(unit)), nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid age %q", value)
+	}
+	return d, nil
+}
+
+// parseCacheSize parses sizes like 500M, 2GB or 1.5G into bytes
+func parseCacheSize(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	v := strings.TrimSuffix(strings.TrimSuffix(strings.ToUpper(value), "B"), "I")
+	multiplier := float64(1)
+	if v != "" {
+		if i := strings.IndexByte("KMGT", v[len(v)-1]); i >= 0 {
+			multiplier = math.Pow(1024, float64(i+1))
+			v = v[:len(v)-1]
 		}
-		return nil
-	})
-	return count, err
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", value)
+	}
+	return int64(n * multiplier), nil
 }
 
 // cacheCmd represents the cache command
 var cacheCmd = &cobra.Command{
 	Use:   "cache",
-	Short: "manage modpack download cache",
-	Long:  `view cache information and clear cached mod files`,
+	Short: "manage the shared download cache",
+	Long:  `view, prune and clear the download cache shared by every project on this machine`,
 }
 
 // cacheClearCmd represents the cache clear command
 var cacheClearCmd = &cobra.Command{
 	Use:   "clear",
-	Short: "clear the modpack download cache",
-	Long:  `removes all cached mod files to free up disk space`,
+	Short: "clear the download cache",
+	Long:  `removes all cached mod files to free up disk space. every project will download its files again on next use`,
 	Run: func(cmd *cobra.Command, args []string) {
-		// Get current working directory
-		cwd, err := os.Getwd()
-		if err != nil {
-			fmt.Printf(util.FormatError("error getting current working directory: %s"), err)
-			return
-		}
-
-		cachePath := filepath.Join(cwd, ".mpcache")
-
-		// Check if cache directory exists
-		if _, err := os.Stat(cachePath); os.IsNotExist(err) {
-			fmt.Println("no cache directory found")
-			return
-		}
-
-		// Get cache information
-		cacheSize, err := getCacheSize(cachePath)
-		if err != nil {
-			fmt.Printf(util.FormatError("error calculating cache size: %s"), err)
-			return
-		}
-
-		fileCount, err := countCacheFiles(cachePath)
+		c, err := cache.Open()
 		if err != nil {
-			fmt.Printf(util.FormatError("error counting cache files: %s"), err)
+			fmt.Printf(util.FormatError("error opening cache: %s"), err)
 			return
 		}
 
-		if fileCount == 0 {
+		projectRoot := currentProjectRoot()
+		stats := getCacheStats(c, projectRoot)
+		if stats.files == 0 {
 			fmt.Println("cache is already empty")
 			return
 		}
 
 		// Show cache info and ask for confirmation
-		fmt.Printf("cache information:\n")
-		fmt.Printf("- location: %s\n", cachePath)
-		fmt.Printf("- files: %d\n", fileCount)
-		fmt.Printf("- size: %s\n\n", formatCacheSize(cacheSize))
+		printCacheStats(c, stats, projectRoot)
+		fmt.Println()
+
+		description := "this will delete all cached mod files"
+		if stats.projects > 1 {
+			description = fmt.Sprintf("this will delete cached mod files used by %d projects", stats.projects)
+		}
 
 		var proceed bool
 		huh.NewConfirm().
 			Title("clear cache?").
-			Description("this will delete all cached mod files").
+			Description(description).
 			Affirmative("yes, clear it").
 			Negative("no, keep it").
 			Value(&proceed).
@@ -123,13 +184,12 @@ var cacheClearCmd = &cobra.Command{
 			return
 		}
 
-		// Remove the cache directory
-		if err := os.RemoveAll(cachePath); err != nil {
+		if err := c.Clear(); err != nil {
 			fmt.Printf(util.FormatError("error clearing cache: %s"), err)
 			return
 		}
 
-		fmt.Print(util.FormatSuccess("cache cleared successfully!"))
+		fmt.Printf(util.FormatSuccess("cache cleared successfully! freed %s"), formatCacheSize(stats.size))
 	},
 }
 
@@ -137,49 +197,245 @@ var cacheClearCmd = &cobra.Command{
 var cacheInfoCmd = &cobra.Command{
 	Use:   "info",
 	Short: "show cache information",
-	Long:  `displays cache size, file count, and location`,
+	Long:  `displays cache size, file count, location and how files are shared between projects`,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := cache.Open()
+		if err != nil {
+			fmt.Printf(util.FormatError("error opening cache: %s"), err)
+			return
+		}
+
+		projectRoot := currentProjectRoot()
+		stats := getCacheStats(c, projectRoot)
+		printCacheStats(c, stats, projectRoot)
+
+		if stats.files == 0 {
+			fmt.Println("\ncache is empty")
+		}
+	},
+}
+
+// cachePruneCmd represents the cache prune command
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "remove old or excess files from the cache",
+	Long: `removes cached files that haven't been used recently and, if the cache is still too big,
+the least recently used files until it fits.
+
+examples:
+  minepack cache prune --older-than 30d
+  minepack cache prune --max-size 2G
+  minepack cache prune --older-than 2w --max-size 500M --dry-run`,
 	Run: func(cmd *cobra.Command, args []string) {
-		// Get current working directory
+		olderThanFlag, _ := cmd.Flags().GetString("older-than")
+		maxSizeFlag, _ := cmd.Flags().GetString("max-size")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		olderThan, err := parseCacheAge(olderThanFlag)
+		if err != nil {
+			fmt.Printf(util.FormatError("error parsing --older-than: %s"), err)
+			return
+		}
+		maxSize, err := parseCacheSize(maxSizeFlag)
+		if err != nil {
+			fmt.Printf(util.FormatError("error parsing --max-size: %s"), err)
+			return
+		}
+		if olderThan == 0 && maxSize == 0 {
+			fmt.Print(util.FormatError("specify --older-than and/or --max-size"))
+			return
+		}
+
+		c, err := cache.Open()
+		if err != nil {
+			fmt.Printf(util.FormatError("error opening cache: %s"), err)
+			return
+		}
+
+		removed, err := c.Prune(olderThan, maxSize, dryRun)
+		if err != nil {
+			fmt.Printf(util.FormatError("error pruning cache: %s"), err)
+			return
+		}
+
+		if len(removed) == 0 {
+			fmt.Println("nothing to prune")
+			return
+		}
+
+		var freed int64
+		for _, entry := range removed {
+			freed += entry.Size
+			fmt.Printf("- %s (%s, last used %s)\n", entry.Filename, formatCacheSize(entry.Size), entry.LastAccess.Format("2006-01-02"))
+		}
+
+		if dryRun {
+			fmt.Printf("\nwould remove %d files, freeing %s\n", len(removed), formatCacheSize(freed))
+			return
+		}
+		fmt.Printf(util.FormatSuccess("\nremoved %d files, freed %s\n"), len(removed), formatCacheSize(freed))
+	},
+}
+
+// cacheDownloadCmd represents the cache download command
+var cacheDownloadCmd = &cobra.Command{
+	Use:     "download",
+	Short:   "download all modpack content into the cache",
+	Long:    `fetches every mod in the modpack into the download cache so later syncs and exports don't need to download them`,
+	Aliases: []string{"fill", "warm"},
+	Run: func(cmd *cobra.Command, args []string) {
+		// Get current working directory and parse project
 		cwd, err := os.Getwd()
 		if err != nil {
 			fmt.Printf(util.FormatError("error getting current working directory: %s"), err)
 			return
 		}
 
-		cachePath := filepath.Join(cwd, ".mpcache")
+		packData, err := project.ParseProject(cwd)
+		if err != nil {
+			fmt.Printf(util.FormatError("error parsing project: %s"), err)
+			return
+		}
+
+		allContent, err := packData.GetAllContent()
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting all content: %s"), err)
+			return
+		}
+
+		// custom content without a download url lives in overrides and has nothing to download
+		var jobs []download.Job
+		var jobContents []project.ContentData
+		for _, content := range allContent {
+			if content.InOverrides() {
+				continue
+			}
+			jobs = append(jobs, api.DownloadJob(content, ""))
+			jobContents = append(jobContents, content)
+		}
 
-		// Check if cache directory exists
-		if _, err := os.Stat(cachePath); os.IsNotExist(err) {
-			fmt.Println("no cache directory found")
+		if len(jobs) == 0 {
+			fmt.Println("nothing to download")
 			return
 		}
 
-		// Get cache information
-		cacheSize, err := getCacheSize(cachePath)
+		manager := newDownloadManager(cmd, cwd)
+		if manager.Cache == nil {
+			return
+		}
+
+		results, err := manager.Run(cmd.Context(), jobs)
 		if err != nil {
-			fmt.Printf(util.FormatError("error calculating cache size: %s"), err)
+			fmt.Print(util.FormatError("download cancelled\n"))
 			return
 		}
 
-		fileCount, err := countCacheFiles(cachePath)
+		recordComputedHashes(cmd.Context(), packData, jobContents, results)
+
+		failed := download.Failed(results)
+		for _, f := range failed {
+			fmt.Printf(util.FormatError("failed to download %s: %s\n"), f.Job.Name, f.Err)
+		}
+		if len(failed) == 0 {
+			fmt.Printf(util.FormatSuccess("cached %d files in %s\n"), len(jobs), manager.Cache.Root())
+		}
+	},
+}
+
+// cacheMetadataCmd represents the cache metadata command
+var cacheMetadataCmd = &cobra.Command{
+	Use:   "metadata",
+	Short: "show cached API and version metadata",
+	Long: `displays the responses cached from Modrinth, CurseForge, Mojang and mod loader mavens.
+these are kept separately from downloaded files and are revalidated after a short time, use --refresh on any command to revalidate immediately`,
+	Aliases: []string{"meta"},
+	Run: func(cmd *cobra.Command, args []string) {
+		list, _ := cmd.Flags().GetBool("list")
+
+		store, err := cache.OpenMetadata()
 		if err != nil {
-			fmt.Printf(util.FormatError("error counting cache files: %s"), err)
+			fmt.Printf(util.FormatError("error opening metadata cache: %s"), err)
 			return
 		}
 
-		fmt.Printf("cache information:\n")
-		fmt.Printf("- location: %s\n", cachePath)
-		fmt.Printf("- files: %d\n", fileCount)
-		fmt.Printf("- size: %s\n", formatCacheSize(cacheSize))
+		responses, err := store.Responses()
+		if err != nil {
+			fmt.Printf(util.FormatError("error reading metadata cache: %s"), err)
+			return
+		}
 
-		if fileCount == 0 {
-			fmt.Println("\ncache is empty")
+		var size int64
+		hosts := make(map[string]int)
+		for _, resp := range responses {
+			size += int64(len(resp.Body))
+			if u, err := url.Parse(resp.URL); err == nil {
+				hosts[u.Host]++
+			}
+		}
+
+		fmt.Printf("metadata cache information:\n")
+		fmt.Printf("- location: %s\n", store.Dir())
+		fmt.Printf("- responses: %d\n", len(responses))
+		fmt.Printf("- size: %s\n", formatCacheSize(size))
+		fmt.Printf("- revalidated after: %s\n", core.DefaultMetadataTTL)
+
+		if len(responses) == 0 {
+			fmt.Println("\nmetadata cache is empty")
+			return
+		}
+
+		hostNames := make([]string, 0, len(hosts))
+		for host := range hosts {
+			hostNames = append(hostNames, host)
+		}
+		sort.Strings(hostNames)
+		fmt.Println("\nby host:")
+		for _, host := range hostNames {
+			fmt.Printf("- %s: %d\n", host, hosts[host])
+		}
+
+		if list {
+			fmt.Println("\nresponses:")
+			for _, resp := range responses {
+				fmt.Printf("- %s (%s, stored %s)\n", resp.URL, formatCacheSize(int64(len(resp.Body))), resp.Stored.Format("2006-01-02 15:04"))
+			}
 		}
 	},
 }
 
+// cacheMetadataClearCmd represents the cache metadata clear command
+var cacheMetadataClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "clear cached API and version metadata",
+	Long:  `removes every cached API response without touching downloaded files. offline mode will not be able to answer requests until they are cached again`,
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := cache.OpenMetadata()
+		if err != nil {
+			fmt.Printf(util.FormatError("error opening metadata cache: %s"), err)
+			return
+		}
+
+		if err := store.Clear(); err != nil {
+			fmt.Printf(util.FormatError("error clearing metadata cache: %s"), err)
+			return
+		}
+
+		fmt.Print(util.FormatSuccess("metadata cache cleared successfully!"))
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(cacheCmd)
 	cacheCmd.AddCommand(cacheClearCmd)
 	cacheCmd.AddCommand(cacheInfoCmd)
+	cacheCmd.AddCommand(cacheDownloadCmd)
+	cacheCmd.AddCommand(cachePruneCmd)
+	cacheCmd.AddCommand(cacheMetadataCmd)
+	cacheMetadataCmd.AddCommand(cacheMetadataClearCmd)
+
+	cacheMetadataCmd.Flags().Bool("list", false, "list every cached response")
+
+	cachePruneCmd.Flags().String("older-than", "", "remove files not used within this long (e.g. 30d, 2w, 12h)")
+	cachePruneCmd.Flags().String("max-size", "", "remove the least recently used files until the cache is at most this big (e.g. 2G, 500M)")
+	cachePruneCmd.Flags().Bool("dry-run", false, "only list the files that would be removed")
 }
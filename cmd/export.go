@@ -5,16 +5,19 @@ package cmd
 
 import (
 	"archive/zip"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"minepack/core/api/curseforge"
-	"minepack/core/api/modrinth"
+	"minepack/core/api"
+	"minepack/core/download"
 	"minepack/core/project"
 	"minepack/util"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -23,7 +26,7 @@ import (
 var exportCmd = &cobra.Command{
 	Use:   "export",
 	Short: "export your modpack to various formats",
-	Long:  `export your modpack to different formats like modrinth (.mrpack) or simple zip`,
+	Long:  `export your modpack to different formats like modrinth (.mrpack) or curseforge (.zip)`,
 }
 
 // copyDirExport recursively copies a directory
@@ -64,6 +67,34 @@ func copyDirExport(src, dst string) error {
 	})
 }
 
+// copyOverridesExport copies the overrides used on a side into dst, with the side's own overrides
+// replacing shared ones at the same path
+func copyOverridesExport(root, dst, side string) error {
+	for _, folder := range project.OverrideFolders(side) {
+		src := filepath.Join(root, folder)
+		if _, err := os.Stat(src); err != nil {
+			continue
+		}
+		if err := copyDirExport(src, dst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasOverrideFiles reports whether an override folder contains any files
+func hasOverrideFiles(dir string) bool {
+	found := false
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || found {
+			return filepath.SkipAll
+		}
+		found = !info.IsDir()
+		return nil
+	})
+	return found
+}
+
 // modSideDataToString converts ModSideData to string for modrinth format
 func modSideDataToString(side project.ModSideData) string {
 	switch side {
@@ -80,25 +111,59 @@ func modSideDataToString(side project.ModSideData) string {
 	}
 }
 
-// downloadContent downloads a single content item based on its source
-func downloadContent(content project.ContentData, destPath string) error {
-	// Ensure directory exists
-	dir := filepath.Dir(destPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory %s: %w", dir, err)
-	}
-
-	switch content.Source {
-	case project.Modrinth:
-		return modrinth.DownloadContent(content, destPath)
-	case project.Curseforge:
-		return curseforge.DownloadContent(content, destPath)
-	case project.Custom:
-		// For custom content, we need to copy from the overrides folder
-		// The content should already be in overrides, so we don't download it
-		return fmt.Errorf("custom content should be handled separately")
+// packVersion is the pack's current version from its version history
+func packVersion(packData *project.Project) (string, error) {
+	history, err := project.ParseVersionHistory(packData.Root)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the pack version: %w", err)
+	}
+	return history.Current, nil
+}
+
+// exportOutput works out where an export is written: --output when it is set, otherwise
+// defaultName. both may use the {name}, {version}, {mc} and {loader} placeholders. an
+// export to a file that is given an existing directory, or a path ending in a separator,
+// is written into it under defaultName
+func exportOutput(cmd *cobra.Command, packData *project.Project, defaultName string, isDir bool) (string, error) {
+	version, err := packVersion(packData)
+	if err != nil {
+		return "", err
+	}
+	expand := strings.NewReplacer(
+		"{name}", exportNamePart(packData.Name),
+		"{version}", exportNamePart(version),
+		"{mc}", exportNamePart(packData.Versions.Game),
+		"{loader}", exportNamePart(packData.Versions.Loader.Name),
+	).Replace
+
+	output, _ := cmd.Flags().GetString("output")
+	if output == "" {
+		return expand(defaultName), nil
+	}
+	output = expand(output)
+	if !isDir {
+		info, err := os.Stat(output)
+		if strings.HasSuffix(output, "/") || strings.HasSuffix(output, string(filepath.Separator)) || (err == nil && info.IsDir()) {
+			output = filepath.Join(output, expand(defaultName))
+		}
+	}
+	return output, nil
+}
+
+// exportNamePart keeps a value put into an output path from adding directories to it
+func exportNamePart(value string) string {
+	return strings.NewReplacer("/", "-", "\\", "-").Replace(value)
+}
+
+// sideName describes where content runs, "both" unless one side doesn't support it
+func sideName(side project.ModSide) string {
+	switch {
+	case side.Client == project.SideUnsupported && side.Server != project.SideUnsupported:
+		return "server"
+	case side.Server == project.SideUnsupported && side.Client != project.SideUnsupported:
+		return "client"
 	default:
-		return fmt.Errorf("unsupported content source: %v", content.Source)
+		return "both"
 	}
 }
 
@@ -145,8 +210,15 @@ var exportModrinthCmd = &cobra.Command{
 		}
 
 		// Export as .mrpack
-		outputName := fmt.Sprintf("%s.mrpack", packData.Name)
-		if err := exportModrinthPack(packData, allContent, outputName); err != nil {
+		outputName, err := exportOutput(cmd, packData, "{name}-{version}.mrpack", false)
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting output path: %s"), err)
+			return
+		}
+		if err := exportModrinthPack(cmd.Context(), newDownloadManager(cmd, cwd), packData, allContent, outputName); err != nil {
+			if interrupted(cmd, "no pack was written") {
+				return
+			}
 			fmt.Printf(util.FormatError("failed to export modrinth pack: %s"), err)
 			return
 		}
@@ -157,25 +229,30 @@ var exportModrinthCmd = &cobra.Command{
 }
 
 // exportModrinthPack exports the pack as a .mrpack file
-func exportModrinthPack(packData *project.Project, allContent []project.ContentData, outputName string) error {
+func exportModrinthPack(ctx context.Context, manager *download.Manager, packData *project.Project, allContent []project.ContentData, outputName string) error {
 	// Create temporary directory for building the pack
-	tempDir, err := os.MkdirTemp("", "minepack-export-*")
+	tempDir, err := exportTempDir(manager)
 	if err != nil {
 		return fmt.Errorf("failed to create temp directory: %w", err)
 	}
 	defer os.RemoveAll(tempDir)
 
-	// Copy overrides folder if it exists
-	overridesPath := filepath.Join(packData.Root, "overrides")
-	if _, err := os.Stat(overridesPath); err == nil {
-		tempOverridesPath := filepath.Join(tempDir, "overrides")
-		if err := copyDirExport(overridesPath, tempOverridesPath); err != nil {
-			return fmt.Errorf("failed to copy overrides: %w", err)
+	// Copy the override folders that exist, launchers apply the side specific ones themselves
+	for _, folder := range []string{project.OverridesFolder, project.ClientOverridesFolder, project.ServerOverridesFolder} {
+		overridesPath := filepath.Join(packData.Root, folder)
+		if _, err := os.Stat(overridesPath); err == nil {
+			if err := copyDirExport(overridesPath, filepath.Join(tempDir, folder)); err != nil {
+				return fmt.Errorf("failed to copy %s: %w", folder, err)
+			}
 		}
 	}
 
 	// Create modrinth.index.json
-	modrinthIndex := createModrinthIndex(packData, allContent)
+	version, err := packVersion(packData)
+	if err != nil {
+		return err
+	}
+	modrinthIndex := createModrinthIndex(packData, version, allContent)
 	indexData, err := json.MarshalIndent(modrinthIndex, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal modrinth index: %w", err)
@@ -187,22 +264,31 @@ func exportModrinthPack(packData *project.Project, allContent []project.ContentD
 	}
 
 	// Download non-modrinth content to overrides
+	// custom content without a download url already lives in the overrides folder, so it is skipped
+	var jobs []download.Job
+	var jobContents []project.ContentData
 	for _, content := range allContent {
-		if content.Source != project.Modrinth {
-			// Download to overrides folder
+		if content.Source != project.Modrinth && !content.InOverrides() {
 			destPath := filepath.Join(tempDir, "overrides", getContentPath(content))
-			if err := downloadContent(content, destPath); err != nil && content.Source != project.Custom {
-				return fmt.Errorf("failed to download %s: %w", content.Name, err)
-			}
+			jobs = append(jobs, api.DownloadJob(content, destPath))
+			jobContents = append(jobContents, content)
 		}
 	}
+	results, err := manager.Run(ctx, jobs)
+	if err != nil {
+		return fmt.Errorf("download cancelled: %w", err)
+	}
+	recordComputedHashes(ctx, packData, jobContents, results)
+	if failed := download.Failed(results); len(failed) > 0 {
+		return fmt.Errorf("failed to download %s: %w", failed[0].Job.Name, failed[0].Err)
+	}
 
 	// Create the .mrpack zip file
-	return createZipFile(tempDir, outputName)
+	return createZipFile(ctx, tempDir, outputName)
 }
 
 // createModrinthIndex creates a modrinth.index.json structure
-func createModrinthIndex(packData *project.Project, allContent []project.ContentData) map[string]interface{} {
+func createModrinthIndex(packData *project.Project, version string, allContent []project.ContentData) map[string]interface{} {
 	// Build dependencies
 	dependencies := map[string]string{
 		"minecraft": packData.Versions.Game,
@@ -253,10 +339,14 @@ func createModrinthIndex(packData *project.Project, allContent []project.Content
 		}
 	}
 
+	sort.Slice(files, func(i, j int) bool {
+		return files[i]["path"].(string) < files[j]["path"].(string)
+	})
+
 	return map[string]interface{}{
 		"formatVersion": 1,
 		"game":          "minecraft",
-		"versionId":     packData.Versions.Minepack,
+		"versionId":     version,
 		"name":          packData.Name,
 		"summary":       fmt.Sprintf("%s by %s - %s", packData.Name, packData.Author, packData.Description),
 		"files":         files,
@@ -264,18 +354,22 @@ func createModrinthIndex(packData *project.Project, allContent []project.Content
 	}
 }
 
-// createZipFile creates a zip file from a directory
-func createZipFile(sourceDir, outputName string) error {
-	file, err := os.Create(outputName)
-	if err != nil {
-		return fmt.Errorf("failed to create zip file: %w", err)
+// zipModTime is the modification time of every zip entry, so exporting the same pack twice gives
+// the same file. it is the earliest time zip files can store
+var zipModTime = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// createZipFile creates a zip file from a directory. entries are sorted with fixed times and
+// permissions so the same pack always gives the same zip. it is written next to outputName and
+// only moved into place once complete, so an interrupted export leaves no partial file behind
+func createZipFile(ctx context.Context, sourceDir, outputName string) error {
+	// Collect the entries first, so their order doesn't depend on the filesystem
+	type zipEntry struct {
+		name string // slash separated path inside the zip, directories end in a slash
+		path string
+		mode os.FileMode
 	}
-	defer file.Close()
-
-	zipWriter := zip.NewWriter(file)
-	defer zipWriter.Close()
-
-	return filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
+	var entries []zipEntry
+	err := filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -290,34 +384,135 @@ func createZipFile(sourceDir, outputName string) error {
 		if err != nil {
 			return err
 		}
+		relPath = filepath.ToSlash(relPath)
+
+		switch {
+		case info.IsDir():
+			entries = append(entries, zipEntry{name: relPath + "/", mode: os.ModeDir | 0755})
+		case info.Mode()&0111 != 0:
+			// keep scripts executable
+			entries = append(entries, zipEntry{name: relPath, path: path, mode: 0755})
+		default:
+			entries = append(entries, zipEntry{name: relPath, path: path, mode: 0644})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
 
-		// Convert Windows paths to forward slashes for zip compatibility
-		relPath = strings.ReplaceAll(relPath, "\\", "/")
+	if err := os.MkdirAll(filepath.Dir(outputName), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	file, err := os.CreateTemp(filepath.Dir(outputName), "."+filepath.Base(outputName)+".*.part")
+	if err != nil {
+		return fmt.Errorf("failed to create zip file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
 
-		if info.IsDir() {
-			// Create directory entry
-			_, err := zipWriter.Create(relPath + "/")
+	zipWriter := zip.NewWriter(file)
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
 			return err
 		}
 
-		// Create file entry
-		zipFile, err := zipWriter.Create(relPath)
+		header := &zip.FileHeader{Name: entry.name, Method: zip.Deflate, Modified: zipModTime}
+		if entry.mode.IsDir() {
+			header.Method = zip.Store
+		}
+		header.SetMode(entry.mode)
+		zipFile, err := zipWriter.CreateHeader(header)
 		if err != nil {
 			return err
 		}
+		if entry.mode.IsDir() {
+			continue
+		}
 
-		srcFile, err := os.Open(path)
-		if err != nil {
+		if err := copyToZip(zipFile, entry.path); err != nil {
 			return err
 		}
-		defer srcFile.Close()
+	}
 
-		_, err = io.Copy(zipFile, srcFile)
+	if err := zipWriter.Close(); err != nil {
+		return fmt.Errorf("failed to write zip file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write zip file: %w", err)
+	}
+	if err := os.Chmod(file.Name(), 0o644); err != nil {
 		return err
-	})
+	}
+	return os.Rename(file.Name(), outputName)
+}
+
+// copyToZip copies the file at path into a zip entry
+func copyToZip(w io.Writer, path string) error {
+	srcFile, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	_, err = io.Copy(w, srcFile)
+	return err
+}
+
+// createDirExport creates a temporary directory next to outputDir to build an export in, so it
+// can be swapped in at once with replaceDirExport
+func createDirExport(outputDir string) (string, error) {
+	absOutput, err := filepath.Abs(outputDir)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(absOutput), 0755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+	tempDir, err := os.MkdirTemp(filepath.Dir(absOutput), "."+filepath.Base(absOutput)+".*.part")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	return tempDir, nil
+}
+
+// exportTempDir creates the temporary folder an archive export is built in. the folder is removed
+// once the archive is written, so the manager may hardlink cached files into it instead of copying
+func exportTempDir(manager *download.Manager) (string, error) {
+	tempDir, err := os.MkdirTemp("", "minepack-export-*")
+	if err == nil {
+		manager.LinkFiles = true
+	}
+	return tempDir, err
+}
+
+// replaceDirExport moves a finished export from tempDir to outputDir, replacing what was there
+func replaceDirExport(tempDir, outputDir string) error {
+	if err := os.Chmod(tempDir, 0755); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(outputDir); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", outputDir, err)
+	}
+	return os.Rename(tempDir, outputDir)
+}
+
+// checkDirExport refuses to replace a directory that isn't empty and doesn't contain marker,
+// a file every export of that kind has
+func checkDirExport(outputDir string, marker string, kind string) error {
+	entries, err := os.ReadDir(outputDir)
+	if err != nil || len(entries) == 0 {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, marker)); err != nil {
+		return fmt.Errorf("%s isn't empty and isn't a %s, refusing to replace it", outputDir, kind)
+	}
+	return nil
 }
 
 func init() {
 	rootCmd.AddCommand(exportCmd)
+	exportCmd.PersistentFlags().StringP("output", "o", "", "where to write the export, may use {name}, {version}, {mc} and {loader}, e.g. dist/{name}-{version}-{mc}.mrpack")
 	exportCmd.AddCommand(exportModrinthCmd)
 }
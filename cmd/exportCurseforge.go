@@ -0,0 +1,264 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"minepack/core/api"
+	"minepack/core/download"
+	"minepack/core/project"
+	"minepack/util"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// exportCurseforgeCmd represents the export curseforge command
+var exportCurseforgeCmd = &cobra.Command{
+	Use:   "curseforge",
+	Short: "export as CurseForge modpack zip",
+	Long: `exports your modpack as a CurseForge modpack zip (manifest.json, modlist.html and overrides folder)
+
+content from other sources is embedded in the overrides folder, which is only done when its license
+allows it. use --allow-embed for content you have been given permission to share anyway`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Get current working directory and parse project
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting current working directory: %s"), err)
+			return
+		}
+
+		packData, err := project.ParseProject(cwd)
+		if err != nil {
+			fmt.Printf(util.FormatError("error parsing project: %s"), err)
+			return
+		}
+
+		// Get all content
+		allContent, err := packData.GetAllContent()
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting all content: %s"), err)
+			return
+		}
+
+		allowEmbed, _ := cmd.Flags().GetStringSlice("allow-embed")
+
+		// Export as a curseforge zip
+		outputName, err := exportOutput(cmd, packData, "{name}-{version}.zip", false)
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting output path: %s"), err)
+			return
+		}
+		if err := exportCurseforgePack(cmd.Context(), newDownloadManager(cmd, cwd), packData, allContent, allowEmbed, outputName); err != nil {
+			if interrupted(cmd, "no pack was written") {
+				return
+			}
+			fmt.Printf(util.FormatError("failed to export curseforge pack: %s"), err)
+			return
+		}
+
+		successMsg := fmt.Sprintf("successfully exported to %s", outputName)
+		fmt.Println(util.FormatSuccess(successMsg))
+	},
+}
+
+// exportCurseforgePack exports the pack as a curseforge modpack zip. curseforge content is
+// listed in the manifest, everything else is downloaded into the overrides
+func exportCurseforgePack(ctx context.Context, manager *download.Manager, packData *project.Project, allContent []project.ContentData, allowEmbed []string, outputName string) error {
+	// split the content into what the manifest references and what has to be embedded
+	// custom content without a download url already lives in the overrides folder, so it is skipped
+	var referenced, embedded []project.ContentData
+	for _, content := range allContent {
+		switch {
+		case content.Source == project.Curseforge:
+			referenced = append(referenced, content)
+		case content.InOverrides():
+		default:
+			embedded = append(embedded, content)
+		}
+	}
+
+	// check every file may actually be shared before building anything
+	redistribution, err := api.CanRedistribute(ctx, append(slices.Clone(referenced), embedded...))
+	if err != nil {
+		return fmt.Errorf("failed to check licenses: %w", err)
+	}
+	var blocked []string
+	for _, content := range embedded {
+		r := redistribution[content.Slug]
+		if !r.Allowed && !slices.Contains(allowEmbed, content.Slug) {
+			blocked = append(blocked, fmt.Sprintf("  - %s (%s, %s)", content.Slug, project.SourceToString(content.Source), r.Reason))
+		}
+	}
+	if len(blocked) > 0 {
+		return fmt.Errorf("%d mods can't be embedded in the pack, remove them or pass --allow-embed <slug> if you have permission to share them:\n%s", len(blocked), strings.Join(blocked, "\n"))
+	}
+
+	// curseforge mods whose authors turned off third party distribution are still referenced,
+	// only the curseforge app itself will download them
+	var appOnly []string
+	for _, content := range referenced {
+		if !redistribution[content.Slug].Allowed {
+			appOnly = append(appOnly, content.Slug)
+		}
+	}
+	if len(appOnly) > 0 {
+		fmt.Printf(util.FormatWarning("warning: %d mods can only be downloaded by the curseforge app, other launchers will ask for them to be downloaded by hand: %s\n"), len(appOnly), strings.Join(appOnly, ", "))
+	}
+
+	// Create temporary directory for building the pack
+	tempDir, err := exportTempDir(manager)
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Copy the client's overrides, curseforge packs have a single overrides folder
+	if err := copyOverridesExport(packData.Root, filepath.Join(tempDir, "overrides"), "client"); err != nil {
+		return fmt.Errorf("failed to copy overrides: %w", err)
+	}
+
+	// Create manifest.json
+	manifest, err := createCurseforgeManifest(packData, referenced)
+	if err != nil {
+		return err
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal curseforge manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "manifest.json"), manifestData, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest.json: %w", err)
+	}
+
+	// Create modlist.html
+	if err := os.WriteFile(filepath.Join(tempDir, "modlist.html"), []byte(createCurseforgeModlist(allContent)), 0644); err != nil {
+		return fmt.Errorf("failed to write modlist.html: %w", err)
+	}
+
+	// Download embedded content to overrides
+	var jobs []download.Job
+	for _, content := range embedded {
+		destPath := filepath.Join(tempDir, "overrides", getContentPath(content))
+		jobs = append(jobs, api.DownloadJob(content, destPath))
+	}
+	results, err := manager.Run(ctx, jobs)
+	if err != nil {
+		return fmt.Errorf("download cancelled: %w", err)
+	}
+	recordComputedHashes(ctx, packData, embedded, results)
+	if failed := download.Failed(results); len(failed) > 0 {
+		return fmt.Errorf("failed to download %s: %w", failed[0].Job.Name, failed[0].Err)
+	}
+
+	// Create the zip file
+	return createZipFile(ctx, tempDir, outputName)
+}
+
+// curseforgeManifest is the manifest.json at the root of a curseforge modpack zip
+type curseforgeManifest struct {
+	Minecraft       curseforgeMinecraft `json:"minecraft"`
+	ManifestType    string              `json:"manifestType"`
+	ManifestVersion int                 `json:"manifestVersion"`
+	Name            string              `json:"name"`
+	Version         string              `json:"version"`
+	Author          string              `json:"author"`
+	Files           []curseforgeFile    `json:"files"`
+	Overrides       string              `json:"overrides"`
+}
+
+type curseforgeMinecraft struct {
+	Version    string                `json:"version"`
+	ModLoaders []curseforgeModLoader `json:"modLoaders"`
+}
+
+type curseforgeModLoader struct {
+	ID      string `json:"id"` // loader name and version, e.g. "fabric-0.16.5"
+	Primary bool   `json:"primary"`
+}
+
+type curseforgeFile struct {
+	ProjectID int  `json:"projectID"`
+	FileID    int  `json:"fileID"`
+	Required  bool `json:"required"`
+}
+
+// createCurseforgeManifest creates the manifest.json structure, referencing curseforge content
+// by project and file id
+func createCurseforgeManifest(packData *project.Project, referenced []project.ContentData) (*curseforgeManifest, error) {
+	modLoaders := []curseforgeModLoader{}
+	switch loader := packData.Versions.Loader; loader.Name {
+	case "fabric", "quilt", "forge", "neoforge":
+		if loader.Version == "" {
+			return nil, fmt.Errorf("the pack doesn't set a %s version, which curseforge requires", loader.Name)
+		}
+		modLoaders = append(modLoaders, curseforgeModLoader{
+			ID:      fmt.Sprintf("%s-%s", loader.Name, loader.Version),
+			Primary: true,
+		})
+	}
+
+	files := []curseforgeFile{}
+	for _, content := range referenced {
+		projectID, err := strconv.Atoi(content.Id)
+		if err != nil {
+			return nil, fmt.Errorf("%s has an invalid curseforge project id %q", content.Slug, content.Id)
+		}
+		fileID, err := strconv.Atoi(content.VersionId)
+		if err != nil {
+			return nil, fmt.Errorf("%s has an invalid curseforge file id %q", content.Slug, content.VersionId)
+		}
+		files = append(files, curseforgeFile{ProjectID: projectID, FileID: fileID, Required: true})
+	}
+	slices.SortFunc(files, func(a, b curseforgeFile) int { return a.ProjectID - b.ProjectID })
+
+	version, err := packVersion(packData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &curseforgeManifest{
+		Minecraft: curseforgeMinecraft{
+			Version:    packData.Versions.Game,
+			ModLoaders: modLoaders,
+		},
+		ManifestType:    "minecraftModpack",
+		ManifestVersion: 1,
+		Name:            packData.Name,
+		Version:         version,
+		Author:          packData.Author,
+		Files:           files,
+		Overrides:       "overrides",
+	}, nil
+}
+
+// createCurseforgeModlist creates the modlist.html curseforge shows on the pack's page, linking
+// every piece of content
+func createCurseforgeModlist(allContent []project.ContentData) string {
+	var b strings.Builder
+	b.WriteString("<ul>\n")
+	for _, content := range allContent {
+		name := html.EscapeString(content.Name)
+		if content.PageUrl != "" {
+			fmt.Fprintf(&b, "<li><a href=\"%s\">%s</a></li>\n", html.EscapeString(content.PageUrl), name)
+		} else {
+			fmt.Fprintf(&b, "<li>%s</li>\n", name)
+		}
+	}
+	b.WriteString("</ul>\n")
+	return b.String()
+}
+
+func init() {
+	exportCmd.AddCommand(exportCurseforgeCmd)
+	exportCurseforgeCmd.Flags().StringSlice("allow-embed", nil, "slugs of content to embed even though its license doesn't allow it, for when you have permission")
+}
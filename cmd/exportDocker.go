@@ -0,0 +1,272 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"minepack/core/api"
+	"minepack/core/project"
+	"minepack/util"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// dockerLoader is how itzg/minecraft-server is told to run a loader
+type dockerLoader struct {
+	Type       string // value of TYPE
+	VersionVar string // variable pinning the loader version, empty for vanilla
+}
+
+var dockerLoaders = map[string]dockerLoader{
+	"":         {"VANILLA", ""},
+	"vanilla":  {"VANILLA", ""},
+	"fabric":   {"FABRIC", "FABRIC_LOADER_VERSION"},
+	"quilt":    {"QUILT", "QUILT_LOADER_VERSION"},
+	"forge":    {"FORGE", "FORGE_VERSION"},
+	"neoforge": {"NEOFORGE", "NEOFORGE_VERSION"},
+}
+
+// exportDockerCmd represents the export docker command
+var exportDockerCmd = &cobra.Command{
+	Use:   "docker [dir]",
+	Short: "export a docker compose setup for itzg/minecraft-server",
+	Long: `exports a docker-compose.yml and .env that run the pack with the itzg/minecraft-server image,
+in [dir] (default <pack name>-docker)
+
+modrinth and curseforge mods are pinned in the .env and downloaded by the container, custom jars are
+copied into a mounted mods folder. the server's data folder is kept when exporting again`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Get current working directory and parse project
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting current working directory: %s"), err)
+			return
+		}
+
+		packData, err := project.ParseProject(cwd)
+		if err != nil {
+			fmt.Printf(util.FormatError("error parsing project: %s"), err)
+			return
+		}
+
+		// Get all content
+		allContent, err := packData.GetAllContent()
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting all content: %s"), err)
+			return
+		}
+
+		outputDir, err := exportOutput(cmd, packData, "{name}-docker", true)
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting output path: %s"), err)
+			return
+		}
+		if len(args) > 0 {
+			outputDir = args[0]
+		}
+		if err := exportDockerSetup(cmd.Context(), packData, allContent, outputDir); err != nil {
+			if interrupted(cmd, "nothing was written") {
+				return
+			}
+			fmt.Printf(util.FormatError("failed to export docker setup: %s"), err)
+			return
+		}
+
+		successMsg := fmt.Sprintf("successfully exported to %s, start it with docker compose up", outputDir)
+		fmt.Println(util.FormatSuccess(successMsg))
+	},
+}
+
+// exportDockerSetup writes the compose setup to outputDir. it is built next to outputDir and
+// swapped in once complete, moving the server's data folder over
+func exportDockerSetup(ctx context.Context, packData *project.Project, allContent []project.ContentData, outputDir string) error {
+	if err := checkDirExport(outputDir, "docker-compose.yml", "docker setup"); err != nil {
+		return err
+	}
+	loader, ok := dockerLoaders[packData.Versions.Loader.Name]
+	if !ok {
+		return fmt.Errorf("itzg/minecraft-server doesn't support %s", packData.Versions.Loader.Name)
+	}
+
+	tempDir, err := createDirExport(outputDir)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Copy server overrides, config and mods get their own folders which the image copies into
+	// the server, everything else is mounted straight into it. the server's own overrides are
+	// copied last so they replace shared ones
+	var mounted []string
+	for _, folder := range project.OverrideFolders("server") {
+		overridesPath := filepath.Join(packData.Root, folder)
+		entries, err := os.ReadDir(overridesPath)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if folder == project.OverridesFolder && isClientOnlyOverride(name) {
+				continue
+			}
+			dst := filepath.Join(tempDir, "overrides", name)
+			switch name {
+			case "config", "mods":
+				dst = filepath.Join(tempDir, name)
+			default:
+				if !slices.Contains(mounted, name) {
+					mounted = append(mounted, name)
+				}
+			}
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				return fmt.Errorf("failed to copy %s: %w", folder, err)
+			}
+			copyOverride := copyServerOverrides
+			if folder != project.OverridesFolder {
+				copyOverride = copyDirExport
+			}
+			if err := copyOverride(filepath.Join(overridesPath, name), dst); err != nil {
+				return fmt.Errorf("failed to copy %s: %w", folder, err)
+			}
+		}
+	}
+	for _, dir := range []string{"config", "mods"} {
+		if err := os.MkdirAll(filepath.Join(tempDir, dir), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+	}
+
+	// Sort server content into what the image downloads itself
+	var modrinthProjects, curseforgeFiles, modURLs []string
+	for _, content := range serverContent(allContent) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		switch {
+		case content.Source == project.Modrinth:
+			// ids, since a project's slug can change while its id can't
+			modrinthProjects = append(modrinthProjects, content.Id+":"+content.VersionId)
+		case content.Source == project.Curseforge:
+			// ids, since the image resolves curseforge slugs by searching
+			curseforgeFiles = append(curseforgeFiles, content.Id+":"+content.VersionId)
+		case content.InOverrides():
+			// custom jars without a download url live in the pack, copy any that aren't already part of the overrides
+			if content.File.Filepath == "" {
+				continue
+			}
+			src := overrideSource(packData.Root, "server", content.File.Filepath)
+			dst := filepath.Join(tempDir, "mods", content.File.Filename)
+			if _, err := os.Stat(dst); err == nil {
+				continue
+			}
+			if err := copyFile(src, dst); err != nil {
+				return fmt.Errorf("failed to copy %s: %w", content.Name, err)
+			}
+		default:
+			job := api.DownloadJob(content, "")
+			if job.URL == "" {
+				return fmt.Errorf("%s has no download url", content.Name)
+			}
+			modURLs = append(modURLs, job.URL)
+		}
+	}
+
+	// Create .env and docker-compose.yml
+	env := createDockerEnv(packData, loader, modrinthProjects, curseforgeFiles, modURLs)
+	if err := os.WriteFile(filepath.Join(tempDir, ".env"), []byte(env), 0644); err != nil {
+		return fmt.Errorf("failed to write .env: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "docker-compose.yml"), []byte(createDockerCompose(mounted)), 0644); err != nil {
+		return fmt.Errorf("failed to write docker-compose.yml: %w", err)
+	}
+
+	// keep the world and everything else the server wrote
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	dataDir := filepath.Join(outputDir, "data")
+	keptData := filepath.Join(tempDir, "data")
+	if _, err := os.Stat(dataDir); err == nil {
+		if err := os.Rename(dataDir, keptData); err != nil {
+			return fmt.Errorf("failed to keep the server's data folder: %w", err)
+		}
+	}
+	if err := replaceDirExport(tempDir, outputDir); err != nil {
+		// put the data folder back instead of losing it with the temp directory
+		if _, statErr := os.Stat(keptData); statErr == nil {
+			_ = os.MkdirAll(outputDir, 0755)
+			_ = os.Rename(keptData, dataDir)
+		}
+		return err
+	}
+	return nil
+}
+
+// createDockerEnv creates the .env passed to the container
+func createDockerEnv(packData *project.Project, loader dockerLoader, modrinthProjects, curseforgeFiles, modURLs []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# itzg/minecraft-server settings for %s, exported by minepack\n\n", packData.Name)
+	b.WriteString("# read the minecraft eula (https://aka.ms/MinecraftEULA) and set EULA=TRUE to start the server\n")
+	b.WriteString("EULA=FALSE\n")
+	b.WriteString("MEMORY=4G\n\n")
+
+	fmt.Fprintf(&b, "TYPE=%s\n", loader.Type)
+	fmt.Fprintf(&b, "VERSION=%s\n", packData.Versions.Game)
+	if loader.VersionVar != "" {
+		fmt.Fprintf(&b, "%s=%s\n", loader.VersionVar, packData.Versions.Loader.Version)
+	}
+
+	if len(modrinthProjects) > 0 {
+		b.WriteString("\n# modrinth mods, pinned to the versions in the pack\n")
+		fmt.Fprintf(&b, "MODRINTH_PROJECTS=%s\n", strings.Join(modrinthProjects, ","))
+		b.WriteString("MODRINTH_DOWNLOAD_DEPENDENCIES=none\n")
+	}
+	if len(curseforgeFiles) > 0 {
+		b.WriteString("\n# curseforge mods, pinned to the files in the pack. downloading them needs an api key from\n")
+		b.WriteString("# https://console.curseforge.com, with every $ in it written as $$\n")
+		b.WriteString("CF_API_KEY=\n")
+		fmt.Fprintf(&b, "CURSEFORGE_FILES=%s\n", strings.Join(curseforgeFiles, ","))
+	}
+	if len(modURLs) > 0 {
+		b.WriteString("\n# mods from other sources, downloaded from their release urls\n")
+		fmt.Fprintf(&b, "MODS=%s\n", strings.Join(modURLs, ","))
+	}
+	return b.String()
+}
+
+// createDockerCompose creates docker-compose.yml, mounting the overrides the image doesn't copy itself
+func createDockerCompose(mounted []string) string {
+	sort.Strings(mounted)
+
+	var b strings.Builder
+	b.WriteString("services:\n")
+	b.WriteString("  minecraft:\n")
+	b.WriteString("    image: itzg/minecraft-server\n")
+	b.WriteString("    tty: true\n")
+	b.WriteString("    stdin_open: true\n")
+	b.WriteString("    ports:\n")
+	b.WriteString("      - \"25565:25565\"\n")
+	b.WriteString("    env_file: .env\n")
+	b.WriteString("    volumes:\n")
+	b.WriteString("      - ./data:/data\n")
+	b.WriteString("      - ./mods:/mods:ro\n")
+	b.WriteString("      - ./config:/config:ro\n")
+	for _, name := range mounted {
+		volume, _ := json.Marshal(fmt.Sprintf("./overrides/%s:/data/%s", name, name))
+		fmt.Fprintf(&b, "      - %s\n", volume)
+	}
+	return b.String()
+}
+
+func init() {
+	exportCmd.AddCommand(exportDockerCmd)
+}
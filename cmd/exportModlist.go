@@ -0,0 +1,392 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"minepack/core/api"
+	"minepack/core/project"
+	"minepack/util"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+// exportModlistCmd represents the export modlist command
+var exportModlistCmd = &cobra.Command{
+	Use:   "modlist",
+	Short: "export a list of the pack's content",
+	Long: `exports a list of everything in the pack with its version, side, source and license, for changelogs,
+release pages and wikis
+
+--format picks markdown, html, csv or json and --group splits the list by content type, tag or side.
+--template renders the list with your own go text/template instead, which is given the same data as
+the json format`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Get current working directory and parse project
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting current working directory: %s"), err)
+			return
+		}
+
+		packData, err := project.ParseProject(cwd)
+		if err != nil {
+			fmt.Printf(util.FormatError("error parsing project: %s"), err)
+			return
+		}
+
+		// Get all content
+		allContent, err := packData.GetAllContent()
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting all content: %s"), err)
+			return
+		}
+
+		format, _ := cmd.Flags().GetString("format")
+		group, _ := cmd.Flags().GetString("group")
+		templatePath, _ := cmd.Flags().GetString("template")
+
+		ext, ok := modlistExtensions[format]
+		if !ok {
+			fmt.Printf(util.FormatError("unknown format %s, expected md, html, csv or json\n"), format)
+			return
+		}
+		if !slices.Contains(modlistGroupings, group) {
+			fmt.Printf(util.FormatError("unknown grouping %s, expected %s\n"), group, strings.Join(modlistGroupings, ", "))
+			return
+		}
+		if templatePath != "" {
+			ext = modlistTemplateExtension(templatePath)
+		}
+
+		outputName, err := exportOutput(cmd, packData, "{name}-{version}-modlist"+ext, false)
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting output path: %s"), err)
+			return
+		}
+		if err := exportModlist(cmd.Context(), packData, allContent, format, group, templatePath, outputName); err != nil {
+			if interrupted(cmd, "no modlist was written") {
+				return
+			}
+			fmt.Printf(util.FormatError("failed to export modlist: %s"), err)
+			return
+		}
+
+		successMsg := fmt.Sprintf("successfully exported to %s", outputName)
+		fmt.Println(util.FormatSuccess(successMsg))
+	},
+}
+
+// modlistExtensions maps each built in format to the extension of its file
+var modlistExtensions = map[string]string{
+	"md":   ".md",
+	"html": ".html",
+	"csv":  ".csv",
+	"json": ".json",
+}
+
+var modlistGroupings = []string{"none", "type", "tag", "side"}
+
+// modlist is the data every modlist format is rendered from, and what custom templates are given
+type modlist struct {
+	Name      string         `json:"name"`
+	Version   string         `json:"version"`
+	Author    string         `json:"author"`
+	Minecraft string         `json:"minecraft"`
+	Loader    string         `json:"loader"`
+	Mods      []modlistEntry `json:"mods"`
+	Groups    []modlistGroup `json:"groups,omitempty"` // a single unnamed group when the list isn't grouped
+}
+
+type modlistGroup struct {
+	Name string         `json:"name"`
+	Mods []modlistEntry `json:"mods"`
+}
+
+type modlistEntry struct {
+	Name              string   `json:"name"`
+	Slug              string   `json:"slug"`
+	Url               string   `json:"url,omitempty"`
+	Version           string   `json:"version"`
+	Type              string   `json:"type"`
+	Side              string   `json:"side"`
+	Source            string   `json:"source"`
+	License           string   `json:"license,omitempty"`
+	Tags              []string `json:"tags"`
+	AddedAsDependency bool     `json:"added_as_dependency"`
+}
+
+// exportModlist writes the pack's content list to outputName in the given format, or with the
+// template at templatePath when it is set
+func exportModlist(ctx context.Context, packData *project.Project, allContent []project.ContentData, format, group, templatePath, outputName string) error {
+	// the list is still useful without licenses and tags, so don't fail when some can't be looked up
+	details, err := api.Describe(ctx, allContent)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		fmt.Printf(util.FormatWarning("warning: couldn't look up licenses and tags of some content, they are left out: %s\n"), err)
+	}
+
+	version, err := packVersion(packData)
+	if err != nil {
+		return err
+	}
+	list := createModlist(packData, version, allContent, details, group)
+
+	var data []byte
+	if templatePath != "" {
+		data, err = renderModlistTemplate(list, templatePath)
+	} else {
+		data, err = renderModlist(list, format, group != "none")
+	}
+	if err != nil {
+		return err
+	}
+	if err := writeExportFile(outputName, data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputName, err)
+	}
+	return nil
+}
+
+// createModlist builds the list, sorted by name and split into groups
+func createModlist(packData *project.Project, version string, allContent []project.ContentData, details map[string]api.Details, group string) modlist {
+	list := modlist{
+		Name:      packData.Name,
+		Version:   version,
+		Author:    packData.Author,
+		Minecraft: packData.Versions.Game,
+		Loader:    strings.TrimSpace(packData.Versions.Loader.Name + " " + packData.Versions.Loader.Version),
+		Mods:      []modlistEntry{},
+	}
+
+	for _, content := range allContent {
+		d := details[content.Slug]
+		contentVersion := d.Version
+		if contentVersion == "" {
+			contentVersion = content.VersionId
+		}
+		tags := d.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		list.Mods = append(list.Mods, modlistEntry{
+			Name:              content.Name,
+			Slug:              content.Slug,
+			Url:               content.PageUrl,
+			Version:           contentVersion,
+			Type:              project.ContentTypeToString(content.ContentType),
+			Side:              sideName(content.Side),
+			Source:            project.SourceToString(content.Source),
+			License:           d.License,
+			Tags:              tags,
+			AddedAsDependency: content.AddedAsDependency,
+		})
+	}
+	sort.SliceStable(list.Mods, func(i, j int) bool {
+		return strings.ToLower(list.Mods[i].Name) < strings.ToLower(list.Mods[j].Name)
+	})
+
+	list.Groups = groupModlist(list.Mods, group)
+	return list
+}
+
+// groupModlist splits entries into named groups, keeping them sorted by name within each group
+func groupModlist(entries []modlistEntry, group string) []modlistGroup {
+	var keys func(entry modlistEntry) []string
+	var order []string
+	switch group {
+	case "type":
+		keys = func(entry modlistEntry) []string { return []string{entry.Type + "s"} }
+		order = []string{"mods", "resourcepacks", "shaderpacks", "datapacks", "worlds"}
+	case "side":
+		keys = func(entry modlistEntry) []string { return []string{entry.Side} }
+		order = []string{"both", "client", "server"}
+	case "tag":
+		// content is listed under each of its tags
+		keys = func(entry modlistEntry) []string {
+			if len(entry.Tags) == 0 {
+				return []string{"untagged"}
+			}
+			return entry.Tags
+		}
+	default:
+		return []modlistGroup{{Mods: entries}}
+	}
+
+	grouped := map[string][]modlistEntry{}
+	for _, entry := range entries {
+		for _, key := range keys(entry) {
+			grouped[key] = append(grouped[key], entry)
+		}
+	}
+
+	// groups without a fixed order are sorted by name, with untagged content last
+	var names []string
+	for name := range grouped {
+		if !slices.Contains(order, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == "untagged") != (names[j] == "untagged") {
+			return names[j] == "untagged"
+		}
+		return names[i] < names[j]
+	})
+
+	var groups []modlistGroup
+	for _, name := range append(order, names...) {
+		if mods, ok := grouped[name]; ok {
+			groups = append(groups, modlistGroup{Name: name, Mods: mods})
+		}
+	}
+	return groups
+}
+
+// renderModlist renders the list in one of the built in formats
+func renderModlist(list modlist, format string, grouped bool) ([]byte, error) {
+	var b bytes.Buffer
+	switch format {
+	case "md":
+		if err := modlistMarkdown.Execute(&b, list); err != nil {
+			return nil, fmt.Errorf("failed to render markdown: %w", err)
+		}
+	case "html":
+		if err := modlistHtml.Execute(&b, list); err != nil {
+			return nil, fmt.Errorf("failed to render html: %w", err)
+		}
+	case "csv":
+		w := csv.NewWriter(&b)
+		header := []string{"name", "slug", "version", "type", "side", "source", "license", "tags", "added_as_dependency", "url"}
+		if grouped {
+			header = append([]string{"group"}, header...)
+		}
+		_ = w.Write(header)
+		for _, group := range list.Groups {
+			for _, entry := range group.Mods {
+				record := []string{entry.Name, entry.Slug, entry.Version, entry.Type, entry.Side, entry.Source, entry.License, strings.Join(entry.Tags, ";"), strconv.FormatBool(entry.AddedAsDependency), entry.Url}
+				if grouped {
+					record = append([]string{group.Name}, record...)
+				}
+				_ = w.Write(record)
+			}
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return nil, fmt.Errorf("failed to write csv: %w", err)
+		}
+	case "json":
+		if !grouped {
+			list.Groups = nil
+		}
+		data, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal modlist: %w", err)
+		}
+		b.Write(append(data, '\n'))
+	}
+	return b.Bytes(), nil
+}
+
+// renderModlistTemplate renders the list with a user's text/template
+func renderModlistTemplate(list modlist, templatePath string) ([]byte, error) {
+	text, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	tmpl, err := template.New(filepath.Base(templatePath)).Funcs(modlistFuncs).Parse(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, list); err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+	return b.Bytes(), nil
+}
+
+// modlistTemplateExtension is the extension of the file a template renders, e.g. ".txt" for "list.txt.tmpl"
+func modlistTemplateExtension(templatePath string) string {
+	name := filepath.Base(templatePath)
+	for _, suffix := range []string{".tmpl", ".tpl", ".gotmpl"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	if ext := filepath.Ext(name); ext != "" {
+		return ext
+	}
+	return ".txt"
+}
+
+// modlistFuncs are the helpers available to the built in templates and custom ones
+var modlistFuncs = template.FuncMap{
+	"join": strings.Join,
+	"title": func(s string) string {
+		if s == "" {
+			return s
+		}
+		return strings.ToUpper(s[:1]) + s[1:]
+	},
+	// md escapes text for a markdown table cell
+	"md": func(s string) string {
+		return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+	},
+}
+
+var modlistMarkdown = template.Must(template.New("md").Funcs(modlistFuncs).Parse(`# {{ md .Name }}{{ if .Version }} {{ md .Version }}{{ end }}
+
+Minecraft {{ .Minecraft }}{{ if .Loader }}, {{ md .Loader }}{{ end }}
+{{ range .Groups }}
+{{ if .Name }}## {{ title .Name }}
+
+{{ end -}}
+| Name | Version | Side | Source | License | Tags | Dependency |
+| --- | --- | --- | --- | --- | --- | --- |
+{{ range .Mods -}}
+| {{ if .Url }}[{{ md .Name }}]({{ .Url }}){{ else }}{{ md .Name }}{{ end }} | {{ md .Version }} | {{ .Side }} | {{ .Source }} | {{ if .License }}{{ md .License }}{{ else }}unknown{{ end }} | {{ md (join .Tags ", ") }} | {{ if .AddedAsDependency }}yes{{ end }} |
+{{ end -}}
+{{ end -}}
+`))
+
+var modlistHtml = htmltemplate.Must(htmltemplate.New("html").Funcs(htmltemplate.FuncMap(modlistFuncs)).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Name }}{{ if .Version }} {{ .Version }}{{ end }}</title>
+</head>
+<body>
+<h1>{{ .Name }}{{ if .Version }} {{ .Version }}{{ end }}</h1>
+<p>Minecraft {{ .Minecraft }}{{ if .Loader }}, {{ .Loader }}{{ end }}</p>
+{{ range .Groups -}}
+{{ if .Name }}<h2>{{ title .Name }}</h2>
+{{ end -}}
+<table>
+<tr><th>Name</th><th>Version</th><th>Side</th><th>Source</th><th>License</th><th>Tags</th><th>Dependency</th></tr>
+{{ range .Mods -}}
+<tr><td>{{ if .Url }}<a href="{{ .Url }}">{{ .Name }}</a>{{ else }}{{ .Name }}{{ end }}</td><td>{{ .Version }}</td><td>{{ .Side }}</td><td>{{ .Source }}</td><td>{{ if .License }}{{ .License }}{{ else }}unknown{{ end }}</td><td>{{ join .Tags ", " }}</td><td>{{ if .AddedAsDependency }}yes{{ end }}</td></tr>
+{{ end -}}
+</table>
+{{ end -}}
+</body>
+</html>
+`))
+
+func init() {
+	exportCmd.AddCommand(exportModlistCmd)
+	exportModlistCmd.Flags().String("format", "md", "format of the list: md, html, csv or json")
+	exportModlistCmd.Flags().String("group", "none", "split the list by: none, type, tag or side")
+	exportModlistCmd.Flags().String("template", "", "render the list with this go text/template instead of a built in format")
+}
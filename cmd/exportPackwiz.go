@@ -0,0 +1,404 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"minepack/core/api"
+	"minepack/core/download"
+	"minepack/core/project"
+	"minepack/util"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// packwizFormat is the pack format version written to pack.toml
+const packwizFormat = "packwiz:1.1.0"
+
+// exportPackwizCmd represents the export packwiz command
+var exportPackwizCmd = &cobra.Command{
+	Use:   "packwiz [dir]",
+	Short: "export as a packwiz pack",
+	Long: `exports your modpack as a packwiz pack in [dir] (default <pack name>-packwiz) with pack.toml,
+index.toml and a .pw.toml file per mod, ready to be served to packwiz-installer. overrides are copied
+into the pack as plain files
+
+an existing packwiz pack in [dir] is replaced`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Get current working directory and parse project
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting current working directory: %s"), err)
+			return
+		}
+
+		packData, err := project.ParseProject(cwd)
+		if err != nil {
+			fmt.Printf(util.FormatError("error parsing project: %s"), err)
+			return
+		}
+
+		// Get all content
+		allContent, err := packData.GetAllContent()
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting all content: %s"), err)
+			return
+		}
+
+		outputDir, err := exportOutput(cmd, packData, "{name}-packwiz", true)
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting output path: %s"), err)
+			return
+		}
+		if len(args) > 0 {
+			outputDir = args[0]
+		}
+		if err := exportPackwizPack(cmd.Context(), newDownloadManager(cmd, cwd), packData, allContent, outputDir); err != nil {
+			if interrupted(cmd, "no pack was written") {
+				return
+			}
+			fmt.Printf(util.FormatError("failed to export packwiz pack: %s"), err)
+			return
+		}
+
+		successMsg := fmt.Sprintf("successfully exported to %s", outputDir)
+		fmt.Println(util.FormatSuccess(successMsg))
+	},
+}
+
+// exportPackwizPack writes the pack to outputDir in packwiz format. it is built next to
+// outputDir and only swapped in once complete
+func exportPackwizPack(ctx context.Context, manager *download.Manager, packData *project.Project, allContent []project.ContentData, outputDir string) error {
+	if err := checkDirExport(outputDir, "pack.toml", "packwiz pack"); err != nil {
+		return err
+	}
+
+	// packwiz needs a hash for every file, so content added without one is downloaded to work it out
+	// custom content without a download url already lives in the overrides folder, so it is skipped
+	var jobs []download.Job
+	var jobContents []project.ContentData
+	scratchDir, err := exportTempDir(manager)
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(scratchDir)
+
+	for _, content := range allContent {
+		if content.InOverrides() {
+			continue
+		}
+		if _, hash := packwizHash(content.File.Hashes); hash == "" {
+			jobs = append(jobs, api.DownloadJob(content, filepath.Join(scratchDir, getContentPath(content))))
+			jobContents = append(jobContents, content)
+		}
+	}
+	if len(jobs) > 0 {
+		results, err := manager.Run(ctx, jobs)
+		if err != nil {
+			return fmt.Errorf("download cancelled: %w", err)
+		}
+		recordComputedHashes(ctx, packData, jobContents, results)
+		if failed := download.Failed(results); len(failed) > 0 {
+			return fmt.Errorf("failed to download %s: %w", failed[0].Job.Name, failed[0].Err)
+		}
+		hashed := make(map[string]project.Hashes)
+		for i, result := range results {
+			hashed[jobContents[i].Slug] = result.Hashes
+		}
+		for i, content := range allContent {
+			if hashes, ok := hashed[content.Slug]; ok {
+				allContent[i].File.Hashes = hashes
+			}
+		}
+	}
+
+	// Create the pack next to the output, so it can be swapped in at once
+	tempDir, err := createDirExport(outputDir)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Copy overrides to the root of the pack, packwiz has no separate overrides folder and only
+	// metafiles can be limited to a side
+	if err := copyOverridesExport(packData.Root, tempDir, ""); err != nil {
+		return fmt.Errorf("failed to copy overrides: %w", err)
+	}
+	for _, folder := range []string{project.ClientOverridesFolder, project.ServerOverridesFolder} {
+		if hasOverrideFiles(filepath.Join(packData.Root, folder)) {
+			fmt.Printf(util.FormatWarning("warning: packwiz packs can't have overrides for one side, %s is left out\n"), folder)
+		}
+	}
+
+	// Create a metafile for each piece of content
+	metafiles := make(map[string]bool)
+	for _, content := range allContent {
+		if content.InOverrides() {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		metafile := path.Join(filepath.ToSlash(filepath.Dir(getContentPath(content))), content.Slug+".pw.toml")
+		data, err := createPackwizMetafile(content)
+		if err != nil {
+			return err
+		}
+		if err := writeExportFile(filepath.Join(tempDir, filepath.FromSlash(metafile)), data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", metafile, err)
+		}
+		metafiles[metafile] = true
+	}
+
+	// Index every file in the pack
+	index, err := createPackwizIndex(tempDir, metafiles)
+	if err != nil {
+		return fmt.Errorf("failed to index pack: %w", err)
+	}
+	if err := writeExportFile(filepath.Join(tempDir, "index.toml"), index); err != nil {
+		return fmt.Errorf("failed to write index.toml: %w", err)
+	}
+
+	// Create pack.toml
+	version, err := packVersion(packData)
+	if err != nil {
+		return err
+	}
+	if err := writeExportFile(filepath.Join(tempDir, "pack.toml"), createPackwizPack(packData, version, index)); err != nil {
+		return fmt.Errorf("failed to write pack.toml: %w", err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return replaceDirExport(tempDir, outputDir)
+}
+
+// writeExportFile writes a file, creating its parent directories
+func writeExportFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// packwizHash picks the strongest hash packwiz understands, returning its format and value
+func packwizHash(hashes project.Hashes) (string, string) {
+	switch {
+	case hashes.Sha512 != "":
+		return "sha512", hashes.Sha512
+	case hashes.Sha256 != "":
+		return "sha256", hashes.Sha256
+	case hashes.Sha1 != "":
+		return "sha1", hashes.Sha1
+	case hashes.Md5 != "":
+		return "md5", hashes.Md5
+	default:
+		return "", ""
+	}
+}
+
+// createPackwizMetafile creates the .pw.toml file describing a piece of content. the layout
+// matches what packwiz writes itself, so refreshing the pack doesn't change it
+func createPackwizMetafile(content project.ContentData) ([]byte, error) {
+	hashFormat, hash := packwizHash(content.File.Hashes)
+	if hash == "" {
+		return nil, fmt.Errorf("%s has no file hash", content.Slug)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "name = %s\n", tomlString(content.Name))
+	fmt.Fprintf(&b, "filename = %s\n", tomlString(content.File.Filename))
+	fmt.Fprintf(&b, "side = %s\n", tomlString(sideName(content.Side)))
+
+	b.WriteString("\n[download]\n")
+	if content.Source != project.Curseforge {
+		if content.DownloadUrl == "" {
+			return nil, fmt.Errorf("%s has no download url", content.Slug)
+		}
+		fmt.Fprintf(&b, "url = %s\n", tomlString(content.DownloadUrl))
+	}
+	fmt.Fprintf(&b, "hash-format = %s\n", tomlString(hashFormat))
+	fmt.Fprintf(&b, "hash = %s\n", tomlString(hash))
+
+	switch content.Source {
+	case project.Modrinth:
+		b.WriteString("\n[update]\n[update.modrinth]\n")
+		fmt.Fprintf(&b, "mod-id = %s\n", tomlString(content.Id))
+		fmt.Fprintf(&b, "version = %s\n", tomlString(content.VersionId))
+	case project.Curseforge:
+		// curseforge files are fetched through the API by packwiz-installer
+		if _, err := strconv.Atoi(content.Id); err != nil {
+			return nil, fmt.Errorf("%s has an invalid curseforge project id %q", content.Slug, content.Id)
+		}
+		if _, err := strconv.Atoi(content.VersionId); err != nil {
+			return nil, fmt.Errorf("%s has an invalid curseforge file id %q", content.Slug, content.VersionId)
+		}
+		b.WriteString("mode = \"metadata:curseforge\"\n")
+		b.WriteString("\n[update]\n[update.curseforge]\n")
+		fmt.Fprintf(&b, "file-id = %s\n", content.VersionId)
+		fmt.Fprintf(&b, "project-id = %s\n", content.Id)
+	}
+	return []byte(b.String()), nil
+}
+
+// createPackwizIndex hashes every file in the pack, sorted by path like packwiz does
+func createPackwizIndex(packDir string, metafiles map[string]bool) ([]byte, error) {
+	var files []string
+	err := filepath.Walk(packDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(packDir, p)
+		if err != nil {
+			return err
+		}
+		if rel = filepath.ToSlash(rel); !packwizIgnored(rel) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var b strings.Builder
+	b.WriteString("hash-format = \"sha256\"\n")
+	for _, file := range files {
+		hash, err := sha256File(filepath.Join(packDir, filepath.FromSlash(file)))
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString("\n[[files]]\n")
+		fmt.Fprintf(&b, "file = %s\n", tomlString(file))
+		fmt.Fprintf(&b, "hash = %s\n", tomlString(hash))
+		if metafiles[file] {
+			b.WriteString("metafile = true\n")
+		}
+	}
+	return []byte(b.String()), nil
+}
+
+// packwizIgnored reports whether packwiz leaves a file out of the index by default
+func packwizIgnored(rel string) bool {
+	first, _, nested := strings.Cut(rel, "/")
+	base := path.Base(rel)
+	switch {
+	case rel == "pack.toml", rel == "index.toml", rel == ".packwizignore":
+		return true
+	case first == ".git", first == "packwiz" && nested, rel == "packwiz", rel == "packwiz.exe":
+		return true
+	case base == ".gitattributes", base == ".gitignore", base == ".DS_Store":
+		return true
+	case !nested && strings.HasSuffix(rel, ".zip"), strings.HasSuffix(rel, ".mrpack"):
+		return true
+	}
+	return false
+}
+
+// createPackwizPack creates pack.toml, pointing at the index
+func createPackwizPack(packData *project.Project, version string, index []byte) []byte {
+	sum := sha256.Sum256(index)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "name = %s\n", tomlString(packData.Name))
+	if packData.Author != "" {
+		fmt.Fprintf(&b, "author = %s\n", tomlString(packData.Author))
+	}
+	if version != "" {
+		fmt.Fprintf(&b, "version = %s\n", tomlString(version))
+	}
+	if packData.Description != "" {
+		fmt.Fprintf(&b, "description = %s\n", tomlString(packData.Description))
+	}
+	fmt.Fprintf(&b, "pack-format = %s\n", tomlString(packwizFormat))
+
+	b.WriteString("\n[index]\n")
+	b.WriteString("file = \"index.toml\"\n")
+	b.WriteString("hash-format = \"sha256\"\n")
+	fmt.Fprintf(&b, "hash = %s\n", tomlString(hex.EncodeToString(sum[:])))
+
+	// versions are a table, which packwiz writes sorted by key
+	versions := map[string]string{"minecraft": packData.Versions.Game}
+	switch loader := packData.Versions.Loader; loader.Name {
+	case "fabric", "quilt", "forge", "neoforge":
+		versions[loader.Name] = loader.Version
+	}
+	keys := make([]string, 0, len(versions))
+	for key := range versions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	b.WriteString("\n[versions]\n")
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%s = %s\n", key, tomlString(versions[key]))
+	}
+	return []byte(b.String())
+}
+
+// sha256File hashes a file's contents
+func sha256File(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// tomlString quotes a string as a TOML basic string
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func init() {
+	exportCmd.AddCommand(exportPackwizCmd)
+}
@@ -0,0 +1,332 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"minepack/core/api"
+	"minepack/core/download"
+	"minepack/core/project"
+	"minepack/util"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// component uids prism and multimc use for each loader
+var prismLoaderUIDs = map[string]string{
+	"fabric":     "net.fabricmc.fabric-loader",
+	"quilt":      "org.quiltmc.quilt-loader",
+	"forge":      "net.minecraftforge",
+	"neoforge":   "net.neoforged",
+	"liteloader": "com.mumfrey.liteloader",
+}
+
+// ways content can end up in an exported instance
+const (
+	prismModeBundled  = "bundled"  // jars are included in the zip
+	prismModeManifest = "manifest" // jars are downloaded before each launch
+)
+
+// files the manifest mode adds next to the instance, and the pre-launch commands that run them
+const (
+	prismDownloadList  = "minepack-downloads.txt"
+	prismDownloadSh    = "minepack-downloads.sh"
+	prismDownloadPs1   = "minepack-downloads.ps1"
+	prismPrelaunchUnix = `sh "$INST_DIR/` + prismDownloadSh + `"`
+	prismPrelaunchWin  = `powershell -NoProfile -ExecutionPolicy Bypass -File "$INST_DIR/` + prismDownloadPs1 + `"`
+)
+
+// exportPrismCmd represents the export prism command
+var exportPrismCmd = &cobra.Command{
+	Use:   "prism",
+	Short: "export as a Prism Launcher / MultiMC instance zip",
+	Long: `exports your modpack as an instance zip that Prism Launcher and MultiMC can import directly
+
+with --mode bundled (the default) every mod is included in the zip. with --mode manifest the zip only
+lists them, and the instance downloads whatever is missing before each launch`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Get current working directory and parse project
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting current working directory: %s"), err)
+			return
+		}
+
+		packData, err := project.ParseProject(cwd)
+		if err != nil {
+			fmt.Printf(util.FormatError("error parsing project: %s"), err)
+			return
+		}
+
+		mode, _ := cmd.Flags().GetString("mode")
+		if mode != prismModeBundled && mode != prismModeManifest {
+			fmt.Printf(util.FormatError("unknown mode %s, must be one of %v\n"), mode, []string{prismModeBundled, prismModeManifest})
+			return
+		}
+		windows, _ := cmd.Flags().GetBool("windows")
+
+		// Get all content
+		allContent, err := packData.GetAllContent()
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting all content: %s"), err)
+			return
+		}
+
+		outputName, err := exportOutput(cmd, packData, "{name}-{version}-prism.zip", false)
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting output path: %s"), err)
+			return
+		}
+		if err := exportPrismInstance(cmd.Context(), newDownloadManager(cmd, cwd), packData, allContent, mode, windows, outputName); err != nil {
+			if interrupted(cmd, "no instance was written") {
+				return
+			}
+			fmt.Printf(util.FormatError("failed to export prism instance: %s"), err)
+			return
+		}
+
+		successMsg := fmt.Sprintf("successfully exported to %s", outputName)
+		fmt.Println(util.FormatSuccess(successMsg))
+	},
+}
+
+// exportPrismInstance exports the pack as a prism instance zip
+func exportPrismInstance(ctx context.Context, manager *download.Manager, packData *project.Project, allContent []project.ContentData, mode string, windows bool, outputName string) error {
+	// Create temporary directory for building the instance
+	tempDir, err := exportTempDir(manager)
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	gameDir := filepath.Join(tempDir, ".minecraft")
+
+	// Copy the client's overrides into the game directory
+	if err := os.MkdirAll(gameDir, 0755); err != nil {
+		return fmt.Errorf("failed to create .minecraft: %w", err)
+	}
+	if err := copyOverridesExport(packData.Root, gameDir, "client"); err != nil {
+		return fmt.Errorf("failed to copy overrides: %w", err)
+	}
+
+	// Create mmc-pack.json
+	pack, err := createPrismPack(packData)
+	if err != nil {
+		return err
+	}
+	packJSON, err := json.MarshalIndent(pack, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal mmc-pack.json: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "mmc-pack.json"), packJSON, 0644); err != nil {
+		return fmt.Errorf("failed to write mmc-pack.json: %w", err)
+	}
+
+	// custom content without a download url already lives in the overrides folder, so it is skipped
+	var jobs []download.Job
+	var jobContents []project.ContentData
+	for _, content := range allContent {
+		if !content.InOverrides() {
+			jobs = append(jobs, api.DownloadJob(content, filepath.Join(gameDir, getContentPath(content))))
+			jobContents = append(jobContents, content)
+		}
+	}
+
+	prelaunch := ""
+	if mode == prismModeManifest {
+		// list the files for the pre-launch scripts instead of bundling them
+		if err := writePrismDownloads(tempDir, gameDir, jobs); err != nil {
+			return err
+		}
+		prelaunch = prismPrelaunchUnix
+		if windows {
+			prelaunch = prismPrelaunchWin
+		}
+	} else {
+		// Download content into the game directory
+		results, err := manager.Run(ctx, jobs)
+		if err != nil {
+			return fmt.Errorf("download cancelled: %w", err)
+		}
+		recordComputedHashes(ctx, packData, jobContents, results)
+		if failed := download.Failed(results); len(failed) > 0 {
+			return fmt.Errorf("failed to download %s: %w", failed[0].Job.Name, failed[0].Err)
+		}
+	}
+
+	// Create instance.cfg
+	if err := os.WriteFile(filepath.Join(tempDir, "instance.cfg"), []byte(createPrismInstanceConfig(packData, prelaunch)), 0644); err != nil {
+		return fmt.Errorf("failed to write instance.cfg: %w", err)
+	}
+
+	// Create the zip file
+	return createZipFile(ctx, tempDir, outputName)
+}
+
+// prismPack is the mmc-pack.json listing an instance's components
+type prismPack struct {
+	Components    []prismComponent `json:"components"`
+	FormatVersion int              `json:"formatVersion"`
+}
+
+type prismComponent struct {
+	UID       string `json:"uid"`
+	Version   string `json:"version"`
+	Important bool   `json:"important,omitempty"`
+}
+
+// createPrismPack creates mmc-pack.json with minecraft and the loader. prism resolves the
+// components they depend on, like intermediary mappings and lwjgl, when the instance is imported
+func createPrismPack(packData *project.Project) (*prismPack, error) {
+	components := []prismComponent{
+		{UID: "net.minecraft", Version: packData.Versions.Game, Important: true},
+	}
+
+	if loader := packData.Versions.Loader; loader.Name != "" && loader.Name != "vanilla" {
+		uid, ok := prismLoaderUIDs[loader.Name]
+		if !ok {
+			return nil, fmt.Errorf("prism doesn't support %s", loader.Name)
+		}
+		components = append(components, prismComponent{UID: uid, Version: loader.Version})
+	}
+
+	return &prismPack{Components: components, FormatVersion: 1}, nil
+}
+
+// createPrismInstanceConfig creates instance.cfg, running prelaunch before each launch if set
+func createPrismInstanceConfig(packData *project.Project, prelaunch string) string {
+	var b strings.Builder
+	b.WriteString("[General]\n")
+	b.WriteString("ConfigVersion=1.2\n")
+	b.WriteString("InstanceType=OneSix\n")
+	b.WriteString("iconKey=default\n")
+	fmt.Fprintf(&b, "name=%s\n", iniValue(packData.Name))
+	if prelaunch != "" {
+		b.WriteString("OverrideCommands=true\n")
+		fmt.Fprintf(&b, "PreLaunchCommand=%s\n", iniValue(prelaunch))
+	}
+	return b.String()
+}
+
+// iniValue quotes a value the way Qt's settings files expect when it has characters they treat
+// specially
+func iniValue(s string) string {
+	if !strings.ContainsAny(s, "\"\\;,=#") && strings.TrimSpace(s) == s {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// writePrismDownloads writes the list of files to download and the scripts that download them.
+// each line of the list is "<hash format>:<hash>", the path inside the game directory and the
+// url, separated by tabs
+func writePrismDownloads(instanceDir, gameDir string, jobs []download.Job) error {
+	var list strings.Builder
+	for _, job := range jobs {
+		if job.URL == "" {
+			return fmt.Errorf("%s has no download url", job.Name)
+		}
+		relPath, err := filepath.Rel(gameDir, job.Dest)
+		if err != nil {
+			return err
+		}
+		hash := "-"
+		switch {
+		case job.Hashes.Sha1 != "":
+			hash = "sha1:" + job.Hashes.Sha1
+		case job.Hashes.Sha512 != "":
+			hash = "sha512:" + job.Hashes.Sha512
+		case job.Hashes.Sha256 != "":
+			hash = "sha256:" + job.Hashes.Sha256
+		}
+		fmt.Fprintf(&list, "%s\t%s\t%s\n", hash, filepath.ToSlash(relPath), job.URL)
+	}
+
+	files := map[string]string{
+		prismDownloadList: list.String(),
+		prismDownloadSh:   prismDownloadScript,
+		prismDownloadPs1:  prismDownloadPowershell,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(instanceDir, name), []byte(data), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// prismDownloadScript downloads missing files from the list before launch on linux and macos
+const prismDownloadScript = `#!/bin/sh
+# downloads the pack's content before launch, run by prism as a pre-launch command
+cd "$INST_MC_DIR" || exit 1
+
+check() {
+  case "$1" in
+    -) return 0 ;;
+    sha1:*) tool="sha1sum"; alt="shasum -a 1" ;;
+    sha256:*) tool="sha256sum"; alt="shasum -a 256" ;;
+    sha512:*) tool="sha512sum"; alt="shasum -a 512" ;;
+  esac
+  if command -v "$tool" >/dev/null 2>&1; then
+    actual=$($tool "$2" | cut -d' ' -f1)
+  else
+    actual=$($alt "$2" | cut -d' ' -f1)
+  fi
+  [ "$actual" = "${1#*:}" ]
+}
+
+tab=$(printf '\t')
+while IFS="$tab" read -r hash path url; do
+  [ -z "$path" ] && continue
+  if [ -f "$path" ] && check "$hash" "$path"; then
+    continue
+  fi
+  echo "downloading $path"
+  mkdir -p "$(dirname "$path")"
+  if ! curl -fsSL -o "$path.part" "$url" || ! check "$hash" "$path.part"; then
+    echo "failed to download $path from $url" >&2
+    rm -f "$path.part"
+    exit 1
+  fi
+  mv "$path.part" "$path"
+done < "$INST_DIR/` + prismDownloadList + `"
+`
+
+// prismDownloadPowershell is the windows version of prismDownloadScript
+const prismDownloadPowershell = `# downloads the pack's content before launch, run by prism as a pre-launch command
+$ErrorActionPreference = "Stop"
+Set-Location $env:INST_MC_DIR
+
+function Test-Hash($hash, $path) {
+  if ($hash -eq "-") { return $true }
+  $algorithm, $expected = $hash -split ":", 2
+  return (Get-FileHash -Algorithm $algorithm -Path $path).Hash -eq $expected.ToUpper()
+}
+
+foreach ($line in Get-Content (Join-Path $env:INST_DIR "` + prismDownloadList + `")) {
+  if (-not $line) { continue }
+  $hash, $path, $url = $line -split "` + "`" + `t", 3
+  if ((Test-Path $path) -and (Test-Hash $hash $path)) { continue }
+  Write-Host "downloading $path"
+  New-Item -ItemType Directory -Force -Path (Split-Path $path) | Out-Null
+  Invoke-WebRequest -UseBasicParsing -Uri $url -OutFile "$path.part"
+  if (-not (Test-Hash $hash "$path.part")) {
+    Remove-Item "$path.part"
+    Write-Error "failed to download $path from $url"
+    exit 1
+  }
+  Move-Item -Force "$path.part" $path
+}
+`
+
+func init() {
+	exportCmd.AddCommand(exportPrismCmd)
+	exportPrismCmd.Flags().String("mode", prismModeBundled, "bundled to include every mod in the zip, or manifest to download them before launch")
+	exportPrismCmd.Flags().Bool("windows", false, "with --mode manifest, run the windows download script before launch instead of the linux and macos one")
+}
@@ -0,0 +1,347 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"minepack/core"
+	"minepack/core/api"
+	"minepack/core/download"
+	"minepack/core/project"
+	"minepack/util"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// overrides that only matter to a client, left out of server packs. directories match
+// everything inside them
+var clientOnlyOverrides = []string{
+	"options.txt",
+	"optionsof.txt",
+	"optionsshaders.txt",
+	"servers.dat",
+	"servers.dat_old",
+	"shaderpacks/",
+	"resourcepacks/",
+	"screenshots/",
+	"saves/",
+	"logs/",
+	"crash-reports/",
+}
+
+// exportServerCmd represents the export server command
+var exportServerCmd = &cobra.Command{
+	Use:   "server",
+	Short: "export a dedicated server pack",
+	Long: `exports a server pack with the mods that run on a server, server relevant overrides, an eula.txt
+and start scripts for linux and windows
+
+the loader's server jar or installer is referenced in the pack's README.txt, pass --bundle-jars to
+download it into the pack as well`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Get current working directory and parse project
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting current working directory: %s"), err)
+			return
+		}
+
+		packData, err := project.ParseProject(cwd)
+		if err != nil {
+			fmt.Printf(util.FormatError("error parsing project: %s"), err)
+			return
+		}
+
+		// Get all content
+		allContent, err := packData.GetAllContent()
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting all content: %s"), err)
+			return
+		}
+
+		bundleJars, _ := cmd.Flags().GetBool("bundle-jars")
+		asDir, _ := cmd.Flags().GetBool("dir")
+
+		// a server pack folder is updated in place, so only the zip is versioned
+		defaultName := "{name}-{version}-server.zip"
+		if asDir {
+			defaultName = "{name}-server"
+		}
+		outputName, err := exportOutput(cmd, packData, defaultName, asDir)
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting output path: %s"), err)
+			return
+		}
+		if err := exportServerPack(cmd.Context(), newDownloadManager(cmd, cwd), packData, allContent, bundleJars, asDir, outputName); err != nil {
+			if interrupted(cmd, "no pack was written") {
+				return
+			}
+			fmt.Printf(util.FormatError("failed to export server pack: %s"), err)
+			return
+		}
+
+		successMsg := fmt.Sprintf("successfully exported to %s", outputName)
+		fmt.Println(util.FormatSuccess(successMsg))
+	},
+}
+
+// serverContent keeps the mods a server can load, everything a server doesn't support or that
+// isn't a mod is left out
+func serverContent(allContent []project.ContentData) []project.ContentData {
+	var filtered []project.ContentData
+	for _, content := range allContent {
+		if content.ContentType != project.Mod {
+			continue
+		}
+		if content.Side.Server == project.SideUnsupported || content.Side.Server == project.SideInapplicable {
+			continue
+		}
+		filtered = append(filtered, content)
+	}
+	return filtered
+}
+
+// isClientOnlyOverride reports whether an override, relative to the overrides folder, is only
+// useful to a client
+func isClientOnlyOverride(relPath string) bool {
+	relPath = filepath.ToSlash(relPath)
+	for _, pattern := range clientOnlyOverrides {
+		if relPath == pattern || relPath+"/" == pattern || (strings.HasSuffix(pattern, "/") && strings.HasPrefix(relPath, pattern)) {
+			return true
+		}
+	}
+	return false
+}
+
+// copyServerOverrides copies the overrides a server uses
+func copyServerOverrides(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if relPath != "." && isClientOnlyOverride(relPath) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		dstPath := filepath.Join(dst, relPath)
+		if info.IsDir() {
+			return os.MkdirAll(dstPath, info.Mode())
+		}
+		return copyFile(path, dstPath)
+	})
+}
+
+// exportServerPack exports a dedicated server pack, as a zip or a directory
+func exportServerPack(ctx context.Context, manager *download.Manager, packData *project.Project, allContent []project.ContentData, bundleJars bool, asDir bool, outputName string) error {
+	if asDir {
+		if err := checkDirExport(outputName, "eula.txt", "server pack"); err != nil {
+			return err
+		}
+	}
+
+	loader, err := core.GetServerLoader(ctx, packData.Versions.Loader, packData.Versions.Game)
+	if err != nil {
+		return err
+	}
+
+	// Create temporary directory for building the pack
+	var tempDir string
+	if asDir {
+		tempDir, err = createDirExport(outputName)
+	} else {
+		tempDir, err = exportTempDir(manager)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Copy server overrides to the root of the pack, then the server's own overrides over them
+	overridesPath := filepath.Join(packData.Root, project.OverridesFolder)
+	if _, err := os.Stat(overridesPath); err == nil {
+		if err := copyServerOverrides(overridesPath, tempDir); err != nil {
+			return fmt.Errorf("failed to copy overrides: %w", err)
+		}
+	}
+	serverOverridesPath := filepath.Join(packData.Root, project.ServerOverridesFolder)
+	if _, err := os.Stat(serverOverridesPath); err == nil {
+		if err := copyDirExport(serverOverridesPath, tempDir); err != nil {
+			return fmt.Errorf("failed to copy server overrides: %w", err)
+		}
+	}
+
+	// Write eula.txt, start scripts and instructions
+	files := map[string]string{
+		"eula.txt":   "# By changing the setting below to TRUE you are indicating your agreement to the Minecraft EULA (https://aka.ms/MinecraftEULA).\neula=false\n",
+		"start.sh":   createServerStartScript(loader),
+		"start.bat":  createServerStartBatch(loader),
+		"README.txt": createServerReadme(packData, loader, bundleJars),
+	}
+	for name, data := range files {
+		mode := os.FileMode(0644)
+		if strings.HasSuffix(name, ".sh") {
+			mode = 0755
+		}
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(data), mode); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	// Download server mods, and the loader jar when bundling it
+	// custom content without a download url already lives in the overrides folder, so it is skipped
+	var jobs []download.Job
+	var jobContents []project.ContentData
+	for _, content := range serverContent(allContent) {
+		if content.InOverrides() {
+			continue
+		}
+		destPath := filepath.Join(tempDir, getContentPath(content))
+		jobs = append(jobs, api.DownloadJob(content, destPath))
+		jobContents = append(jobContents, content)
+	}
+	if bundleJars {
+		jobs = append(jobs, download.Job{Name: loader.Jar, URL: loader.URL, Dest: filepath.Join(tempDir, loader.Jar)})
+	}
+	results, err := manager.Run(ctx, jobs)
+	if err != nil {
+		return fmt.Errorf("download cancelled: %w", err)
+	}
+	recordComputedHashes(ctx, packData, jobContents, results[:len(jobContents)])
+	if failed := download.Failed(results); len(failed) > 0 {
+		return fmt.Errorf("failed to download %s: %w", failed[0].Job.Name, failed[0].Err)
+	}
+
+	if asDir {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return replaceDirExport(tempDir, outputName)
+	}
+	return createZipFile(ctx, tempDir, outputName)
+}
+
+// createServerStartScript creates start.sh, which installs the loader on first start if needed
+func createServerStartScript(loader *core.ServerLoader) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString("cd \"$(dirname \"$0\")\" || exit 1\n")
+	if loader.LaunchJar != "" {
+		b.WriteString("# set JAVA_ARGS to change how much memory the server gets\n")
+		b.WriteString("JAVA_ARGS=\"${JAVA_ARGS:--Xms2G -Xmx4G}\"\n")
+	}
+	b.WriteString("\n")
+
+	launch := loader.LaunchJar
+	if launch == "" {
+		launch = "run.sh"
+	}
+	fmt.Fprintf(&b, "if [ ! -f %s ]; then\n", launch)
+	if len(loader.InstallArgs) > 0 {
+		fmt.Fprintf(&b, "  if [ ! -f %s ]; then\n", loader.Jar)
+		fmt.Fprintf(&b, "    echo \"%s is missing, see README.txt\"\n", loader.Jar)
+		b.WriteString("    exit 1\n")
+		b.WriteString("  fi\n")
+		fmt.Fprintf(&b, "  java -jar %s %s || exit 1\n", loader.Jar, strings.Join(loader.InstallArgs, " "))
+	} else {
+		fmt.Fprintf(&b, "  echo \"%s is missing, see README.txt\"\n", launch)
+		b.WriteString("  exit 1\n")
+	}
+	b.WriteString("fi\n\n")
+
+	if loader.LaunchJar == "" {
+		// the installer's run script reads memory settings from user_jvm_args.txt
+		b.WriteString("exec sh ./run.sh nogui\n")
+	} else {
+		fmt.Fprintf(&b, "exec java $JAVA_ARGS -jar %s nogui\n", loader.LaunchJar)
+	}
+	return b.String()
+}
+
+// createServerStartBatch creates start.bat, the windows version of start.sh
+func createServerStartBatch(loader *core.ServerLoader) string {
+	var b strings.Builder
+	b.WriteString("@echo off\r\n")
+	b.WriteString("cd /d \"%~dp0\"\r\n")
+	if loader.LaunchJar != "" {
+		b.WriteString("rem set JAVA_ARGS to change how much memory the server gets\r\n")
+		b.WriteString("if \"%JAVA_ARGS%\"==\"\" set JAVA_ARGS=-Xms2G -Xmx4G\r\n")
+	}
+	b.WriteString("\r\n")
+
+	launch := loader.LaunchJar
+	if launch == "" {
+		launch = "run.bat"
+	}
+	fmt.Fprintf(&b, "if not exist %s (\r\n", launch)
+	if len(loader.InstallArgs) > 0 {
+		fmt.Fprintf(&b, "  if not exist %s (\r\n", loader.Jar)
+		fmt.Fprintf(&b, "    echo %s is missing, see README.txt\r\n", loader.Jar)
+		b.WriteString("    pause\r\n")
+		b.WriteString("    exit /b 1\r\n")
+		b.WriteString("  )\r\n")
+		fmt.Fprintf(&b, "  java -jar %s %s || exit /b 1\r\n", loader.Jar, strings.Join(loader.InstallArgs, " "))
+	} else {
+		fmt.Fprintf(&b, "  echo %s is missing, see README.txt\r\n", launch)
+		b.WriteString("  pause\r\n")
+		b.WriteString("  exit /b 1\r\n")
+	}
+	b.WriteString(")\r\n\r\n")
+
+	if loader.LaunchJar == "" {
+		b.WriteString("call run.bat nogui\r\n")
+	} else {
+		fmt.Fprintf(&b, "java %%JAVA_ARGS%% -jar %s nogui\r\n", loader.LaunchJar)
+	}
+	b.WriteString("pause\r\n")
+	return b.String()
+}
+
+// createServerReadme explains how to get the server running
+func createServerReadme(packData *project.Project, loader *core.ServerLoader, bundled bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s server\n\n", packData.Name)
+	fmt.Fprintf(&b, "minecraft %s", packData.Versions.Game)
+	if packData.Versions.Loader.Name != "" {
+		fmt.Fprintf(&b, " with %s %s", loader.Name, packData.Versions.Loader.Version)
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString("setting up:\n")
+	step := 1
+	if !bundled {
+		fmt.Fprintf(&b, "%d. download %s\n   and save it in this folder as %s\n", step, loader.URL, loader.Jar)
+		step++
+	}
+	fmt.Fprintf(&b, "%d. read the minecraft eula (https://aka.ms/MinecraftEULA) and set eula=true in eula.txt\n", step)
+	step++
+	if len(loader.InstallArgs) > 0 {
+		fmt.Fprintf(&b, "%d. run start.sh (linux/macos) or start.bat (windows), the first start installs %s with\n   java -jar %s %s\n", step, loader.Name, loader.Jar, strings.Join(loader.InstallArgs, " "))
+	} else {
+		fmt.Fprintf(&b, "%d. run start.sh (linux/macos) or start.bat (windows)\n", step)
+	}
+
+	if loader.LaunchJar == "" {
+		b.WriteString("\nmemory is set in user_jvm_args.txt once the server is installed\n")
+	} else {
+		b.WriteString("\nset the JAVA_ARGS environment variable to change memory, it defaults to -Xms2G -Xmx4G\n")
+	}
+	return b.String()
+}
+
+func init() {
+	exportCmd.AddCommand(exportServerCmd)
+	exportServerCmd.Flags().Bool("bundle-jars", false, "download the loader's server jar or installer into the pack, through the download cache")
+	exportServerCmd.Flags().Bool("dir", false, "write the server pack to a directory instead of a zip")
+}
@@ -5,18 +5,16 @@ package cmd
 
 import (
 	"archive/zip"
-	"crypto/sha512"
-	"encoding/hex"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"minepack/core"
 	mymodrinth "minepack/core/api/modrinth"
+	"minepack/core/download"
 	"minepack/core/project"
 	"minepack/util"
 	"minepack/util/version"
-	"net/http"
-	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -115,7 +113,7 @@ func (m importProgress) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case tea.KeyMsg:
 		if msg.String() == "ctrl+c" {
-			return m, tea.Quit
+			return m, tea.Interrupt
 		}
 	}
 
@@ -135,100 +133,291 @@ func (m importProgress) View() string {
 	return fmt.Sprintf("%s %s (%d/%d)\n", m.stage, m.progress.ViewAs(percentage), m.current, m.total)
 }
 
-// ModrinthVersionFromHash represents response from version lookup by hash
-type ModrinthVersionFromHash struct {
-	ID        string `json:"id"`
-	ProjectID string `json:"project_id"`
-	Name      string `json:"name"`
-	Files     []struct {
-		Hashes struct {
-			Sha1   string `json:"sha1"`
-			Sha512 string `json:"sha512"`
-		} `json:"hashes"`
-		URL      string `json:"url"`
-		Filename string `json:"filename"`
-		Primary  bool   `json:"primary"`
-		Size     int64  `json:"size"`
-	} `json:"files"`
+// runProgress shows a progress bar while work runs in the background. pressing ctrl+c, which the
+// progress bar receives as a key instead of a signal, calls cancel so work can stop. it returns
+// once work has
+func runProgress(ctx context.Context, cancel context.CancelFunc, prog importProgress, work func(p *tea.Program)) error {
+	p := tea.NewProgram(prog, tea.WithContext(ctx))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		work(p)
+		p.Quit()
+	}()
+
+	_, err := p.Run()
+	if errors.Is(err, tea.ErrInterrupted) || errors.Is(err, tea.ErrProgramKilled) {
+		cancel()
+	}
+	<-done
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// importDefaults is what an importer read from the imported pack about the project it creates,
+// the name, description and author are confirmed with the user first
+type importDefaults struct {
+	name          string
+	description   string
+	author        string
+	origin        string // what the project was imported from, e.g. "modrinth pack"
+	defaultSource string
+	game          string
+	loader        project.ModloaderVersion
 }
 
-// calculateSHA512 calculates SHA512 hash of a file
-func calculateSHA512(filePath string) (string, error) {
-	file, err := os.Open(filePath)
+// importedProject is a project created by an import. everything the import adds to it is part
+// of batch, which the importer commits once it is done
+type importedProject struct {
+	*project.Project
+	batch       *project.Batch
+	createdRoot bool
+	created     int // content files that were added
+}
+
+// createImportedProject asks for the project's details, creates it and adds contents to it. an
+// interrupted import removes the project it created instead of leaving half of it behind, callers
+// defer discardIfInterrupted for whatever they add afterwards
+func createImportedProject(ctx context.Context, cancel context.CancelFunc, defaults importDefaults, contents []project.ContentData) (*importedProject, error) {
+	name, description, author := defaults.name, defaults.description, defaults.author
+
+	metaForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("project name").
+				Description("name for the imported project").
+				Placeholder("imported-pack").
+				Value(&name),
+			huh.NewText().
+				Title("description").
+				Description("description for the imported project").
+				Lines(3).
+				Placeholder("imported from "+defaults.origin).
+				Value(&description),
+			huh.NewInput().
+				Title("author").
+				Description("author of the project").
+				Placeholder("unknown").
+				Value(&author),
+		),
+	)
+
+	if err := metaForm.Run(); err != nil {
+		return nil, fmt.Errorf("prompt failed: %w", err)
+	}
+
+	if name == "" {
+		name = "imported-pack"
+	}
+	if description == "" {
+		description = "imported from " + defaults.origin
+	}
+	if author == "" {
+		author = "unknown"
+	}
+
+	// Create project structure
+	cwd, err := os.Getwd()
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("failed to get current working directory: %w", err)
 	}
-	defer file.Close()
 
-	hash := sha512.New()
-	if _, err := io.Copy(hash, file); err != nil {
-		return "", err
+	imported := &importedProject{Project: &project.Project{
+		Name:          name,
+		Description:   description,
+		Author:        author,
+		DefaultSource: defaults.defaultSource,
+		Root:          filepath.Join(cwd, strings.ReplaceAll(name, " ", "-")),
+		Versions: project.ProjectVersions{
+			Game:     defaults.game,
+			Loader:   defaults.loader,
+			Minepack: version.Version,
+		},
+	}}
+
+	_, statErr := os.Stat(imported.Root)
+	imported.createdRoot = os.IsNotExist(statErr)
+
+	// Write project files
+	if err := project.WriteProject(imported.Project); err != nil {
+		return nil, fmt.Errorf("failed to write project files: %w", err)
 	}
 
-	return hex.EncodeToString(hash.Sum(nil)), nil
-}
+	imported.batch, err = imported.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start changes: %w", err)
+	}
+
+	fmt.Printf("\nproject created at: %s\n", imported.Root)
 
-// bulkFetchProjects fetches multiple projects from Modrinth in a single API call
-func bulkFetchProjects(projectIDs []string) (map[string]*modrinth.Project, error) {
-	if len(projectIDs) == 0 {
-		return make(map[string]*modrinth.Project), nil
+	if len(contents) == 0 {
+		return imported, nil
 	}
 
-	// Build the URL with proper JSON array encoding for GET request
-	baseURL := "https://api.modrinth.com/v2/projects"
+	// Create content files
+	var contentCreationErrors []string
+	prog := newImportProgress(len(contents), "creating content files...")
+	err = runProgress(ctx, cancel, prog, func(p *tea.Program) {
+		for i, contentData := range contents {
+			if ctx.Err() != nil {
+				return
+			}
+			// Add content to project (this updates content.mp.sum.yaml)
+			if err := imported.AddContent(contentData); err != nil {
+				contentCreationErrors = append(contentCreationErrors, fmt.Sprintf("failed to add content for %s: %v", contentData.Name, err))
+			} else {
+				imported.created++
+			}
+			p.Send(contentCreationProgressMsg{current: i + 1, total: len(contents)})
+		}
+	})
 
-	// Create JSON array string
-	idsJSON, err := json.Marshal(projectIDs)
+	if ctx.Err() != nil {
+		imported.discardIfInterrupted(ctx)
+		return nil, ctx.Err()
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal IDs: %w", err)
+		fmt.Printf(util.FormatError("content creation failed: %v\n"), err)
+	} else {
+		fmt.Printf("created %d content files\n", imported.created)
 	}
 
-	// Create URL with proper encoding
-	u, err := url.Parse(baseURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse URL: %w", err)
+	// Report any content creation errors
+	for _, errMsg := range contentCreationErrors {
+		fmt.Println(util.FormatWarning(errMsg))
 	}
+	return imported, nil
+}
 
-	q := u.Query()
-	q.Set("ids", string(idsJSON))
-	u.RawQuery = q.Encode()
+// discardIfInterrupted undoes an import that was interrupted after creating its project: a
+// directory the import created is removed, otherwise the content changes are rolled back
+func (i *importedProject) discardIfInterrupted(ctx context.Context) {
+	if ctx.Err() == nil {
+		return
+	}
+	if i.createdRoot {
+		os.RemoveAll(i.Root)
+		return
+	}
+	i.batch.Rollback()
+}
 
-	// Make GET request
-	req, err := http.NewRequest("GET", u.String(), nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+// commit records everything the import added, along with the upstream it was imported from if
+// any. the upstream shares the commit with the imported content, which is what a sync later
+// compares the project's own changes against
+func (i *importedProject) commit(ctx context.Context, upstream *project.Upstream) error {
+	if upstream != nil {
+		i.Upstream = upstream
+		if err := i.Save("Set upstream"); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			fmt.Printf(util.FormatWarning("warning: failed to record the upstream: %s\n"), err)
+		}
 	}
 
-	req.Header.Set("User-Agent", "Minepack/1.0 (+https://github.com/ayeuhugyu/minepack)")
+	if err := i.batch.Commit(fmt.Sprintf("Import content: %d mods", i.created)); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		fmt.Printf(util.FormatWarning("warning: failed to commit changes: %s\n"), err)
+	}
+	return nil
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+// optionalSide makes content that a pack marks as optional installable but not required on the
+// sides it runs on
+func optionalSide(side project.ModSide) project.ModSide {
+	if side.Client == project.SideRequired {
+		side.Client = project.SideOptional
 	}
-	defer resp.Body.Close()
+	if side.Server == project.SideRequired {
+		side.Server = project.SideOptional
+	}
+	return side
+}
+
+// ModrinthVersionFromHash represents response from version lookup by hash
+type ModrinthVersionFromHash struct {
+	ID        string `json:"id"`
+	ProjectID string `json:"project_id"`
+	Name      string `json:"name"`
+	Files     []struct {
+		Hashes struct {
+			Sha1   string `json:"sha1"`
+			Sha512 string `json:"sha512"`
+		} `json:"hashes"`
+		URL      string `json:"url"`
+		Filename string `json:"filename"`
+		Primary  bool   `json:"primary"`
+		Size     int64  `json:"size"`
+	} `json:"files"`
+}
 
-	if resp.StatusCode != 200 {
-		// Read response body for debugging
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error: %d - %s", resp.StatusCode, string(body))
+// prefetchVersionDependencies looks up the dependencies of every matched version in bulk so
+// creating content files doesn't make a request per mod
+func prefetchVersionDependencies(ctx context.Context, versions map[string]*modrinth.Version) error {
+	list := make([]*modrinth.Version, 0, len(versions))
+	for _, version := range versions {
+		list = append(list, version)
 	}
+	return mymodrinth.PrefetchDependencies(ctx, list)
+}
 
-	// Parse response
-	var projects []*modrinth.Project
-	if err := json.NewDecoder(resp.Body).Decode(&projects); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+// packFileHash is the hash a file in a modrinth pack's index is identified by, its sha512 where
+// the index has one and its sha1 otherwise
+func packFileHash(hashes map[string]string) string {
+	if hashes["sha512"] != "" {
+		return hashes["sha512"]
 	}
+	return hashes["sha1"]
+}
+
+// lookupPackHashes finds the modrinth versions of files in a modrinth pack's index, keyed by
+// packFileHash. files are looked up by sha512, the ones that weren't found that way by sha1
+func lookupPackHashes(ctx context.Context, files []map[string]string) (map[string]*modrinth.Version, error) {
+	result := make(map[string]*modrinth.Version)
 
-	// Convert to map for easy lookup
-	projectMap := make(map[string]*modrinth.Project)
-	for _, proj := range projects {
-		if proj.ID != nil {
-			projectMap[*proj.ID] = proj
+	var sha512s []string
+	for _, hashes := range files {
+		if hashes["sha512"] != "" {
+			sha512s = append(sha512s, hashes["sha512"])
+		}
+	}
+	if len(sha512s) > 0 {
+		versions, err := mymodrinth.GetVersionsFromHashes(ctx, sha512s, "sha512")
+		if err != nil {
+			return nil, err
+		}
+		for hash, version := range versions {
+			result[hash] = version
 		}
 	}
 
-	return projectMap, nil
+	var sha1s []string
+	bySha1 := make(map[string]string)
+	for _, hashes := range files {
+		key := packFileHash(hashes)
+		if _, found := result[key]; found || hashes["sha1"] == "" {
+			continue
+		}
+		sha1s = append(sha1s, hashes["sha1"])
+		bySha1[hashes["sha1"]] = key
+	}
+	if len(sha1s) > 0 {
+		versions, err := mymodrinth.GetVersionsFromHashes(ctx, sha1s, "sha1")
+		if err != nil {
+			return nil, err
+		}
+		for hash, version := range versions {
+			if key, ok := bySha1[hash]; ok {
+				result[key] = version
+			}
+		}
+	}
+	return result, nil
 }
 
 // copyDirectory recursively copies a directory and all its contents
@@ -273,32 +462,29 @@ func copyDirectory(src, dst string) error {
 	})
 }
 
-// importModrinthPack imports a Modrinth modpack
-func importModrinthPack(packPath string) error {
-	// Create temporary directory for extraction
-	tempDir, err := os.MkdirTemp("", "minepack-mrpack-*")
-	if err != nil {
-		return fmt.Errorf("failed to create temporary directory: %w", err)
-	}
-	defer os.RemoveAll(tempDir) // Clean up when done
-
-	fmt.Printf("extracting modpack archive...\n")
-
-	// Open the .mrpack file as a ZIP archive
+// extractArchive extracts a modpack zip into dest
+func extractArchive(ctx context.Context, packPath, dest string) error {
 	zipReader, err := zip.OpenReader(packPath)
 	if err != nil {
-		return fmt.Errorf("failed to open .mrpack file as ZIP archive: %w", err)
+		return fmt.Errorf("failed to open %s as ZIP archive: %w", filepath.Base(packPath), err)
 	}
 	defer zipReader.Close()
 
 	// Extract all files from the ZIP archive
 	for _, file := range zipReader.File {
-		// Create the full file path
-		destPath := filepath.Join(tempDir, file.Name)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Create the full file path, refusing entries that would end up outside dest
+		if !filepath.IsLocal(filepath.FromSlash(file.Name)) {
+			return fmt.Errorf("archive contains an unsafe path %s", file.Name)
+		}
+		destPath := filepath.Join(dest, filepath.FromSlash(file.Name))
 
 		// Create directory if needed
 		if file.FileInfo().IsDir() {
-			os.MkdirAll(destPath, file.FileInfo().Mode())
+			os.MkdirAll(destPath, 0755)
 			continue
 		}
 
@@ -327,6 +513,69 @@ func importModrinthPack(packPath string) error {
 			return fmt.Errorf("failed to extract file %s: %w", file.Name, err)
 		}
 	}
+	return nil
+}
+
+// packArchiveFormat tells modrinth and curseforge modpack zips apart by their manifest
+func packArchiveFormat(packPath string) (string, error) {
+	zipReader, err := zip.OpenReader(packPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s as ZIP archive: %w", filepath.Base(packPath), err)
+	}
+	defer zipReader.Close()
+
+	for _, file := range zipReader.File {
+		switch file.Name {
+		case "modrinth.index.json":
+			return "modrinth", nil
+		case "manifest.json":
+			return "curseforge", nil
+		}
+	}
+	return "", fmt.Errorf("%s has no modrinth.index.json or manifest.json, it isn't a modrinth or curseforge modpack", filepath.Base(packPath))
+}
+
+// isPackwizPack reports whether a path is a packwiz pack.toml or a folder containing one
+func isPackwizPack(importPath string, info os.FileInfo) bool {
+	if !info.IsDir() {
+		return strings.HasSuffix(importPath, ".toml")
+	}
+	_, err := os.Stat(filepath.Join(importPath, "pack.toml"))
+	return err == nil
+}
+
+// modrinthPackLoader reads a .mrpack's loader from its dependencies
+func modrinthPackLoader(dependencies map[string]string) project.ModloaderVersion {
+	if fabricVersion, ok := dependencies["fabric-loader"]; ok {
+		return project.ModloaderVersion{Name: "fabric", Version: fabricVersion}
+	} else if forgeVersion, ok := dependencies["forge"]; ok {
+		return project.ModloaderVersion{Name: "forge", Version: forgeVersion}
+	} else if quiltVersion, ok := dependencies["quilt-loader"]; ok {
+		return project.ModloaderVersion{Name: "quilt", Version: quiltVersion}
+	} else if neoforgeVersion, ok := dependencies["neoforge"]; ok {
+		return project.ModloaderVersion{Name: "neoforge", Version: neoforgeVersion}
+	}
+	return project.ModloaderVersion{}
+}
+
+// importModrinthPack imports a Modrinth modpack, recording upstream in the project when the
+// pack was downloaded from a published modpack
+func importModrinthPack(ctx context.Context, manager *download.Manager, packPath string, upstream *project.Upstream) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Create temporary directory for extraction
+	tempDir, err := os.MkdirTemp("", "minepack-mrpack-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir) // Clean up when done
+
+	fmt.Printf("extracting modpack archive...\n")
+
+	if err := extractArchive(ctx, packPath, tempDir); err != nil {
+		return err
+	}
 
 	// Read the modrinth.index.json file
 	indexPath := filepath.Join(tempDir, "modrinth.index.json")
@@ -345,22 +594,9 @@ func importModrinthPack(packPath string) error {
 	fmt.Printf("game version: %s\n", pack.Dependencies["minecraft"])
 
 	// Extract modloader info
-	var modloader, modloaderVersion string
-	if fabricVersion, ok := pack.Dependencies["fabric-loader"]; ok {
-		modloader = "fabric"
-		modloaderVersion = fabricVersion
-	} else if forgeVersion, ok := pack.Dependencies["forge"]; ok {
-		modloader = "forge"
-		modloaderVersion = forgeVersion
-	} else if quiltVersion, ok := pack.Dependencies["quilt-loader"]; ok {
-		modloader = "quilt"
-		modloaderVersion = quiltVersion
-	} else if neoforgeVersion, ok := pack.Dependencies["neoforge"]; ok {
-		modloader = "neoforge"
-		modloaderVersion = neoforgeVersion
-	}
+	loader := modrinthPackLoader(pack.Dependencies)
 
-	fmt.Printf("modloader: %s %s\n", modloader, modloaderVersion)
+	fmt.Printf("modloader: %s %s\n", loader.Name, loader.Version)
 	fmt.Printf("files: %d\n\n", len(pack.Files))
 
 	// Collect mod files (files in mods/ folder with .jar extension)
@@ -417,32 +653,22 @@ func importModrinthPack(packPath string) error {
 			FileSize  int64
 		})
 
+		var fileHashes []map[string]string
 		for _, file := range modFiles {
-			if sha512Hash, ok := file.Hashes["sha512"]; ok {
-				hashes = append(hashes, sha512Hash)
-				hashToFile[sha512Hash] = struct {
-					Path      string
-					Downloads []string
-					FileSize  int64
-				}{
-					Path:      file.Path,
-					Downloads: file.Downloads,
-					FileSize:  file.FileSize,
-				}
-			} else {
-				// If no SHA512, try SHA1
-				if sha1Hash, ok := file.Hashes["sha1"]; ok {
-					hashes = append(hashes, sha1Hash)
-					hashToFile[sha1Hash] = struct {
-						Path      string
-						Downloads []string
-						FileSize  int64
-					}{
-						Path:      file.Path,
-						Downloads: file.Downloads,
-						FileSize:  file.FileSize,
-					}
-				}
+			hash := packFileHash(file.Hashes)
+			if hash == "" {
+				continue
+			}
+			hashes = append(hashes, hash)
+			fileHashes = append(fileHashes, file.Hashes)
+			hashToFile[hash] = struct {
+				Path      string
+				Downloads []string
+				FileSize  int64
+			}{
+				Path:      file.Path,
+				Downloads: file.Downloads,
+				FileSize:  file.FileSize,
 			}
 		}
 
@@ -453,15 +679,15 @@ func importModrinthPack(packPath string) error {
 		err = spinner.New().
 			Title("looking up mods on modrinth...").
 			Type(spinner.Dots).
+			Context(ctx).
 			Action(func() {
-				versions, lookupErr = mymodrinth.ModrinthClient.VersionFiles.GetFromHashes(hashes, "sha512")
-				if lookupErr != nil {
-					// Try with SHA1 if SHA512 fails
-					versions, lookupErr = mymodrinth.ModrinthClient.VersionFiles.GetFromHashes(hashes, "sha1")
-				}
+				versions, lookupErr = lookupPackHashes(ctx, fileHashes)
 			}).
 			Run()
 
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		if err != nil {
 			fmt.Printf(util.FormatError("spinner error: %v\n"), err)
 			return fmt.Errorf("lookup failed: %w", err)
@@ -489,11 +715,18 @@ func importModrinthPack(packPath string) error {
 		err = spinner.New().
 			Title("fetching project details...").
 			Type(spinner.Dots).
+			Context(ctx).
 			Action(func() {
-				projects, fetchErr = bulkFetchProjects(projectIDs)
+				projects, fetchErr = mymodrinth.GetProjects(ctx, projectIDs)
+				if fetchErr == nil {
+					fetchErr = prefetchVersionDependencies(ctx, versions)
+				}
 			}).
 			Run()
 
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		if err != nil {
 			fmt.Printf(util.FormatError("spinner error: %v\n"), err)
 			return fmt.Errorf("fetch failed: %w", err)
@@ -529,128 +762,29 @@ func importModrinthPack(packPath string) error {
 			return nil
 		}
 
-		// Ask for project details
-		var name, description, author string
-
-		if pack.Name != "" {
-			name = pack.Name
-		}
-		if pack.Summary != "" {
-			description = pack.Summary
-		}
-
-		metaForm := huh.NewForm(
-			huh.NewGroup(
-				huh.NewInput().
-					Title("project name").
-					Description("name for the imported project").
-					Placeholder("imported-pack").
-					Value(&name),
-				huh.NewText().
-					Title("description").
-					Description("description for the imported project").
-					Lines(3).
-					Placeholder("imported from modrinth pack").
-					Value(&description),
-				huh.NewInput().
-					Title("author").
-					Description("author of the project").
-					Placeholder("unknown").
-					Value(&author),
-			),
-		)
-
-		if err := metaForm.Run(); err != nil {
-			return fmt.Errorf("prompt failed: %w", err)
-		}
-
-		if name == "" {
-			name = "imported-pack"
-		}
-		if description == "" {
-			description = "imported from modrinth pack"
-		}
-		if author == "" {
-			author = "unknown"
+		var contents []project.ContentData
+		for _, hash := range hashes {
+			version, found := versions[hash]
+			if !found || version.ProjectID == nil {
+				continue
+			}
+			if proj, exists := projects[*version.ProjectID]; exists {
+				contents = append(contents, mymodrinth.ConvertProjectToContentData(ctx, proj, version))
+			}
 		}
 
-		// Create project structure
-		cwd, err := os.Getwd()
+		projectData, err := createImportedProject(ctx, cancel, importDefaults{
+			name:          pack.Name,
+			description:   pack.Summary,
+			origin:        "modrinth pack",
+			defaultSource: "modrinth",
+			game:          pack.Dependencies["minecraft"],
+			loader:        loader,
+		}, contents)
 		if err != nil {
-			return fmt.Errorf("failed to get current working directory: %w", err)
-		}
-
-		projectData := project.Project{
-			Name:          name,
-			Description:   description,
-			Author:        author,
-			DefaultSource: "modrinth",
-			Root:          filepath.Join(cwd, strings.ReplaceAll(name, " ", "-")),
-			Versions: project.ProjectVersions{
-				Game: pack.Dependencies["minecraft"],
-				Loader: project.ModloaderVersion{
-					Name:    modloader,
-					Version: modloaderVersion,
-				},
-				Minepack: version.Version,
-			},
-		}
-
-		// Write project files
-		if err := project.WriteProject(&projectData); err != nil {
-			return fmt.Errorf("failed to write project files: %w", err)
-		}
-
-		fmt.Printf("\nproject created at: %s\n", projectData.Root)
-
-		// Create content files for found mods
-		if len(foundMods) > 0 {
-			var contentCreationErrors []string
-			var createdCount int
-
-			prog := newImportProgress(len(hashes), "creating content files...")
-			p := tea.NewProgram(prog)
-
-			go func() {
-				current := 0
-				for _, hash := range hashes {
-					if version, found := versions[hash]; found && version.ProjectID != nil {
-						projectID := *version.ProjectID
-
-						// Get project data for content creation
-						if project, exists := projects[projectID]; exists {
-							// Convert project to content data
-							contentData := mymodrinth.ConvertProjectToContentData(project, version)
-
-							// Add content to project (this updates content.mp.sum.yaml)
-							if err := projectData.AddContent(contentData); err != nil {
-								projectTitle := projectID
-								if project.Title != nil {
-									projectTitle = *project.Title
-								}
-								contentCreationErrors = append(contentCreationErrors, fmt.Sprintf("failed to add content for %s: %v", projectTitle, err))
-							} else {
-								createdCount++
-							}
-						}
-					}
-					current++
-					p.Send(contentCreationProgressMsg{current: current, total: len(hashes)})
-				}
-				p.Quit()
-			}()
-
-			if _, err := p.Run(); err != nil {
-				fmt.Printf(util.FormatError("content creation failed: %v\n"), err)
-			} else {
-				fmt.Printf("created %d content files\n", createdCount)
-			}
-
-			// Report any content creation errors
-			for _, errMsg := range contentCreationErrors {
-				fmt.Println(util.FormatWarning(errMsg))
-			}
+			return err
 		}
+		defer projectData.discardIfInterrupted(ctx)
 
 		// Handle not found mods and other files as overrides
 		if len(notFoundFiles) > 0 || len(overrideFiles) > 0 {
@@ -660,533 +794,81 @@ func importModrinthPack(packPath string) error {
 			}
 		}
 
-		// Copy overrides folder from extracted archive if it exists
-		archiveOverridesPath := filepath.Join(tempDir, "overrides")
-		if _, err := os.Stat(archiveOverridesPath); err == nil {
-			projectOverridesPath := filepath.Join(projectData.Root, "overrides")
+		// Copy the override folders from the extracted archive if they exist
+		for _, folder := range []string{project.OverridesFolder, project.ClientOverridesFolder, project.ServerOverridesFolder} {
+			archiveOverridesPath := filepath.Join(tempDir, folder)
+			if _, err := os.Stat(archiveOverridesPath); err != nil {
+				continue
+			}
+			projectOverridesPath := filepath.Join(projectData.Root, folder)
 
 			err = spinner.New().
-				Title("copying overrides from archive...").
+				Title(fmt.Sprintf("copying %s from archive...", folder)).
 				Type(spinner.Dots).
 				Action(func() {
 					err = copyDirectory(archiveOverridesPath, projectOverridesPath)
 				}).
 				Run()
 
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			if err != nil {
-				fmt.Printf(util.FormatWarning("failed to copy overrides from archive: %v\n"), err)
+				fmt.Printf(util.FormatWarning("failed to copy %s from archive: %v\n"), folder, err)
 			} else {
-				fmt.Println("copied overrides from archive")
+				fmt.Printf("copied %s from archive\n", folder)
 			}
 		}
 
-		fmt.Printf("\nsuccessfully imported modpack!\n")
-		fmt.Printf("- modrinth mods: %d\n", len(foundMods))
-		if len(notFoundFiles) > 0 {
-			fmt.Printf("- override mods: %d\n", len(notFoundFiles))
-		}
-		if len(overrideFiles) > 0 {
-			fmt.Printf("- override files: %d\n", len(overrideFiles))
-		}
-	} else {
-		fmt.Println("no mod files found in pack")
-	}
-
-	return nil
-}
-
-// importMinecraftInstance imports a Minecraft instance
-func importMinecraftInstance(instancePath string) error {
-	// Check if it's a valid minecraft instance
-	modsPath := filepath.Join(instancePath, "mods")
-	if _, err := os.Stat(modsPath); os.IsNotExist(err) {
-		fmt.Println(util.FormatError("not a valid minecraft instance: mods folder not found"))
-		return nil
-	}
-
-	// Get list of mod files
-	modFiles, err := filepath.Glob(filepath.Join(modsPath, "*.jar"))
-	if err != nil {
-		fmt.Printf(util.FormatError("failed to list mod files: %w"), err)
-		return nil
-	}
-
-	if len(modFiles) == 0 {
-		fmt.Println(util.FormatError("no mod files found in instance"))
-		return nil
-	}
-
-	fmt.Printf("found %d mod files in instance\n", len(modFiles))
-
-	// Ask for version and modloader info (reuse logic from init command)
-	var gameVersion, selectedModloader string
-
-	// Fetch minecraft versions for validation
-	var allGameVersions *core.MinecraftManifest
-	var fetchErr error
-
-	err = spinner.New().
-		Title("fetching minecraft versions...").
-		Type(spinner.Dots).
-		Action(func() {
-			allGameVersions, fetchErr = core.FetchMinecraftVersions()
-		}).
-		Run()
-
-	if err != nil {
-		fmt.Println(util.FormatError("spinner error: %w"), err)
-		return nil
-	}
-	if fetchErr != nil {
-		fmt.Println(util.FormatError("failed to fetch minecraft versions: %w"), fetchErr)
-		return nil
-	}
-
-	var allGameVersionsFlat []string
-	for _, v := range allGameVersions.Versions {
-		allGameVersionsFlat = append(allGameVersionsFlat, v.ID)
-	}
-
-	// Get game version
-	var inputGameVersion string
-	versionForm := huh.NewForm(
-		huh.NewGroup(
-			huh.NewInput().
-				Title("game version").
-				Description("enter minecraft version for this instance").
-				Placeholder("1.20.1").
-				Suggestions(allGameVersionsFlat).
-				Value(&inputGameVersion),
-		),
-	)
-
-	if err := versionForm.Run(); err != nil {
-		fmt.Println(util.FormatError("prompt failed: %w"), err)
-		return nil
-	}
-
-	gameVersion = inputGameVersion
-	if gameVersion == "" {
-		gameVersion = "1.20.1"
-	}
-
-	// Fetch modloader versions
-	var allModloaderVersions map[string]string
-
-	err = spinner.New().
-		Title("fetching modloader versions...").
-		Type(spinner.Dots).
-		Action(func() {
-			allModloaderVersions = core.GetAllLatestVersions(gameVersion)
-		}).
-		Run()
-
-	if err != nil {
-		fmt.Println(util.FormatError("spinner error: %w"), err)
-		return nil
-	}
-
-	// Get modloader
-	modloaderOrder := []string{"fabric", "forge", "quilt", "neoforge", "liteloader"}
-	var availableModloaderNames []string
-
-	for _, name := range modloaderOrder {
-		if version, exists := allModloaderVersions[name]; exists {
-			if name == "minecraft" {
+		// Download files listed in the index that did not become content entries into overrides
+		var jobs []download.Job
+		for _, file := range pack.Files {
+			if _, found := versions[packFileHash(file.Hashes)]; found {
 				continue
 			}
-			if strings.HasPrefix(version, "error:") {
+			if len(file.Downloads) == 0 || !filepath.IsLocal(filepath.FromSlash(file.Path)) {
+				fmt.Printf(util.FormatWarning("skipping %s: no usable download\n"), file.Path)
 				continue
 			}
-			availableModloaderNames = append(availableModloaderNames, name)
+			jobs = append(jobs, download.Job{
+				Name: filepath.Base(file.Path),
+				URL:  file.Downloads[0],
+				Dest: filepath.Join(projectData.Root, "overrides", filepath.FromSlash(file.Path)),
+				Size: file.FileSize,
+				Hashes: project.Hashes{
+					Sha1:   file.Hashes["sha1"],
+					Sha512: file.Hashes["sha512"],
+				},
+			})
 		}
-	}
-
-	modloaderForm := huh.NewForm(
-		huh.NewGroup(
-			huh.NewSelect[string]().
-				Title("modloader").
-				Description("choose the modloader used by this instance").
-				Options(huh.NewOptions(availableModloaderNames...)...).
-				Value(&selectedModloader),
-		),
-	)
-
-	if err := modloaderForm.Run(); err != nil {
-		fmt.Println(util.FormatError("prompt failed: %w"), err)
-		return nil
-	}
-
-	if selectedModloader == "" {
-		selectedModloader = "fabric"
-	}
-
-	fmt.Printf("\ngame version: %s\n", gameVersion)
-	fmt.Printf("modloader: %s %s\n\n", selectedModloader, allModloaderVersions[selectedModloader])
-
-	// Hash mods and lookup on Modrinth
-	fmt.Println("analyzing mods...")
-
-	// First, hash all mods with progress bar
-	var hashes []string
-	hashToFile := make(map[string]string)
-	var hashingErrors []string
-
-	if len(modFiles) > 0 {
-		prog := newImportProgress(len(modFiles), "hashing mod files...")
-		p := tea.NewProgram(prog)
-
-		go func() {
-			for i, modFile := range modFiles {
-				filename := filepath.Base(modFile)
-
-				hash, err := calculateSHA512(modFile)
-				if err != nil {
-					hashingErrors = append(hashingErrors, fmt.Sprintf("failed to hash %s: %s", filename, err))
-					p.Send(hashingProgressMsg{current: i + 1, total: len(modFiles), err: err})
-					continue
-				}
-
-				hashes = append(hashes, hash)
-				hashToFile[hash] = filename
-				p.Send(hashingProgressMsg{current: i + 1, total: len(modFiles), name: filename})
+		if len(jobs) > 0 {
+			fmt.Printf("\ndownloading %d files into overrides...\n", len(jobs))
+			manager.Project = projectData.Root
+			results, err := manager.Run(ctx, jobs)
+			if err != nil {
+				return err
 			}
-			p.Quit()
-		}()
-
-		if _, err := p.Run(); err != nil {
-			fmt.Printf(util.FormatError("hashing failed: %v\n"), err)
-			return nil
-		}
-	}
-
-	// Report any hashing errors
-	for _, errMsg := range hashingErrors {
-		fmt.Println(util.FormatWarning(errMsg))
-	}
-
-	// Batch lookup all hashes
-	fmt.Printf("\nlooking up %d mods on modrinth...\n", len(hashes))
-
-	var versions map[string]*modrinth.Version
-	var lookupErr error
-
-	err = spinner.New().
-		Title("looking up mods on modrinth...").
-		Type(spinner.Dots).
-		Action(func() {
-			versions, lookupErr = mymodrinth.ModrinthClient.VersionFiles.GetFromHashes(hashes, "sha512")
-		}).
-		Run()
-
-	if err != nil {
-		fmt.Printf(util.FormatError("spinner error: %v\n"), err)
-		return nil
-	}
-	if lookupErr != nil {
-		fmt.Printf(util.FormatError("failed to lookup mods: %v\n"), lookupErr)
-		return nil
-	}
-
-	if len(versions) == 0 {
-		fmt.Println(util.FormatError("no mods found on modrinth"))
-		return nil
-	}
-
-	// Collect project IDs for bulk lookup
-	projectIDs := make([]string, 0, len(versions))
-	versionToProject := make(map[string]string)
-
-	for hash, version := range versions {
-		if version.ProjectID != nil {
-			projectID := *version.ProjectID
-			projectIDs = append(projectIDs, projectID)
-			versionToProject[hash] = projectID
-		}
-	}
-
-	// Bulk fetch project data
-	var projects map[string]*modrinth.Project
-	var projectFetchErr error
-
-	err = spinner.New().
-		Title("fetching project details...").
-		Type(spinner.Dots).
-		Action(func() {
-			projects, projectFetchErr = bulkFetchProjects(projectIDs)
-		}).
-		Run()
-
-	if err != nil {
-		fmt.Printf(util.FormatError("spinner error: %v\n"), err)
-		return nil
-	}
-	if projectFetchErr != nil {
-		return fmt.Errorf("failed to fetch project details: %w", projectFetchErr)
-	}
-
-	var foundMods []string
-	var notFoundMods []string
-
-	for _, hash := range hashes {
-		filename := hashToFile[hash]
-		if version, found := versions[hash]; found {
-			if version.ProjectID != nil {
-				foundMods = append(foundMods, *version.ProjectID)
+			for _, failed := range download.Failed(results) {
+				fmt.Printf(util.FormatWarning("failed to download %s: %s\n"), failed.Job.Name, failed.Err)
 			}
-		} else {
-			notFoundMods = append(notFoundMods, filename)
 		}
-	}
-
-	fmt.Printf("\nanalysis complete:\n")
-	fmt.Printf("- found on modrinth: %d\n", len(foundMods))
-	fmt.Printf("- not found: %d\n", len(notFoundMods))
-
-	if len(notFoundMods) > 0 {
-		fmt.Printf("\nmods not found on modrinth:\n")
-		for _, mod := range notFoundMods {
-			fmt.Printf("  - %s\n", mod)
-		}
-	}
-
-	if len(foundMods) == 0 {
-		fmt.Println(util.FormatError("no mods found on modrinth to import"))
-		return nil
-	}
-
-	// Ask for project details
-	var name, description, author string
-
-	metaForm := huh.NewForm(
-		huh.NewGroup(
-			huh.NewInput().
-				Title("project name").
-				Description("name for the imported project").
-				Placeholder("imported-pack").
-				Value(&name),
-			huh.NewText().
-				Title("description").
-				Description("description for the imported project").
-				Lines(3).
-				Placeholder("imported from minecraft instance").
-				Value(&description),
-			huh.NewInput().
-				Title("author").
-				Description("author of the project").
-				Placeholder("unknown").
-				Value(&author),
-		),
-	)
-
-	if err := metaForm.Run(); err != nil {
-		return fmt.Errorf("prompt failed: %w", err)
-	}
-
-	if name == "" {
-		name = "imported-pack"
-	}
-	if description == "" {
-		description = "imported from minecraft instance"
-	}
-	if author == "" {
-		author = "unknown"
-	}
 
-	// Create project structure
-	cwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get current working directory: %w", err)
-	}
-
-	projectData := project.Project{
-		Name:          name,
-		Description:   description,
-		Author:        author,
-		DefaultSource: "modrinth",
-		Root:          filepath.Join(cwd, strings.ReplaceAll(name, " ", "-")),
-		Versions: project.ProjectVersions{
-			Game: gameVersion,
-			Loader: project.ModloaderVersion{
-				Name:    selectedModloader,
-				Version: allModloaderVersions[selectedModloader],
-			},
-			Minepack: version.Version,
-		},
-	}
-
-	// Write project files
-	if err := project.WriteProject(&projectData); err != nil {
-		return fmt.Errorf("failed to write project files: %w", err)
-	}
-
-	fmt.Printf("\nproject created at: %s\n", projectData.Root)
-
-	// Create content files for found mods
-	var contentCreationErrors []string
-	var createdCount int
-
-	if len(foundMods) > 0 {
-		prog := newImportProgress(len(hashes), "Creating content files...")
-		p := tea.NewProgram(prog)
-
-		go func() {
-			current := 0
-			for _, hash := range hashes {
-				if version, found := versions[hash]; found && version.ProjectID != nil {
-					projectID := *version.ProjectID
-
-					// Get project data for content creation
-					if project, exists := projects[projectID]; exists {
-						// Convert project to content data
-						contentData := mymodrinth.ConvertProjectToContentData(project, version)
-
-						// Add content to project (this updates content.mp.sum.yaml)
-						if err := projectData.AddContent(contentData); err != nil {
-							projectTitle := projectID
-							if project.Title != nil {
-								projectTitle = *project.Title
-							}
-							contentCreationErrors = append(contentCreationErrors, fmt.Sprintf("failed to add content for %s: %v", projectTitle, err))
-						} else {
-							createdCount++
-						}
-					}
-				}
-				current++
-				p.Send(contentCreationProgressMsg{current: current, total: len(hashes)})
-			}
-			p.Quit()
-		}()
-
-		if _, err := p.Run(); err != nil {
-			fmt.Printf(util.FormatError("content creation failed: %v\n"), err)
-			return nil
+		if err := projectData.commit(ctx, upstream); err != nil {
+			return err
 		}
 
-		fmt.Printf("created %d content files\n", createdCount)
-
-		// Report any content creation errors
-		for _, errMsg := range contentCreationErrors {
-			fmt.Println(util.FormatWarning(errMsg))
+		fmt.Printf("\nsuccessfully imported modpack!\n")
+		fmt.Printf("- modrinth mods: %d\n", len(foundMods))
+		if len(notFoundFiles) > 0 {
+			fmt.Printf("- override mods: %d\n", len(notFoundFiles))
 		}
-	}
-
-	// Copy unfound mods to overrides folder
-	if len(notFoundMods) > 0 {
-		fmt.Printf("\ncopying %d unfound mods to overrides...\n", len(notFoundMods))
-
-		overridesModsPath := filepath.Join(projectData.Root, "overrides", "mods")
-		if err := os.MkdirAll(overridesModsPath, os.ModePerm); err != nil {
-			fmt.Printf(util.FormatWarning("failed to create overrides/mods directory: %v\n"), err)
-		} else {
-			var copyErrors []string
-			var copiedCount int
-
-			prog := newImportProgress(len(notFoundMods), "copying mods to overrides...")
-			p := tea.NewProgram(prog)
-
-			go func() {
-				for i, filename := range notFoundMods {
-					// Find the original file path
-					var originalPath string
-					for _, modFile := range modFiles {
-						if filepath.Base(modFile) == filename {
-							originalPath = modFile
-							break
-						}
-					}
-
-					if originalPath != "" {
-						destPath := filepath.Join(overridesModsPath, filename)
-
-						// Copy the file
-						sourceFile, err := os.Open(originalPath)
-						if err != nil {
-							copyErrors = append(copyErrors, fmt.Sprintf("failed to open %s: %v", filename, err))
-							p.Send(copyProgressMsg{current: i + 1, total: len(notFoundMods), err: err})
-							continue
-						}
-
-						destFile, err := os.Create(destPath)
-						if err != nil {
-							sourceFile.Close()
-							copyErrors = append(copyErrors, fmt.Sprintf("failed to create %s: %v", destPath, err))
-							p.Send(copyProgressMsg{current: i + 1, total: len(notFoundMods), err: err})
-							continue
-						}
-
-						_, err = io.Copy(destFile, sourceFile)
-						sourceFile.Close()
-						destFile.Close()
-
-						if err != nil {
-							copyErrors = append(copyErrors, fmt.Sprintf("failed to copy %s: %v", filename, err))
-						} else {
-							copiedCount++
-						}
-					}
-					p.Send(copyProgressMsg{current: i + 1, total: len(notFoundMods), name: filename})
-				}
-				p.Quit()
-			}()
-
-			if _, err := p.Run(); err != nil {
-				fmt.Printf(util.FormatError("copying failed: %v\n"), err)
-			} else {
-				fmt.Printf("copied %d mods to overrides\n", copiedCount)
-			}
-
-			// Report any copy errors
-			for _, errMsg := range copyErrors {
-				fmt.Println(util.FormatWarning(errMsg))
-			}
+		if len(overrideFiles) > 0 {
+			fmt.Printf("- override files: %d\n", len(overrideFiles))
 		}
-	}
-
-	fmt.Printf("\nsuccessfully imported project with %d mods!\n", len(foundMods))
-	if len(notFoundMods) > 0 {
-		fmt.Printf("+ %d mods copied to overrides (not found on Modrinth)\n", len(notFoundMods))
-	}
-
-	// Automatically link the project to the source instance
-	fmt.Println("\nlinking project to source instance...")
-	absInstancePath, err := filepath.Abs(instancePath)
-	if err != nil {
-		fmt.Printf(util.FormatWarning("warning: failed to get absolute path for linking: %v\n"), err)
 	} else {
-		// Load existing linked folders
-		linked, err := loadLinkedFolders(projectData.Root)
-		if err != nil {
-			fmt.Printf(util.FormatWarning("warning: failed to load linked folders: %v\n"), err)
-		} else {
-			// Check if already linked (shouldn't be, but just in case)
-			alreadyLinked := false
-			for _, existingPath := range linked.Links {
-				if existingPath == absInstancePath {
-					alreadyLinked = true
-					break
-				}
-			}
-
-			if !alreadyLinked {
-				// Add the new link
-				linked.Links = append(linked.Links, absInstancePath)
-
-				// Save the updated list
-				if err := saveLinkedFolders(projectData.Root, linked); err != nil {
-					fmt.Printf(util.FormatWarning("warning: failed to save link: %v\n"), err)
-				} else {
-					fmt.Printf(util.FormatSuccess("linked to instance: %s\n"), absInstancePath)
-				}
-			}
-		}
+		fmt.Println("no mod files found in pack")
 	}
 
-	// Helpful message about getting overrides
-	fmt.Printf("\nrun 'minepack link get-overrides' to copy any custom files\n")
-	fmt.Printf("   (configs, resource packs, etc.) from the instance to your project\n")
-
 	return nil
 }
 
@@ -1194,11 +876,34 @@ func importMinecraftInstance(instancePath string) error {
 var importCmd = &cobra.Command{
 	Use:   "import [path]",
 	Short: "import a modpack from various formats",
-	Long:  `import a modpack from modrinth pack format or minecraft instance folder`,
-	Args:  cobra.ExactArgs(1),
+	Long: `import a modpack from a modrinth pack (.mrpack), a curseforge modpack zip, a packwiz pack (its pack.toml or the folder containing it) or a minecraft instance folder
+
+published modpacks are imported with "modrinth:<slug>[@version]" or "curseforge:<id>[@fileId]",
+the newest version is used when none is given. the pack is remembered as the project's upstream`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		importPath := args[0]
 
+		// a published modpack, e.g. modrinth:fabulously-optimized@6.0.0
+		if source, ref, ok := strings.Cut(importPath, ":"); ok && (source == "modrinth" || source == "curseforge") {
+			if _, err := os.Stat(importPath); os.IsNotExist(err) {
+				if !requireOnline("import") {
+					return
+				}
+				fmt.Printf("importing %s modpack...\n", source)
+				if err := importPublishedPack(cmd.Context(), newDownloadManager(cmd, ""), source, ref); err != nil {
+					if errors.Is(err, context.Canceled) {
+						fmt.Print(util.FormatWarning("interrupted, nothing was imported\n"))
+						return
+					}
+					fmt.Printf(util.FormatError("failed to import pack: %s\n"), err)
+					return
+				}
+				fmt.Print(util.FormatSuccess("import completed!"))
+				return
+			}
+		}
+
 		// Check if path exists
 		if _, err := os.Stat(importPath); os.IsNotExist(err) {
 			fmt.Printf(util.FormatError("path does not exist: %s\n"), importPath)
@@ -1212,21 +917,47 @@ var importCmd = &cobra.Command{
 			return
 		}
 
-		if info.IsDir() {
+		if isPackwizPack(importPath, info) {
+			fmt.Println("importing packwiz pack...")
+			if err := importPackwizPack(cmd.Context(), importPath); err != nil {
+				if errors.Is(err, context.Canceled) {
+					fmt.Print(util.FormatWarning("interrupted, nothing was imported\n"))
+					return
+				}
+				fmt.Printf(util.FormatError("failed to import pack: %s\n"), err)
+				return
+			}
+		} else if info.IsDir() {
 			// Assume it's a minecraft instance
 			fmt.Println("importing minecraft instance...")
-			if err := importMinecraftInstance(importPath); err != nil {
+			if err := importMinecraftInstance(cmd.Context(), importPath); err != nil {
+				if errors.Is(err, context.Canceled) {
+					fmt.Print(util.FormatWarning("interrupted, nothing was imported\n"))
+					return
+				}
 				fmt.Printf(util.FormatError("failed to import instance: %s\n"), err)
 				return
 			}
 		} else {
-			// Assume it's a modrinth pack file
-			if !strings.HasSuffix(strings.ToLower(importPath), ".mrpack") {
-				fmt.Print(util.FormatWarning("warning: file doesn't have .mrpack extension\n"))
+			// Tell the pack format apart by its manifest
+			format, err := packArchiveFormat(importPath)
+			if err != nil {
+				fmt.Printf(util.FormatError("failed to import pack: %s\n"), err)
+				return
 			}
 
-			fmt.Println("importing modrinth pack...")
-			if err := importModrinthPack(importPath); err != nil {
+			if format == "curseforge" {
+				fmt.Println("importing curseforge pack...")
+				err = importCurseforgePack(cmd.Context(), importPath, nil)
+			} else {
+				fmt.Println("importing modrinth pack...")
+				err = importModrinthPack(cmd.Context(), newDownloadManager(cmd, ""), importPath, nil)
+			}
+			if err != nil {
+				if errors.Is(err, context.Canceled) {
+					fmt.Print(util.FormatWarning("interrupted, nothing was imported\n"))
+					return
+				}
 				fmt.Printf(util.FormatError("failed to import pack: %s\n"), err)
 				return
 			}
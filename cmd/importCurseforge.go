@@ -0,0 +1,210 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"minepack/core/api/curseforge"
+	"minepack/core/project"
+	"minepack/util"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/huh/spinner"
+)
+
+// importCurseforgePack imports a CurseForge modpack zip. every file in its manifest becomes
+// curseforge content, including mods whose authors turned off downloads outside of curseforge.
+// upstream is recorded in the project when the zip was downloaded from a published modpack
+func importCurseforgePack(ctx context.Context, packPath string, upstream *project.Upstream) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Create temporary directory for extraction
+	tempDir, err := os.MkdirTemp("", "minepack-cfpack-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir) // Clean up when done
+
+	fmt.Printf("extracting modpack archive...\n")
+
+	if err := extractArchive(ctx, packPath, tempDir); err != nil {
+		return err
+	}
+
+	// Read the manifest.json file
+	manifestData, err := os.ReadFile(filepath.Join(tempDir, "manifest.json"))
+	if err != nil {
+		return fmt.Errorf("failed to read manifest.json: %w", err)
+	}
+
+	var manifest curseforgeManifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return fmt.Errorf("failed to parse manifest.json: %w", err)
+	}
+	if manifest.ManifestType != "" && manifest.ManifestType != "minecraftModpack" {
+		return fmt.Errorf("manifest.json is a %s, not a minecraft modpack", manifest.ManifestType)
+	}
+
+	loader := curseforgeModLoaderVersion(manifest.Minecraft.ModLoaders)
+
+	fmt.Printf("importing curseforge pack: %s\n", manifest.Name)
+	fmt.Printf("game version: %s\n", manifest.Minecraft.Version)
+	fmt.Printf("modloader: %s %s\n", loader.Name, loader.Version)
+	fmt.Printf("files: %d\n\n", len(manifest.Files))
+
+	// Bulk fetch every project and file in the manifest, and the projects they depend on
+	var modIDs, fileIDs []int
+	for _, file := range manifest.Files {
+		modIDs = append(modIDs, file.ProjectID)
+		fileIDs = append(fileIDs, file.FileID)
+	}
+
+	var mods map[int]*curseforge.Mod
+	var files map[int]*curseforge.File
+	var fetchErr error
+
+	err = spinner.New().
+		Title("fetching project details from curseforge...").
+		Type(spinner.Dots).
+		Context(ctx).
+		Action(func() {
+			mods, fetchErr = curseforge.GetMods(ctx, modIDs)
+			if fetchErr != nil {
+				return
+			}
+			files, fetchErr = curseforge.GetFiles(ctx, fileIDs)
+			if fetchErr != nil {
+				return
+			}
+			list := make([]*curseforge.File, 0, len(files))
+			for _, file := range files {
+				list = append(list, file)
+			}
+			fetchErr = curseforge.PrefetchDependencies(ctx, list)
+		}).
+		Run()
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		fmt.Printf(util.FormatError("spinner error: %v\n"), err)
+		return fmt.Errorf("fetch failed: %w", err)
+	}
+	if fetchErr != nil {
+		return fmt.Errorf("failed to fetch project details: %w", fetchErr)
+	}
+
+	// files curseforge no longer knows about can't be imported
+	var found []curseforgeFile
+	var missing []string
+	for _, file := range manifest.Files {
+		if mods[file.ProjectID] == nil || files[file.FileID] == nil {
+			missing = append(missing, fmt.Sprintf("%d/%d", file.ProjectID, file.FileID))
+			continue
+		}
+		found = append(found, file)
+	}
+
+	fmt.Printf("\nanalysis complete:\n")
+	fmt.Printf("- found on curseforge: %d\n", len(found))
+	fmt.Printf("- not found: %d\n", len(missing))
+	if len(missing) > 0 {
+		fmt.Printf(util.FormatWarning("warning: these projects/files no longer exist on curseforge and are skipped: %s\n"), strings.Join(missing, ", "))
+	}
+
+	// mods without a download url are recorded too and downloaded from curseforge's cdn later, mods
+	// the manifest doesn't require stay optional
+	var contents []project.ContentData
+	var appOnly []string
+	for _, file := range found {
+		contentData := curseforge.ConvertModToContentData(ctx, mods[file.ProjectID], files[file.FileID])
+		if contentData.DownloadUrl == "" {
+			appOnly = append(appOnly, contentData.Slug)
+		}
+		if !file.Required {
+			contentData.Side = optionalSide(contentData.Side)
+		}
+		contents = append(contents, contentData)
+	}
+
+	projectData, err := createImportedProject(ctx, cancel, importDefaults{
+		name:          manifest.Name,
+		author:        manifest.Author,
+		origin:        "curseforge pack",
+		defaultSource: "curseforge",
+		game:          manifest.Minecraft.Version,
+		loader:        loader,
+	}, contents)
+	if err != nil {
+		return err
+	}
+	defer projectData.discardIfInterrupted(ctx)
+
+	if len(appOnly) > 0 {
+		fmt.Printf(util.FormatWarning("warning: %d mods have downloads outside of curseforge turned off, they were recorded without a download url: %s\n"), len(appOnly), strings.Join(appOnly, ", "))
+	}
+
+	// Copy overrides folder from extracted archive if it exists
+	overridesFolder := manifest.Overrides
+	if overridesFolder == "" {
+		overridesFolder = "overrides"
+	}
+	archiveOverridesPath := filepath.Join(tempDir, filepath.FromSlash(overridesFolder))
+	if _, err := os.Stat(archiveOverridesPath); err == nil && filepath.IsLocal(filepath.FromSlash(overridesFolder)) {
+		projectOverridesPath := filepath.Join(projectData.Root, project.OverridesFolder)
+
+		err = spinner.New().
+			Title("copying overrides from archive...").
+			Type(spinner.Dots).
+			Action(func() {
+				err = copyDirectory(archiveOverridesPath, projectOverridesPath)
+			}).
+			Run()
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			fmt.Printf(util.FormatWarning("failed to copy overrides from archive: %v\n"), err)
+		} else {
+			fmt.Println("copied overrides from archive")
+		}
+	}
+
+	if err := projectData.commit(ctx, upstream); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nsuccessfully imported modpack!\n")
+	fmt.Printf("- curseforge mods: %d\n", projectData.created)
+	if len(missing) > 0 {
+		fmt.Printf("- skipped: %d\n", len(missing))
+	}
+
+	return nil
+}
+
+// curseforgeModLoaderVersion reads the pack's loader from the manifest's mod loaders, ids of the
+// form "fabric-0.16.5". the primary loader is used when several are listed, and vanilla packs
+// list none
+func curseforgeModLoaderVersion(modLoaders []curseforgeModLoader) project.ModloaderVersion {
+	var id string
+	for _, modLoader := range modLoaders {
+		if id == "" || modLoader.Primary {
+			id = modLoader.ID
+		}
+	}
+	if id == "" {
+		return project.ModloaderVersion{}
+	}
+
+	name, loaderVersion, _ := strings.Cut(id, "-")
+	return project.ModloaderVersion{Name: strings.ToLower(name), Version: loaderVersion}
+}
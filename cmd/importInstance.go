@@ -0,0 +1,729 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"minepack/core"
+	"minepack/core/api"
+	"minepack/core/api/curseforge"
+	"minepack/core/project"
+	"minepack/util"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/unascribed/FlexVer/go/flexver"
+)
+
+// instanceJar is a mod jar found in an instance, with every hash it can be identified by
+type instanceJar struct {
+	path        string
+	filename    string
+	size        int64
+	sha1        string
+	sha512      string
+	fingerprint uint32 // curseforge's murmur2 fingerprint
+}
+
+// importMinecraftInstance imports a Minecraft instance. its jars are identified on modrinth and
+// curseforge by hash, and the ones neither knows become custom content kept in the overrides
+func importMinecraftInstance(ctx context.Context, instancePath string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Check if it's a valid minecraft instance
+	gameDir, ok := instanceGameDir(instancePath)
+	if !ok {
+		fmt.Println(util.FormatError("not a valid minecraft instance: mods folder not found"))
+		return nil
+	}
+
+	// Get list of mod files
+	modFiles, err := filepath.Glob(filepath.Join(gameDir, "mods", "*.jar"))
+	if err != nil {
+		fmt.Printf(util.FormatError("failed to list mod files: %v\n"), err)
+		return nil
+	}
+
+	if len(modFiles) == 0 {
+		fmt.Println(util.FormatError("no mod files found in instance"))
+		return nil
+	}
+
+	fmt.Printf("found %d mod files in instance\n", len(modFiles))
+
+	// Read the versions from the launcher's metadata, only asking for what it doesn't say
+	gameVersion, loader := detectInstanceVersions(instancePath, gameDir)
+	if gameVersion != "" {
+		fmt.Printf("detected game version: %s\n", gameVersion)
+	}
+	if loader.Name != "" {
+		fmt.Printf("detected modloader: %s %s\n", loader.Name, loader.Version)
+	}
+
+	if gameVersion == "" {
+		// Fetch minecraft versions for validation
+		var allGameVersions *core.MinecraftManifest
+		var fetchErr error
+
+		err = spinner.New().
+			Title("fetching minecraft versions...").
+			Type(spinner.Dots).
+			Context(ctx).
+			Action(func() {
+				allGameVersions, fetchErr = core.FetchMinecraftVersions()
+			}).
+			Run()
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			fmt.Printf(util.FormatError("spinner error: %v\n"), err)
+			return nil
+		}
+		if fetchErr != nil {
+			fmt.Printf(util.FormatError("failed to fetch minecraft versions: %v\n"), fetchErr)
+			return nil
+		}
+
+		var allGameVersionsFlat []string
+		for _, v := range allGameVersions.Versions {
+			allGameVersionsFlat = append(allGameVersionsFlat, v.ID)
+		}
+
+		// Get game version
+		versionForm := huh.NewForm(
+			huh.NewGroup(
+				huh.NewInput().
+					Title("game version").
+					Description("enter minecraft version for this instance").
+					Placeholder("1.20.1").
+					Suggestions(allGameVersionsFlat).
+					Value(&gameVersion),
+			),
+		)
+
+		if err := versionForm.Run(); err != nil {
+			fmt.Printf(util.FormatError("prompt failed: %v\n"), err)
+			return nil
+		}
+
+		if gameVersion == "" {
+			gameVersion = "1.20.1"
+		}
+	}
+
+	if loader.Name == "" {
+		// Fetch modloader versions
+		var allModloaderVersions map[string]string
+
+		err = spinner.New().
+			Title("fetching modloader versions...").
+			Type(spinner.Dots).
+			Context(ctx).
+			Action(func() {
+				allModloaderVersions = core.GetAllLatestVersions(gameVersion)
+			}).
+			Run()
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			fmt.Printf(util.FormatError("spinner error: %v\n"), err)
+			return nil
+		}
+
+		// Get modloader
+		modloaderOrder := []string{"fabric", "forge", "quilt", "neoforge", "liteloader"}
+		var availableModloaderNames []string
+
+		for _, name := range modloaderOrder {
+			if version, exists := allModloaderVersions[name]; exists {
+				if strings.HasPrefix(version, "error:") {
+					continue
+				}
+				availableModloaderNames = append(availableModloaderNames, name)
+			}
+		}
+
+		modloaderForm := huh.NewForm(
+			huh.NewGroup(
+				huh.NewSelect[string]().
+					Title("modloader").
+					Description("choose the modloader used by this instance").
+					Options(huh.NewOptions(availableModloaderNames...)...).
+					Value(&loader.Name),
+			),
+		)
+
+		if err := modloaderForm.Run(); err != nil {
+			fmt.Printf(util.FormatError("prompt failed: %v\n"), err)
+			return nil
+		}
+
+		if loader.Name == "" {
+			loader.Name = "fabric"
+		}
+		loader.Version = allModloaderVersions[loader.Name]
+	} else if loader.Version == "" {
+		// the loader is known but not its version, use the newest one
+		var fetchErr error
+		err = spinner.New().
+			Title("fetching modloader version...").
+			Type(spinner.Dots).
+			Context(ctx).
+			Action(func() {
+				loader.Version, fetchErr = core.GetLatestModLoaderVersion(loader.Name, gameVersion)
+			}).
+			Run()
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			fmt.Printf(util.FormatError("spinner error: %v\n"), err)
+			return nil
+		}
+		if fetchErr != nil {
+			return fmt.Errorf("failed to fetch the latest %s version for minecraft %s: %w", loader.Name, gameVersion, fetchErr)
+		}
+	}
+
+	fmt.Printf("\ngame version: %s\n", gameVersion)
+	fmt.Printf("modloader: %s %s\n\n", loader.Name, loader.Version)
+
+	// Hash mods so they can be identified
+	fmt.Println("analyzing mods...")
+
+	var jars []instanceJar
+	var hashingErrors []string
+
+	prog := newImportProgress(len(modFiles), "hashing mod files...")
+	err = runProgress(ctx, cancel, prog, func(p *tea.Program) {
+		for i, modFile := range modFiles {
+			if ctx.Err() != nil {
+				return
+			}
+			jar, err := hashInstanceJar(modFile)
+			if err != nil {
+				hashingErrors = append(hashingErrors, fmt.Sprintf("failed to hash %s: %s", filepath.Base(modFile), err))
+				p.Send(hashingProgressMsg{current: i + 1, total: len(modFiles), err: err})
+				continue
+			}
+			jars = append(jars, jar)
+			p.Send(hashingProgressMsg{current: i + 1, total: len(modFiles), name: jar.filename})
+		}
+	})
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		fmt.Printf(util.FormatError("hashing failed: %v\n"), err)
+		return nil
+	}
+
+	// Report any hashing errors
+	for _, errMsg := range hashingErrors {
+		fmt.Println(util.FormatWarning(errMsg))
+	}
+
+	// Identify the jars on modrinth and curseforge
+	var identified map[string]project.ContentData
+	var lookupErrors []string
+
+	err = spinner.New().
+		Title(fmt.Sprintf("identifying %d mods...", len(jars))).
+		Type(spinner.Dots).
+		Context(ctx).
+		Action(func() {
+			identified, lookupErrors = identifyInstanceJars(ctx, jars)
+		}).
+		Run()
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		fmt.Printf(util.FormatError("spinner error: %v\n"), err)
+		return nil
+	}
+	for _, errMsg := range lookupErrors {
+		fmt.Println(util.FormatWarning(errMsg))
+	}
+
+	var modrinthCount, curseforgeCount int
+	var customJars []instanceJar
+	for _, jar := range jars {
+		content, found := identified[jar.filename]
+		switch {
+		case !found:
+			customJars = append(customJars, jar)
+		case content.Source == project.Curseforge:
+			curseforgeCount++
+		default:
+			modrinthCount++
+		}
+	}
+
+	fmt.Printf("\nanalysis complete:\n")
+	fmt.Printf("- found on modrinth: %d\n", modrinthCount)
+	fmt.Printf("- found on curseforge: %d\n", curseforgeCount)
+	fmt.Printf("- not found: %d\n", len(customJars))
+
+	if len(customJars) > 0 {
+		fmt.Printf("\nmods not found on modrinth or curseforge, they are imported as custom mods:\n")
+		for _, jar := range customJars {
+			fmt.Printf("  - %s\n", jar.filename)
+		}
+	}
+
+	// unidentified jars become custom content
+	contents := make([]project.ContentData, 0, len(jars))
+	for _, jar := range jars {
+		contentData, found := identified[jar.filename]
+		if !found {
+			contentData = instanceCustomContent(jar)
+		}
+		contents = append(contents, contentData)
+	}
+
+	defaultSource := "modrinth"
+	if curseforgeCount > modrinthCount {
+		defaultSource = "curseforge"
+	}
+
+	projectData, err := createImportedProject(ctx, cancel, importDefaults{
+		origin:        "minecraft instance",
+		defaultSource: defaultSource,
+		game:          gameVersion,
+		loader:        loader,
+	}, contents)
+	if err != nil {
+		return err
+	}
+	defer projectData.discardIfInterrupted(ctx)
+
+	// Copy custom mods to the overrides folder, where their content files expect them
+	if len(customJars) > 0 {
+		fmt.Printf("\ncopying %d custom mods to overrides...\n", len(customJars))
+
+		overridesModsPath := filepath.Join(projectData.Root, project.OverridesFolder, "mods")
+		if err := os.MkdirAll(overridesModsPath, os.ModePerm); err != nil {
+			fmt.Printf(util.FormatWarning("failed to create overrides/mods directory: %v\n"), err)
+		} else {
+			var copyErrors []string
+			var copiedCount int
+
+			prog := newImportProgress(len(customJars), "copying mods to overrides...")
+			err := runProgress(ctx, cancel, prog, func(p *tea.Program) {
+				for i, jar := range customJars {
+					if ctx.Err() != nil {
+						return
+					}
+					if err := copyFile(jar.path, filepath.Join(overridesModsPath, jar.filename)); err != nil {
+						copyErrors = append(copyErrors, fmt.Sprintf("failed to copy %s: %v", jar.filename, err))
+						p.Send(copyProgressMsg{current: i + 1, total: len(customJars), err: err})
+						continue
+					}
+					copiedCount++
+					p.Send(copyProgressMsg{current: i + 1, total: len(customJars), name: jar.filename})
+				}
+			})
+
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil {
+				fmt.Printf(util.FormatError("copying failed: %v\n"), err)
+			} else {
+				fmt.Printf("copied %d mods to overrides\n", copiedCount)
+			}
+
+			// Report any copy errors
+			for _, errMsg := range copyErrors {
+				fmt.Println(util.FormatWarning(errMsg))
+			}
+		}
+	}
+
+	if err := projectData.commit(ctx, nil); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nsuccessfully imported project with %d mods!\n", projectData.created)
+	fmt.Printf("- modrinth mods: %d\n", modrinthCount)
+	fmt.Printf("- curseforge mods: %d\n", curseforgeCount)
+	if len(customJars) > 0 {
+		fmt.Printf("- custom mods: %d (kept in overrides)\n", len(customJars))
+	}
+
+	// Automatically link the project to the instance's game folder
+	fmt.Println("\nlinking project to source instance...")
+	absInstancePath, err := filepath.Abs(gameDir)
+	if err != nil {
+		fmt.Printf(util.FormatWarning("warning: failed to get absolute path for linking: %v\n"), err)
+	} else {
+		// Load existing linked folders
+		linked, err := loadLinkedFolders(projectData.Root)
+		if err != nil {
+			fmt.Printf(util.FormatWarning("warning: failed to load linked folders: %v\n"), err)
+		} else {
+			// Check if already linked (shouldn't be, but just in case)
+			alreadyLinked := false
+			for _, existingPath := range linked.Links {
+				if existingPath == absInstancePath {
+					alreadyLinked = true
+					break
+				}
+			}
+
+			if !alreadyLinked {
+				// Add the new link
+				linked.Links = append(linked.Links, absInstancePath)
+
+				// Save the updated list
+				if err := saveLinkedFolders(projectData.Root, linked); err != nil {
+					fmt.Printf(util.FormatWarning("warning: failed to save link: %v\n"), err)
+				} else {
+					fmt.Printf(util.FormatSuccess("linked to instance: %s\n"), absInstancePath)
+				}
+			}
+		}
+	}
+
+	// Helpful message about getting overrides
+	fmt.Printf("\nrun 'minepack link get-overrides' to copy any custom files\n")
+	fmt.Printf("   (configs, resource packs, etc.) from the instance to your project\n")
+
+	return nil
+}
+
+// hashInstanceJar reads a jar and computes the hashes modrinth and curseforge identify it by
+func hashInstanceJar(jarPath string) (instanceJar, error) {
+	data, err := os.ReadFile(jarPath)
+	if err != nil {
+		return instanceJar{}, err
+	}
+	sha1Sum := sha1.Sum(data)
+	sha512Sum := sha512.Sum512(data)
+	return instanceJar{
+		path:        jarPath,
+		filename:    filepath.Base(jarPath),
+		size:        int64(len(data)),
+		sha1:        hex.EncodeToString(sha1Sum[:]),
+		sha512:      hex.EncodeToString(sha512Sum[:]),
+		fingerprint: curseforge.Fingerprint(data),
+	}, nil
+}
+
+// identifyInstanceJars looks jars up on modrinth by sha512 and then sha1, and what's left on
+// curseforge by fingerprint, keyed by filename. a source that can't be reached is reported and
+// skipped, so its jars fall through to the next one
+func identifyInstanceJars(ctx context.Context, jars []instanceJar) (map[string]project.ContentData, []string) {
+	lookups := []struct {
+		source string
+		format project.HashFormat
+		hash   func(jar instanceJar) string
+	}{
+		{"modrinth", project.SHA512, func(jar instanceJar) string { return jar.sha512 }},
+		{"modrinth", project.SHA1, func(jar instanceJar) string { return jar.sha1 }},
+		{"curseforge", project.Murmur2, func(jar instanceJar) string { return strconv.FormatUint(uint64(jar.fingerprint), 10) }},
+	}
+
+	identified := make(map[string]project.ContentData)
+	var lookupErrors []string
+	failed := make(map[string]bool)
+	for _, lookup := range lookups {
+		if failed[lookup.source] {
+			continue
+		}
+
+		var hashes []string
+		hashToFile := make(map[string]string)
+		for _, jar := range jars {
+			if _, done := identified[jar.filename]; done {
+				continue
+			}
+			hash := lookup.hash(jar)
+			hashes = append(hashes, hash)
+			hashToFile[hash] = jar.filename
+		}
+		if len(hashes) == 0 {
+			break
+		}
+
+		provider, err := api.Get(lookup.source)
+		if err != nil {
+			lookupErrors = append(lookupErrors, err.Error())
+			failed[lookup.source] = true
+			continue
+		}
+		found, err := provider.LookupHashes(ctx, hashes, lookup.format)
+		if ctx.Err() != nil {
+			return identified, lookupErrors
+		}
+		if err != nil {
+			lookupErrors = append(lookupErrors, fmt.Sprintf("failed to look mods up on %s: %s", lookup.source, err))
+			failed[lookup.source] = true
+			continue
+		}
+		for hash, content := range found {
+			if filename, ok := hashToFile[strings.ToLower(hash)]; ok {
+				identified[filename] = content
+			}
+		}
+	}
+	return identified, lookupErrors
+}
+
+// instanceCustomContent describes a jar no source knows as custom content. its file is kept in
+// the overrides, so it has no download url
+func instanceCustomContent(jar instanceJar) project.ContentData {
+	name := strings.TrimSuffix(jar.filename, ".jar")
+	slug := strings.Trim(customSlugPattern.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if slug == "" {
+		slug = jar.sha1[:8]
+	}
+	return project.ContentData{
+		ContentType: project.Mod,
+		Name:        name,
+		Id:          slug,
+		Slug:        slug,
+		Side: project.ModSide{
+			Client: project.SideRequired,
+			Server: project.SideRequired,
+		},
+		Source:       project.Custom,
+		Dependencies: []project.Dependency{},
+		File: project.FileData{
+			Filename: jar.filename,
+			Filesize: jar.size,
+			Filepath: path.Join("mods", jar.filename),
+			Hashes: project.Hashes{
+				Sha1:   jar.sha1,
+				Sha512: jar.sha512,
+			},
+		},
+	}
+}
+
+// customSlugPattern matches the characters a slug made from a filename can't contain
+var customSlugPattern = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// instanceGameDir finds the folder holding the instance's mods. launchers like prism keep it in
+// a .minecraft or minecraft folder next to the instance's metadata
+func instanceGameDir(instancePath string) (string, bool) {
+	for _, dir := range []string{instancePath, filepath.Join(instancePath, ".minecraft"), filepath.Join(instancePath, "minecraft")} {
+		if info, err := os.Stat(filepath.Join(dir, "mods")); err == nil && info.IsDir() {
+			return dir, true
+		}
+	}
+	return "", false
+}
+
+// detectInstanceVersions reads the game and loader versions from launcher metadata, or the
+// loader's own files. the instance folder, its game folder and the folder above it are searched,
+// so pointing at either the instance or its .minecraft folder works. empty results weren't found
+func detectInstanceVersions(instancePath, gameDir string) (string, project.ModloaderVersion) {
+	dirs := []string{instancePath, gameDir, filepath.Dir(filepath.Clean(instancePath))}
+	detectors := []func(dir string) (string, project.ModloaderVersion){
+		detectMultiMCPack,
+		detectCurseforgeInstance,
+		detectInstanceConfig,
+		detectLoaderFiles,
+	}
+
+	var gameVersion string
+	var loader project.ModloaderVersion
+	for _, detect := range detectors {
+		for _, dir := range dirs {
+			detectedGame, detectedLoader := detect(dir)
+			if gameVersion == "" {
+				gameVersion = detectedGame
+			}
+			if loader.Name == "" {
+				loader = detectedLoader
+			}
+		}
+	}
+	return gameVersion, loader
+}
+
+// multiMCLoaders maps the component uids in mmc-pack.json to loader names
+var multiMCLoaders = map[string]string{
+	"net.fabricmc.fabric-loader": "fabric",
+	"org.quiltmc.quilt-loader":   "quilt",
+	"net.minecraftforge":         "forge",
+	"net.neoforged":              "neoforge",
+	"com.mumfrey.liteloader":     "liteloader",
+}
+
+// detectMultiMCPack reads the components of a prism launcher or multimc instance
+func detectMultiMCPack(dir string) (string, project.ModloaderVersion) {
+	data, err := os.ReadFile(filepath.Join(dir, "mmc-pack.json"))
+	if err != nil {
+		return "", project.ModloaderVersion{}
+	}
+	var pack struct {
+		Components []struct {
+			UID     string `json:"uid"`
+			Version string `json:"version"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &pack); err != nil {
+		return "", project.ModloaderVersion{}
+	}
+
+	var gameVersion string
+	var loader project.ModloaderVersion
+	for _, component := range pack.Components {
+		if component.UID == "net.minecraft" {
+			gameVersion = component.Version
+		} else if name, ok := multiMCLoaders[component.UID]; ok && loader.Name == "" {
+			loader = project.ModloaderVersion{Name: name, Version: component.Version}
+		}
+	}
+	return gameVersion, loader
+}
+
+// detectCurseforgeInstance reads the minecraftinstance.json the curseforge app writes, whose
+// loader names look like "forge-47.2.0" or "fabric-0.15.7-1.20.1"
+func detectCurseforgeInstance(dir string) (string, project.ModloaderVersion) {
+	data, err := os.ReadFile(filepath.Join(dir, "minecraftinstance.json"))
+	if err != nil {
+		return "", project.ModloaderVersion{}
+	}
+	var instance struct {
+		GameVersion   string `json:"gameVersion"`
+		BaseModLoader *struct {
+			Name string `json:"name"`
+		} `json:"baseModLoader"`
+	}
+	if err := json.Unmarshal(data, &instance); err != nil {
+		return "", project.ModloaderVersion{}
+	}
+
+	var loader project.ModloaderVersion
+	if instance.BaseModLoader != nil {
+		loader = curseforgeModLoaderVersion([]curseforgeModLoader{{ID: instance.BaseModLoader.Name, Primary: true}})
+		loader.Version = strings.TrimSuffix(loader.Version, "-"+instance.GameVersion)
+	}
+	return instance.GameVersion, loader
+}
+
+// detectInstanceConfig reads the game version older multimc instances keep in instance.cfg
+func detectInstanceConfig(dir string) (string, project.ModloaderVersion) {
+	data, err := os.ReadFile(filepath.Join(dir, "instance.cfg"))
+	if err != nil {
+		return "", project.ModloaderVersion{}
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), "IntendedVersion="); ok {
+			return strings.TrimSpace(value), project.ModloaderVersion{}
+		}
+	}
+	return "", project.ModloaderVersion{}
+}
+
+var (
+	// the launcher jar fabric's installer puts in a server folder
+	fabricServerJarPattern = regexp.MustCompile(`^fabric-server-mc\.(.+)-loader\.(.+)-launcher\..+\.jar$`)
+	// the jar older forge installers put in a server folder
+	forgeJarPattern = regexp.MustCompile(`^forge-(\d[^-]*)-([^-]+)(-universal|-server|-shim)?\.jar$`)
+	// the vanilla server jar
+	serverJarPattern = regexp.MustCompile(`^minecraft_server\.(.+)\.jar$`)
+)
+
+// detectLoaderFiles finds the loader from the jars and libraries its installer leaves behind,
+// mostly in server folders
+func detectLoaderFiles(dir string) (string, project.ModloaderVersion) {
+	var gameVersion string
+	var loader project.ModloaderVersion
+
+	if entries, err := os.ReadDir(dir); err == nil {
+		for _, entry := range entries {
+			name := entry.Name()
+			if match := fabricServerJarPattern.FindStringSubmatch(name); match != nil {
+				gameVersion, loader = match[1], project.ModloaderVersion{Name: "fabric", Version: match[2]}
+			} else if match := forgeJarPattern.FindStringSubmatch(name); match != nil && loader.Name == "" {
+				gameVersion, loader = match[1], project.ModloaderVersion{Name: "forge", Version: match[2]}
+			} else if match := serverJarPattern.FindStringSubmatch(name); match != nil && gameVersion == "" {
+				gameVersion = match[1]
+			}
+		}
+	}
+
+	libraries := filepath.Join(dir, "libraries")
+	if loader.Name == "" {
+		if v := newestLibraryVersion(libraries, "net", "fabricmc", "fabric-loader"); v != "" {
+			loader = project.ModloaderVersion{Name: "fabric", Version: v}
+		} else if v := newestLibraryVersion(libraries, "org", "quiltmc", "quilt-loader"); v != "" {
+			loader = project.ModloaderVersion{Name: "quilt", Version: v}
+		} else if v := newestLibraryVersion(libraries, "net", "neoforged", "neoforge"); v != "" {
+			loader = project.ModloaderVersion{Name: "neoforge", Version: v}
+			if gameVersion == "" {
+				gameVersion = neoforgeGameVersion(v)
+			}
+		} else if v := newestLibraryVersion(libraries, "net", "minecraftforge", "forge"); v != "" {
+			// forge library versions are "<game>-<forge>"
+			game, forgeVersion, _ := strings.Cut(v, "-")
+			loader = project.ModloaderVersion{Name: "forge", Version: forgeVersion}
+			if gameVersion == "" {
+				gameVersion = game
+			}
+		}
+	}
+	if gameVersion == "" {
+		gameVersion = newestLibraryVersion(libraries, "net", "minecraft", "server")
+	}
+	return gameVersion, loader
+}
+
+// newestLibraryVersion returns the last version folder of a library in a maven style libraries
+// folder, empty when it isn't there
+func newestLibraryVersion(libraries string, artifact ...string) string {
+	entries, err := os.ReadDir(filepath.Join(append([]string{libraries}, artifact...)...))
+	if err != nil {
+		return ""
+	}
+	var versions []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			versions = append(versions, entry.Name())
+		}
+	}
+	if len(versions) == 0 {
+		return ""
+	}
+	flexver.VersionSlice(versions).Sort()
+	return versions[len(versions)-1]
+}
+
+// neoforgeGameVersion derives the game version from a neoforge version, which starts with the
+// game's minor and patch versions, e.g. 20.4.237 is for 1.20.4 and 21.0.10 for 1.21
+func neoforgeGameVersion(neoforgeVersion string) string {
+	parts := strings.Split(neoforgeVersion, ".")
+	if len(parts) < 2 {
+		return ""
+	}
+	if parts[1] == "0" {
+		return "1." + parts[0]
+	}
+	return "1." + parts[0] + "." + parts[1]
+}
@@ -0,0 +1,410 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"minepack/core/api/curseforge"
+	mymodrinth "minepack/core/api/modrinth"
+	"minepack/core/project"
+	"minepack/util"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"codeberg.org/jmansfield/go-modrinth/modrinth"
+	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/huh/spinner"
+)
+
+// packwizPackFile is the part of a packwiz pack.toml minepack reads
+type packwizPackFile struct {
+	Name        string `toml:"name"`
+	Author      string `toml:"author"`
+	Version     string `toml:"version"`
+	Description string `toml:"description"`
+	Index       struct {
+		File string `toml:"file"`
+	} `toml:"index"`
+	Versions map[string]string `toml:"versions"`
+}
+
+// packwizIndexFile is a packwiz index.toml, listing every file in the pack
+type packwizIndexFile struct {
+	HashFormat string `toml:"hash-format"`
+	Files      []struct {
+		File     string `toml:"file"`
+		Alias    string `toml:"alias"`
+		Metafile bool   `toml:"metafile"`
+	} `toml:"files"`
+}
+
+// packwizMetafileData is a .pw.toml file describing one piece of content
+type packwizMetafileData struct {
+	Name     string `toml:"name"`
+	Filename string `toml:"filename"`
+	Side     string `toml:"side"`
+	Pin      bool   `toml:"pin"`
+	Download struct {
+		URL        string `toml:"url"`
+		HashFormat string `toml:"hash-format"`
+		Hash       string `toml:"hash"`
+	} `toml:"download"`
+	Update struct {
+		Modrinth *struct {
+			ModID   string `toml:"mod-id"`
+			Version string `toml:"version"`
+		} `toml:"modrinth"`
+		Curseforge *struct {
+			FileID    int `toml:"file-id"`
+			ProjectID int `toml:"project-id"`
+		} `toml:"curseforge"`
+	} `toml:"update"`
+	Option struct {
+		Optional bool `toml:"optional"`
+	} `toml:"option"`
+}
+
+// packwizEntry is a metafile along with where it sits in the pack
+type packwizEntry struct {
+	slug   string // the metafile's name without .pw.toml
+	folder string // the metafile's folder, relative to the index
+	meta   packwizMetafileData
+}
+
+// importPackwizPack imports a local packwiz pack from its pack.toml, or a folder containing one.
+// metafiles with modrinth or curseforge update info become content from those sources, the rest
+// become custom content downloaded from their url. every other indexed file is an override
+func importPackwizPack(ctx context.Context, packPath string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if info, err := os.Stat(packPath); err == nil && info.IsDir() {
+		packPath = filepath.Join(packPath, "pack.toml")
+	}
+
+	var pack packwizPackFile
+	if err := readPackwizFile(packPath, &pack); err != nil {
+		return err
+	}
+	if pack.Index.File == "" {
+		return fmt.Errorf("pack.toml has no index file")
+	}
+
+	indexPath := filepath.Join(filepath.Dir(packPath), filepath.FromSlash(pack.Index.File))
+	var index packwizIndexFile
+	if err := readPackwizFile(indexPath, &index); err != nil {
+		return err
+	}
+	packDir := filepath.Dir(indexPath)
+
+	loader := packwizModLoaderVersion(pack.Versions)
+
+	fmt.Printf("importing packwiz pack: %s\n", pack.Name)
+	fmt.Printf("game version: %s\n", pack.Versions["minecraft"])
+	fmt.Printf("modloader: %s %s\n", loader.Name, loader.Version)
+	fmt.Printf("files: %d\n\n", len(index.Files))
+
+	// Read every metafile, and keep the other files to copy into the overrides
+	var entries []packwizEntry
+	var overrideFiles []string
+	for _, file := range index.Files {
+		if !filepath.IsLocal(filepath.FromSlash(file.File)) {
+			fmt.Printf(util.FormatWarning("skipping %s: outside of the pack\n"), file.File)
+			continue
+		}
+		if !file.Metafile && !strings.HasSuffix(file.File, ".pw.toml") {
+			overrideFiles = append(overrideFiles, file.File)
+			continue
+		}
+
+		var meta packwizMetafileData
+		if err := readPackwizFile(filepath.Join(packDir, filepath.FromSlash(file.File)), &meta); err != nil {
+			return err
+		}
+		entries = append(entries, packwizEntry{
+			slug:   strings.TrimSuffix(path.Base(file.File), ".pw.toml"),
+			folder: path.Dir(file.File),
+			meta:   meta,
+		})
+	}
+
+	// Bulk fetch the modrinth and curseforge projects the metafiles update from
+	var modrinthProjectIDs, modrinthVersionIDs []string
+	var curseforgeModIDs, curseforgeFileIDs []int
+	for _, entry := range entries {
+		if mr := entry.meta.Update.Modrinth; mr != nil {
+			modrinthProjectIDs = append(modrinthProjectIDs, mr.ModID)
+			modrinthVersionIDs = append(modrinthVersionIDs, mr.Version)
+		} else if cf := entry.meta.Update.Curseforge; cf != nil {
+			curseforgeModIDs = append(curseforgeModIDs, cf.ProjectID)
+			curseforgeFileIDs = append(curseforgeFileIDs, cf.FileID)
+		}
+	}
+
+	var modrinthProjects map[string]*modrinth.Project
+	var modrinthVersions map[string]*modrinth.Version
+	var curseforgeMods map[int]*curseforge.Mod
+	var curseforgeFiles map[int]*curseforge.File
+	var fetchErr error
+
+	err := spinner.New().
+		Title("fetching project details...").
+		Type(spinner.Dots).
+		Context(ctx).
+		Action(func() {
+			if len(modrinthProjectIDs) > 0 {
+				modrinthProjects, fetchErr = mymodrinth.GetProjects(ctx, modrinthProjectIDs)
+				if fetchErr != nil {
+					return
+				}
+				modrinthVersions, fetchErr = mymodrinth.GetVersions(ctx, modrinthVersionIDs)
+				if fetchErr != nil {
+					return
+				}
+				fetchErr = prefetchVersionDependencies(ctx, modrinthVersions)
+				if fetchErr != nil {
+					return
+				}
+			}
+			if len(curseforgeModIDs) > 0 {
+				curseforgeMods, fetchErr = curseforge.GetMods(ctx, curseforgeModIDs)
+				if fetchErr != nil {
+					return
+				}
+				curseforgeFiles, fetchErr = curseforge.GetFiles(ctx, curseforgeFileIDs)
+				if fetchErr != nil {
+					return
+				}
+				list := make([]*curseforge.File, 0, len(curseforgeFiles))
+				for _, file := range curseforgeFiles {
+					list = append(list, file)
+				}
+				fetchErr = curseforge.PrefetchDependencies(ctx, list)
+			}
+		}).
+		Run()
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		fmt.Printf(util.FormatError("spinner error: %v\n"), err)
+		return fmt.Errorf("fetch failed: %w", err)
+	}
+	if fetchErr != nil {
+		return fmt.Errorf("failed to fetch project details: %w", fetchErr)
+	}
+
+	// Turn each metafile into content, falling back to custom content when its project is gone
+	var contents []project.ContentData
+	var skipped []string
+	var modrinthCount, curseforgeCount, customCount int
+	for _, entry := range entries {
+		var contentData project.ContentData
+		mr, cf := entry.meta.Update.Modrinth, entry.meta.Update.Curseforge
+		switch {
+		case mr != nil && modrinthProjects[mr.ModID] != nil && modrinthVersions[mr.Version] != nil:
+			contentData = mymodrinth.ConvertProjectToContentData(ctx, modrinthProjects[mr.ModID], modrinthVersions[mr.Version])
+			modrinthCount++
+		case cf != nil && curseforgeMods[cf.ProjectID] != nil && curseforgeFiles[cf.FileID] != nil:
+			contentData = curseforge.ConvertModToContentData(ctx, curseforgeMods[cf.ProjectID], curseforgeFiles[cf.FileID])
+			curseforgeCount++
+		case entry.meta.Download.URL != "":
+			if mr != nil || cf != nil {
+				fmt.Printf(util.FormatWarning("warning: %s was not found upstream, importing it as custom content\n"), entry.slug)
+			}
+			contentData = packwizCustomContent(entry)
+			customCount++
+		default:
+			skipped = append(skipped, entry.slug)
+			continue
+		}
+
+		contentData.Side = packwizModSide(entry.meta.Side, entry.meta.Option.Optional, contentData.Side)
+		contentData.Pinned = entry.meta.Pin
+		contents = append(contents, contentData)
+	}
+
+	fmt.Printf("\nanalysis complete:\n")
+	fmt.Printf("- found on modrinth: %d\n", modrinthCount)
+	fmt.Printf("- found on curseforge: %d\n", curseforgeCount)
+	fmt.Printf("- custom: %d\n", customCount)
+	fmt.Printf("- override files: %d\n", len(overrideFiles))
+	if len(skipped) > 0 {
+		fmt.Printf(util.FormatWarning("warning: these metafiles have no usable download and are skipped: %s\n"), strings.Join(skipped, ", "))
+	}
+
+	defaultSource := "modrinth"
+	if curseforgeCount > modrinthCount {
+		defaultSource = "curseforge"
+	}
+
+	projectData, err := createImportedProject(ctx, cancel, importDefaults{
+		name:          pack.Name,
+		description:   pack.Description,
+		author:        pack.Author,
+		origin:        "packwiz pack",
+		defaultSource: defaultSource,
+		game:          pack.Versions["minecraft"],
+		loader:        loader,
+	}, contents)
+	if err != nil {
+		return err
+	}
+	defer projectData.discardIfInterrupted(ctx)
+
+	// Copy the pack's other files into the overrides, where packwiz would install them
+	var copiedCount int
+	for _, file := range index.Files {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if file.Metafile || strings.HasSuffix(file.File, ".pw.toml") || !filepath.IsLocal(filepath.FromSlash(file.File)) {
+			continue
+		}
+		dest := file.File
+		if file.Alias != "" {
+			dest = file.Alias
+		}
+		if !filepath.IsLocal(filepath.FromSlash(dest)) {
+			fmt.Printf(util.FormatWarning("skipping %s: its alias is outside of the pack\n"), file.File)
+			continue
+		}
+
+		src := filepath.Join(packDir, filepath.FromSlash(file.File))
+		dst := filepath.Join(projectData.Root, project.OverridesFolder, filepath.FromSlash(dest))
+		if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+			fmt.Printf(util.FormatWarning("failed to copy %s: %v\n"), file.File, err)
+			continue
+		}
+		if err := copyFile(src, dst); err != nil {
+			fmt.Printf(util.FormatWarning("failed to copy %s: %v\n"), file.File, err)
+			continue
+		}
+		copiedCount++
+	}
+	if copiedCount > 0 {
+		fmt.Printf("copied %d files into overrides\n", copiedCount)
+	}
+
+	if err := projectData.commit(ctx, nil); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nsuccessfully imported modpack!\n")
+	fmt.Printf("- modrinth mods: %d\n", modrinthCount)
+	fmt.Printf("- curseforge mods: %d\n", curseforgeCount)
+	fmt.Printf("- custom mods: %d\n", customCount)
+	if copiedCount > 0 {
+		fmt.Printf("- override files: %d\n", copiedCount)
+	}
+	if len(skipped) > 0 {
+		fmt.Printf("- skipped: %d\n", len(skipped))
+	}
+
+	return nil
+}
+
+// readPackwizFile parses one of the pack's toml files
+func readPackwizFile(filePath string, v any) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(filePath), err)
+	}
+	if err := toml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filepath.Base(filePath), err)
+	}
+	return nil
+}
+
+// packwizModLoaderVersion reads the pack's loader from pack.toml's versions table
+func packwizModLoaderVersion(versions map[string]string) project.ModloaderVersion {
+	for _, name := range []string{"fabric", "quilt", "forge", "neoforge"} {
+		if loaderVersion, ok := versions[name]; ok {
+			return project.ModloaderVersion{Name: name, Version: loaderVersion}
+		}
+	}
+	return project.ModloaderVersion{}
+}
+
+// packwizCustomContent turns a metafile without a known upstream into custom content that is
+// downloaded from the metafile's url
+func packwizCustomContent(entry packwizEntry) project.ContentData {
+	var hashes project.Hashes
+	hash := strings.ToLower(entry.meta.Download.Hash)
+	switch entry.meta.Download.HashFormat {
+	case "sha1":
+		hashes.Sha1 = hash
+	case "sha256":
+		hashes.Sha256 = hash
+	case "sha512":
+		hashes.Sha512 = hash
+	case "md5":
+		hashes.Md5 = hash
+	}
+
+	var contentType project.ContentType
+	switch path.Base(entry.folder) {
+	case "resourcepacks":
+		contentType = project.Resourcepack
+	case "shaderpacks":
+		contentType = project.Shaderpack
+	case "datapacks":
+		contentType = project.Datapack
+	default:
+		contentType = project.Mod
+	}
+
+	name := entry.meta.Name
+	if name == "" {
+		name = entry.slug
+	}
+
+	return project.ContentData{
+		ContentType: contentType,
+		Name:        name,
+		Id:          entry.slug,
+		Slug:        entry.slug,
+		Side: project.ModSide{
+			Client: project.SideRequired,
+			Server: project.SideRequired,
+		},
+		DownloadUrl:  entry.meta.Download.URL,
+		Source:       project.Custom,
+		Dependencies: []project.Dependency{},
+		File: project.FileData{
+			Filename: entry.meta.Filename,
+			Filepath: path.Join(entry.folder, entry.meta.Filename),
+			Hashes:   hashes,
+		},
+	}
+}
+
+// packwizModSide applies a metafile's side ("client", "server" or "both") to content. optional
+// content stays installable but isn't required on the sides it runs on
+func packwizModSide(side string, optional bool, current project.ModSide) project.ModSide {
+	supported := func(s project.ModSideData) project.ModSideData {
+		if s == project.SideUnsupported || s == project.SideInapplicable {
+			return project.SideRequired
+		}
+		return s
+	}
+
+	switch side {
+	case "client":
+		current = project.ModSide{Client: supported(current.Client), Server: project.SideUnsupported}
+	case "server":
+		current = project.ModSide{Client: project.SideUnsupported, Server: supported(current.Server)}
+	case "both":
+		current = project.ModSide{Client: supported(current.Client), Server: supported(current.Server)}
+	}
+
+	if optional {
+		current = optionalSide(current)
+	}
+	return current
+}
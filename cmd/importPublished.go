@@ -0,0 +1,151 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"minepack/core/api"
+	"minepack/core/api/curseforge"
+	mymodrinth "minepack/core/api/modrinth"
+	"minepack/core/download"
+	"minepack/core/project"
+	"minepack/util"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/huh/spinner"
+)
+
+// importPublishedPack imports a modpack published on modrinth or curseforge, given as
+// "<id or slug>[@version]". the pack is downloaded through the cache, imported like a local one
+// and remembered as the project's upstream
+func importPublishedPack(ctx context.Context, manager *download.Manager, source string, ref string) error {
+	id, versionRef, _ := strings.Cut(ref, "@")
+	if id == "" {
+		return fmt.Errorf("no modpack given, expected %s:<id>[@version]", source)
+	}
+
+	var packFile project.ContentData
+	var upstream project.Upstream
+	var resolveErr error
+
+	err := spinner.New().
+		Title(fmt.Sprintf("looking up %s on %s...", id, source)).
+		Type(spinner.Dots).
+		Context(ctx).
+		Action(func() {
+			packFile, upstream, resolveErr = resolvePublishedPack(ctx, source, id, versionRef)
+		}).
+		Run()
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		fmt.Printf(util.FormatError("spinner error: %v\n"), err)
+		return fmt.Errorf("lookup failed: %w", err)
+	}
+	if resolveErr != nil {
+		return resolveErr
+	}
+
+	fmt.Printf("found %s %s\n", packFile.Name, upstream.Version)
+
+	packPath, cleanup, err := downloadPublishedPack(ctx, manager, packFile)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if source == "curseforge" {
+		return importCurseforgePack(ctx, packPath, &upstream)
+	}
+	return importModrinthPack(ctx, manager, packPath, &upstream)
+}
+
+// resolvePublishedPack finds a modpack's version, its newest when version is empty, returning
+// the pack file to download and the upstream it becomes. modrinth versions are matched by id or
+// version number, curseforge ones by file id
+func resolvePublishedPack(ctx context.Context, source string, id string, version string) (project.ContentData, project.Upstream, error) {
+	switch source {
+	case "modrinth":
+		proj, err := mymodrinth.GetProject(ctx, id)
+		if err != nil {
+			return project.ContentData{}, project.Upstream{}, err
+		}
+		if proj.ProjectType == nil || *proj.ProjectType != "modpack" {
+			return project.ContentData{}, project.Upstream{}, fmt.Errorf("%s is not a modpack", id)
+		}
+		modpackVersion, err := mymodrinth.GetModpackVersion(ctx, *proj.ID, version)
+		if err != nil {
+			return project.ContentData{}, project.Upstream{}, err
+		}
+
+		packFile := mymodrinth.ConvertProjectToContentData(ctx, proj, modpackVersion)
+		upstream := project.Upstream{
+			Source:    source,
+			Id:        packFile.Id,
+			Slug:      packFile.Slug,
+			VersionId: packFile.VersionId,
+		}
+		if modpackVersion.VersionNumber != nil {
+			upstream.Version = *modpackVersion.VersionNumber
+		}
+		return packFile, upstream, nil
+	case "curseforge":
+		var fileID int
+		if version != "" {
+			parsed, err := strconv.Atoi(version)
+			if err != nil {
+				return project.ContentData{}, project.Upstream{}, fmt.Errorf("invalid curseforge file id %q", version)
+			}
+			fileID = parsed
+		}
+		mod, file, err := curseforge.GetModpackFile(ctx, id, fileID)
+		if err != nil {
+			return project.ContentData{}, project.Upstream{}, err
+		}
+
+		packFile := curseforge.ConvertModToContentData(ctx, mod, file)
+		return packFile, project.Upstream{
+			Source:    source,
+			Id:        packFile.Id,
+			Slug:      packFile.Slug,
+			VersionId: packFile.VersionId,
+			Version:   file.DisplayName,
+		}, nil
+	default:
+		return project.ContentData{}, project.Upstream{}, fmt.Errorf("modpacks can't be imported from %s", source)
+	}
+}
+
+// downloadPublishedPack downloads a modpack's file into a temporary folder, which cleanup removes
+func downloadPublishedPack(ctx context.Context, manager *download.Manager, packFile project.ContentData) (string, func(), error) {
+	tempDir, err := os.MkdirTemp("", "minepack-published-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(tempDir) }
+
+	filename := filepath.Base(packFile.File.Filename)
+	if filename == "." || filename == string(filepath.Separator) {
+		filename = "pack.zip"
+	}
+	packPath := filepath.Join(tempDir, filename)
+
+	results, err := manager.Run(ctx, []download.Job{api.DownloadJob(packFile, packPath)})
+	if err == nil {
+		if failed := download.Failed(results); len(failed) > 0 {
+			err = fmt.Errorf("failed to download %s: %w", packFile.Name, failed[0].Err)
+		}
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return packPath, cleanup, nil
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"minepack/core"
+	"minepack/core/api"
 	"minepack/core/project"
 	"minepack/util"
 	"minepack/util/version"
@@ -211,7 +212,7 @@ var initCmd = &cobra.Command{
 		fmt.Printf(util.FormatSuccess("modloader version: %s\n"), allModloaderVersions[selectedModloader])
 
 		// select default source
-		var availableDefaultSources = []string{"modrinth", "curseforge"}
+		var availableDefaultSources = api.Names()
 
 		defaultSourceForm := huh.NewForm(
 			huh.NewGroup(
@@ -228,7 +229,7 @@ var initCmd = &cobra.Command{
 			return
 		}
 		if selectedDefaultSource == "" {
-			selectedDefaultSource = "modrinth"
+			selectedDefaultSource = api.DefaultProvider
 		}
 		// if the selected source is curseforge, verify that the user actually wants to use this
 		if selectedDefaultSource == "curseforge" {
@@ -17,7 +17,28 @@ import (
 
 // LinkedFolders represents the structure of linked.mp.yaml
 type LinkedFolders struct {
-	Links []string `yaml:"links"`
+	Links []string          `yaml:"links"`
+	Sides map[string]string `yaml:"sides,omitempty"` // link -> "client" or "server", links without one are clients
+}
+
+// Side returns which side a linked instance is, deciding the overrides it gets
+func (l *LinkedFolders) Side(link string) string {
+	if side, ok := l.Sides[link]; ok {
+		return side
+	}
+	return "client"
+}
+
+// SetSide records which side a linked instance is
+func (l *LinkedFolders) SetSide(link, side string) {
+	if side == "client" {
+		delete(l.Sides, link)
+		return
+	}
+	if l.Sides == nil {
+		l.Sides = make(map[string]string)
+	}
+	l.Sides[link] = side
 }
 
 // getLinkedFile returns the path to linked.mp.yaml
@@ -86,9 +107,12 @@ var linkCmd = &cobra.Command{
 
 // linkAddCmd represents the link add command
 var linkAddCmd = &cobra.Command{
-	Use:     "add [folder_path]",
-	Short:   "link a Minecraft instance folder to this modpack",
-	Long:    `adds a link to a Minecraft instance folder, allowing future operations to sync with that instance`,
+	Use:   "add [folder_path]",
+	Short: "link a Minecraft instance folder to this modpack",
+	Long: `adds a link to a Minecraft instance folder, allowing future operations to sync with that instance
+
+--side server links a server folder, which gets server-overrides instead of client-overrides. adding
+an existing link again with --side changes its side`,
 	Aliases: []string{"new", "link"},
 	Args:    cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
@@ -106,6 +130,11 @@ var linkAddCmd = &cobra.Command{
 		}
 
 		folderPath := args[0]
+		side, _ := cmd.Flags().GetString("side")
+		if side != "client" && side != "server" {
+			fmt.Printf(util.FormatError("unknown side %s, expected client or server\n"), side)
+			return
+		}
 
 		// Convert to absolute path
 		absPath, err := filepath.Abs(folderPath)
@@ -141,13 +170,23 @@ var linkAddCmd = &cobra.Command{
 		// Check if already linked
 		for _, existingPath := range linked.Links {
 			if existingPath == absPath {
-				fmt.Printf(util.FormatWarning("folder is already linked: %s"), absPath)
+				if !cmd.Flags().Changed("side") || linked.Side(absPath) == side {
+					fmt.Printf(util.FormatWarning("folder is already linked: %s"), absPath)
+					return
+				}
+				linked.SetSide(absPath, side)
+				if err := saveLinkedFolders(cwd, linked); err != nil {
+					fmt.Printf(util.FormatError("error saving linked folders: %s"), err)
+					return
+				}
+				fmt.Printf(util.FormatSuccess("linked folder %s is now a %s"), absPath, side)
 				return
 			}
 		}
 
 		// Add the new link
 		linked.Links = append(linked.Links, absPath)
+		linked.SetSide(absPath, side)
 
 		// Save the updated list
 		if err := saveLinkedFolders(cwd, linked); err != nil {
@@ -231,6 +270,7 @@ var linkRemoveCmd = &cobra.Command{
 		}
 
 		linked.Links = updatedLinks
+		delete(linked.Sides, selectedLink)
 
 		// Save the updated list
 		if err := saveLinkedFolders(cwd, linked); err != nil {
@@ -279,9 +319,9 @@ var linkListCmd = &cobra.Command{
 		for i, link := range linked.Links {
 			// Check if folder still exists
 			if _, err := os.Stat(link); os.IsNotExist(err) {
-				fmt.Printf("  %d. %s %s\n", i+1, link, util.FormatWarning("(missing)"))
+				fmt.Printf("  %d. %s (%s) %s\n", i+1, link, linked.Side(link), util.FormatWarning("(missing)"))
 			} else {
-				fmt.Printf("  %d. %s\n", i+1, link)
+				fmt.Printf("  %d. %s (%s)\n", i+1, link, linked.Side(link))
 			}
 		}
 	},
@@ -292,4 +332,5 @@ func init() {
 	linkCmd.AddCommand(linkAddCmd)
 	linkCmd.AddCommand(linkRemoveCmd)
 	linkCmd.AddCommand(linkListCmd)
+	linkAddCmd.Flags().String("side", "client", "whether the folder is a client instance or a server: client or server")
 }
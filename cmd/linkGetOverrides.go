@@ -18,7 +18,8 @@ import (
 var linkGetOverridesCmd = &cobra.Command{
 	Use:   "get-overrides",
 	Short: "copy files from a linked instance to overrides",
-	Long:  `select a linked instance and copy files from it to your project's overrides folder`,
+	Long: `select a linked instance and copy files from it to one of your project's override folders:
+overrides for both sides, client-overrides or server-overrides. --to picks the folder without asking`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// get current working directory and parse project
 		cwd, err := os.Getwd()
@@ -112,6 +113,30 @@ var linkGetOverridesCmd = &cobra.Command{
 			return nil
 		}
 
+		// Pick the override folder to copy to
+		destination, _ := cmd.Flags().GetString("to")
+		if !cmd.Flags().Changed("to") {
+			destinationForm := huh.NewForm(
+				huh.NewGroup(
+					huh.NewSelect[string]().
+						Title("select where to copy them").
+						Options(
+							huh.NewOption("overrides (both sides)", project.OverridesFolder),
+							huh.NewOption("client-overrides (clients only)", project.ClientOverridesFolder),
+							huh.NewOption("server-overrides (servers only)", project.ServerOverridesFolder),
+						).
+						Value(&destination),
+				),
+			)
+			if err := destinationForm.Run(); err != nil {
+				return fmt.Errorf("prompt failed: %w", err)
+			}
+		}
+		if destination != project.OverridesFolder && destination != project.ClientOverridesFolder && destination != project.ServerOverridesFolder {
+			fmt.Printf(util.FormatError("unknown override folder %s, expected %s, %s or %s\n"), destination, project.OverridesFolder, project.ClientOverridesFolder, project.ServerOverridesFolder)
+			return nil
+		}
+
 		// Load project content to check for conflicts
 		packData, err := project.ParseProject(projectRoot)
 		if err != nil {
@@ -132,10 +157,10 @@ var linkGetOverridesCmd = &cobra.Command{
 			contentFilePaths[normalizedPath] = true
 		}
 
-		// Create overrides directory if it doesn't exist
-		overridesDir := filepath.Join(projectRoot, "overrides")
+		// Create the override directory if it doesn't exist
+		overridesDir := filepath.Join(projectRoot, destination)
 		if err := os.MkdirAll(overridesDir, 0755); err != nil {
-			return fmt.Errorf("failed to create overrides directory: %w", err)
+			return fmt.Errorf("failed to create %s directory: %w", destination, err)
 		}
 
 		// Check which files would conflict with content data and copy the rest
@@ -226,7 +251,7 @@ var linkGetOverridesCmd = &cobra.Command{
 						return nil
 					}
 
-					fmt.Printf(util.FormatSuccess("copied file %s to overrides\n"), walkRelPath)
+					fmt.Printf(util.FormatSuccess("copied file %s to %s\n"), walkRelPath, destination)
 					return nil
 				})
 
@@ -240,7 +265,7 @@ var linkGetOverridesCmd = &cobra.Command{
 					fmt.Printf(util.FormatError("failed to copy file %s: %v\n"), fileName, err)
 					continue
 				}
-				fmt.Printf(util.FormatSuccess("copied file %s to overrides\n"), fileName)
+				fmt.Printf(util.FormatSuccess("copied file %s to %s\n"), fileName, destination)
 			}
 		}
 
@@ -250,4 +275,5 @@ var linkGetOverridesCmd = &cobra.Command{
 
 func init() {
 	linkCmd.AddCommand(linkGetOverridesCmd)
+	linkGetOverridesCmd.Flags().String("to", project.OverridesFolder, "override folder to copy to: overrides, client-overrides or server-overrides")
 }
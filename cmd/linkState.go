@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"io"
+	"minepack/core/project"
 	"os"
 	"path/filepath"
 	"time"
@@ -13,10 +14,11 @@ import (
 
 // LinkState represents the state of linked instances
 type LinkState struct {
-	LastUpdate      time.Time         `yaml:"last_update"`
-	RemovedFiles    []string          `yaml:"removed_files"`    // file paths of removed mods/content
-	OverridesFiles  map[string]string `yaml:"overrides_files"`  // path -> hash of files in overrides
-	Version         string            `yaml:"version"`
+	LastUpdate         time.Time                    `yaml:"last_update"`
+	RemovedFiles       []string                     `yaml:"removed_files"`                  // file paths of removed mods/content
+	OverridesFiles     map[string]string            `yaml:"overrides_files"`                // path -> hash of files in overrides
+	SideOverridesFiles map[string]map[string]string `yaml:"side_overrides_files,omitempty"` // client-overrides or server-overrides -> path -> hash
+	Version            string                       `yaml:"version"`
 }
 
 // getLinkStateFile returns the path to linkstate.mp.yaml
@@ -108,19 +110,15 @@ func calculateFileHash(filepath string) (string, error) {
 	return fmt.Sprintf("%x", hash.Sum(nil)), nil
 }
 
-// ScanOverridesFolder scans the overrides folder and updates the file hash map
-func (ls *LinkState) ScanOverridesFolder(projectRoot string) error {
-	overridesPath := filepath.Join(projectRoot, "overrides")
-	
-	// Check if overrides folder exists
+// scanOverrideFiles hashes every file in an override folder, keyed by its path in the folder
+func scanOverrideFiles(overridesPath string) (map[string]string, error) {
+	files := make(map[string]string)
+
+	// Check if the folder exists
 	if _, err := os.Stat(overridesPath); os.IsNotExist(err) {
-		// No overrides folder, clear the map
-		ls.OverridesFiles = make(map[string]string)
-		return nil
+		return files, nil
 	}
 
-	newOverridesFiles := make(map[string]string)
-
 	err := filepath.Walk(overridesPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -143,29 +141,67 @@ func (ls *LinkState) ScanOverridesFolder(projectRoot string) error {
 			return err
 		}
 
-		newOverridesFiles[relPath] = hash
+		files[relPath] = hash
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
 
+// ScanOverridesFolder scans the override folders and updates the file hash maps
+func (ls *LinkState) ScanOverridesFolder(projectRoot string) error {
+	newOverridesFiles, err := scanOverrideFiles(filepath.Join(projectRoot, project.OverridesFolder))
 	if err != nil {
 		return fmt.Errorf("failed to scan overrides folder: %w", err)
 	}
 
+	newSideOverridesFiles := make(map[string]map[string]string)
+	for _, folder := range []string{project.ClientOverridesFolder, project.ServerOverridesFolder} {
+		files, err := scanOverrideFiles(filepath.Join(projectRoot, folder))
+		if err != nil {
+			return fmt.Errorf("failed to scan %s folder: %w", folder, err)
+		}
+		if len(files) > 0 {
+			newSideOverridesFiles[folder] = files
+		}
+	}
+
 	ls.OverridesFiles = newOverridesFiles
+	ls.SideOverridesFiles = newSideOverridesFiles
 	return nil
 }
 
-// GetOverridesDiff returns lists of added, modified, and removed files in overrides
-func (ls *LinkState) GetOverridesDiff(projectRoot string) (added []string, modified []string, removed []string, err error) {
-	// Get current state of overrides folder
+// sideOverrides merges the hashes of the override folders used on a side, with the side's own
+// files replacing shared ones
+func (ls *LinkState) sideOverrides(side string) map[string]string {
+	merged := make(map[string]string)
+	for _, folder := range project.OverrideFolders(side) {
+		files := ls.OverridesFiles
+		if folder != project.OverridesFolder {
+			files = ls.SideOverridesFiles[folder]
+		}
+		for relPath, hash := range files {
+			merged[relPath] = hash
+		}
+	}
+	return merged
+}
+
+// GetOverridesDiff returns lists of added, modified, and removed files in the overrides a side uses
+func (ls *LinkState) GetOverridesDiff(projectRoot string, side string) (added []string, modified []string, removed []string, err error) {
+	// Get current state of the override folders
 	currentState := &LinkState{OverridesFiles: make(map[string]string)}
 	if err := currentState.ScanOverridesFolder(projectRoot); err != nil {
 		return nil, nil, nil, err
 	}
+	previousFiles := ls.sideOverrides(side)
+	currentFiles := currentState.sideOverrides(side)
 
 	// Compare with previous state
-	for relPath, currentHash := range currentState.OverridesFiles {
-		if previousHash, exists := ls.OverridesFiles[relPath]; exists {
+	for relPath, currentHash := range currentFiles {
+		if previousHash, exists := previousFiles[relPath]; exists {
 			if previousHash != currentHash {
 				modified = append(modified, relPath)
 			}
@@ -175,11 +211,23 @@ func (ls *LinkState) GetOverridesDiff(projectRoot string) (added []string, modif
 	}
 
 	// Find removed files
-	for relPath := range ls.OverridesFiles {
-		if _, exists := currentState.OverridesFiles[relPath]; !exists {
+	for relPath := range previousFiles {
+		if _, exists := currentFiles[relPath]; !exists {
 			removed = append(removed, relPath)
 		}
 	}
 
 	return added, modified, removed, nil
-}
\ No newline at end of file
+}
+
+// overrideSource returns the override file a side uses at relPath, the side's own when it has one
+func overrideSource(projectRoot string, side string, relPath string) string {
+	folders := project.OverrideFolders(side)
+	for i := len(folders) - 1; i > 0; i-- {
+		path := filepath.Join(projectRoot, folders[i], relPath)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(projectRoot, folders[0], relPath)
+}
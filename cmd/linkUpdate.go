@@ -6,15 +6,14 @@ package cmd
 import (
 	"fmt"
 	"io"
+	"minepack/core/api"
+	"minepack/core/download"
 	"minepack/core/project"
 	"minepack/util"
-	"net/http"
 	"os"
 	"path/filepath"
-	"sync"
+	"slices"
 
-	"github.com/charmbracelet/bubbles/progress"
-	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss/v2"
 	"github.com/spf13/cobra"
@@ -39,6 +38,10 @@ func getMissingFiles(linkPath string, allContent []project.ContentData) ([]proje
 	var missingFiles []project.ContentData
 
 	for _, content := range allContent {
+		// custom content without a download url arrives with the overrides
+		if content.InOverrides() {
+			continue
+		}
 		// Check if the file exists in the link's mods directory
 		modPath := filepath.Join(linkPath, content.File.Filepath)
 		if _, err := os.Stat(modPath); os.IsNotExist(err) {
@@ -49,40 +52,6 @@ func getMissingFiles(linkPath string, allContent []project.ContentData) ([]proje
 	return missingFiles, nil
 }
 
-// downloadFile downloads a file from URL to the specified path with progress tracking
-func downloadFileWithProgress(url, filepath string, workerID int, name string, progressCh chan<- downloadMsg) error {
-	// Create the file
-	out, err := os.Create(filepath)
-	if err != nil {
-		return err
-	}
-	defer out.Close()
-
-	// Get the data
-	resp, err := http.Get(url)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	// Check server response
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad status: %s", resp.Status)
-	}
-
-	// Create progress writer
-	pw := &progressWriter{
-		workerID:   workerID,
-		name:       name,
-		total:      resp.ContentLength,
-		progressCh: progressCh,
-	}
-
-	// Write the body to file with progress tracking
-	_, err = io.Copy(out, io.TeeReader(resp.Body, pw))
-	return err
-}
-
 // copyFile copies a file from src to dst
 func copyFile(src, dst string) error {
 	sourceFile, err := os.Open(src)
@@ -131,178 +100,6 @@ var updateSummaryStyle = lipgloss.NewStyle().
 	BorderForeground(lipgloss.Color("#874BFD")).
 	Margin(0, 1)
 
-// Progress bar model for downloads
-type downloadProgress struct {
-	globalProgress   progress.Model
-	workerProgresses [3]progress.Model
-	workerNames      [3]string
-	workerActive     [3]bool
-	workerProgress   [3]float64
-	current          int
-	total            int
-	done             bool
-	err              error
-}
-
-func newDownloadProgress(total int) downloadProgress {
-	globalProg := progress.New(progress.WithDefaultGradient())
-	globalProg.Width = 60
-
-	var workerProgs [3]progress.Model
-	for i := 0; i < 3; i++ {
-		workerProgs[i] = progress.New(progress.WithDefaultGradient())
-		workerProgs[i].Width = 40
-	}
-
-	return downloadProgress{
-		globalProgress:   globalProg,
-		workerProgresses: workerProgs,
-		total:            total,
-	}
-}
-
-type downloadMsg struct {
-	workerID int
-	name     string
-	progress float64 // 0.0 to 1.0 for real-time progress
-	err      error
-	complete bool
-}
-
-type downloadCompleteMsg struct{}
-
-// progressWriter tracks download progress and sends updates
-type progressWriter struct {
-	workerID   int
-	name       string
-	total      int64
-	downloaded int64
-	progressCh chan<- downloadMsg
-}
-
-func (pw *progressWriter) Write(p []byte) (int, error) {
-	n := len(p)
-	pw.downloaded += int64(n)
-
-	if pw.total > 0 {
-		progress := float64(pw.downloaded) / float64(pw.total)
-		// Send progress update
-		select {
-		case pw.progressCh <- downloadMsg{
-			workerID: pw.workerID,
-			name:     pw.name,
-			progress: progress,
-			complete: false,
-		}:
-		default:
-			// Don't block if channel is full
-		}
-	}
-
-	return n, nil
-}
-
-func (m downloadProgress) Init() tea.Cmd {
-	return nil
-}
-
-func (m downloadProgress) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case downloadMsg:
-		if msg.err != nil {
-			m.err = msg.err
-			return m, nil
-		}
-
-		// Update worker progress
-		if msg.workerID >= 0 && msg.workerID < 3 {
-			if msg.complete {
-				m.workerActive[msg.workerID] = false
-				m.workerNames[msg.workerID] = ""
-				m.workerProgress[msg.workerID] = 1.0
-				m.current++
-			} else {
-				m.workerActive[msg.workerID] = true
-				m.workerNames[msg.workerID] = msg.name
-				m.workerProgress[msg.workerID] = msg.progress
-			}
-		}
-
-		if m.current >= m.total {
-			m.done = true
-			return m, tea.Quit
-		}
-		return m, nil
-	case downloadCompleteMsg:
-		m.done = true
-		return m, tea.Quit
-	default:
-		var cmd tea.Cmd
-		globalModel, cmd := m.globalProgress.Update(msg)
-		m.globalProgress = globalModel.(progress.Model)
-
-		for i := 0; i < 3; i++ {
-			workerModel, _ := m.workerProgresses[i].Update(msg)
-			m.workerProgresses[i] = workerModel.(progress.Model)
-		}
-		return m, cmd
-	}
-}
-
-func (m downloadProgress) View() string {
-	if m.err != nil {
-		return fmt.Sprintf("download failed: %s\n", m.err.Error())
-	}
-
-	if m.done {
-		return fmt.Sprintf(util.FormatSuccess("downloaded all %d files\n"), m.total)
-	}
-
-	// Global progress
-	globalPercent := float64(m.current) / float64(m.total)
-	view := fmt.Sprintf("overall progress: %d/%d\n%s\n\n",
-		m.current, m.total, m.globalProgress.ViewAs(globalPercent))
-
-	// Individual worker progress bars
-	view += "downloading:\n"
-	for i := 0; i < 3; i++ {
-		if m.workerActive[i] {
-			view += fmt.Sprintf("worker %d: %s\n%s\n",
-				i+1, m.workerNames[i], m.workerProgresses[i].ViewAs(m.workerProgress[i]))
-		} else {
-			view += fmt.Sprintf("worker %d: waiting...\n%s\n",
-				i+1, m.workerProgresses[i].ViewAs(m.workerProgress[i]))
-		}
-	}
-
-	return view
-}
-
-// downloadWorker handles downloading files in parallel
-func downloadWorker(workerID int, jobs <-chan project.ContentData, results chan<- downloadMsg, cacheDir string) {
-	for content := range jobs {
-		// Signal start of download
-		results <- downloadMsg{workerID: workerID, name: content.Name, progress: 0.0, complete: false}
-
-		cachePath := filepath.Join(cacheDir, content.File.Filepath)
-
-		// Create directory if needed
-		if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
-			results <- downloadMsg{workerID: workerID, name: content.Name, err: fmt.Errorf("failed to create cache directory: %w", err), complete: true}
-			continue
-		}
-
-		// Skip if already exists in cache
-		if _, err := os.Stat(cachePath); err == nil {
-			results <- downloadMsg{workerID: workerID, name: content.Name, progress: 1.0, complete: true}
-			continue
-		}
-
-		err := downloadFileWithProgress(content.DownloadUrl, cachePath, workerID, content.Name, results)
-		results <- downloadMsg{workerID: workerID, name: content.Name, progress: 1.0, err: err, complete: true}
-	}
-}
-
 // filterContent filters content based on flags with dependency resolution
 func filterContent(allContent []project.ContentData, serverOnly, clientOnly bool, source string) []project.ContentData {
 	var filtered []project.ContentData
@@ -355,19 +152,8 @@ func filterContent(allContent []project.ContentData, serverOnly, clientOnly bool
 		}
 
 		// Filter by source
-		if source != "" {
-			var contentSource string
-			switch content.Source {
-			case 0:
-				contentSource = "modrinth"
-			case 1:
-				contentSource = "curseforge"
-			default:
-				contentSource = "unknown"
-			}
-			if contentSource != source {
-				includeContent = false
-			}
+		if source != "" && project.SourceToString(content.Source) != source {
+			includeContent = false
 		}
 
 		if includeContent {
@@ -383,19 +169,8 @@ func filterContent(allContent []project.ContentData, serverOnly, clientOnly bool
 					depInclude := true
 
 					// Still respect source filtering for dependencies
-					if source != "" {
-						var depSource string
-						switch dep.Source {
-						case 0:
-							depSource = "modrinth"
-						case 1:
-							depSource = "curseforge"
-						default:
-							depSource = "unknown"
-						}
-						if depSource != source {
-							depInclude = false
-						}
+					if source != "" && project.SourceToString(dep.Source) != source {
+						depInclude = false
 					}
 
 					// Don't include client-only dependencies for server-only filtering
@@ -419,11 +194,49 @@ func filterContent(allContent []project.ContentData, serverOnly, clientOnly bool
 	return filtered
 }
 
+// linkSides returns the sides of the linked instances, each once
+func linkSides(linked *LinkedFolders) []string {
+	var sides []string
+	for _, linkPath := range linked.Links {
+		if side := linked.Side(linkPath); !slices.Contains(sides, side) {
+			sides = append(sides, side)
+		}
+	}
+	return sides
+}
+
+// hasOverrideFolders reports whether the project has any override folder
+func hasOverrideFolders(projectRoot string) bool {
+	for _, folder := range []string{project.OverridesFolder, project.ClientOverridesFolder, project.ServerOverridesFolder} {
+		if _, err := os.Stat(filepath.Join(projectRoot, folder)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// syncOverrideFolders copies every override a side uses to a linked instance
+func syncOverrideFolders(projectRoot, side, linkPath string) error {
+	for _, folder := range project.OverrideFolders(side) {
+		overridesPath := filepath.Join(projectRoot, folder)
+		if _, err := os.Stat(overridesPath); err != nil {
+			continue
+		}
+		if err := copyDir(overridesPath, linkPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // linkUpdateCmd represents the link update command
 var linkUpdateCmd = &cobra.Command{
 	Use:   "update",
 	Short: "update all linked Minecraft instances with current modpack content",
-	Long:  `downloads missing mods and syncs overrides to all linked Minecraft instance folders`,
+	Long: `downloads missing mods and syncs overrides to all linked Minecraft instance folders
+
+client instances get client-overrides on top of overrides and servers get server-overrides, see
+'minepack link add --side'`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Get flags
 		serverOnly, _ := cmd.Flags().GetBool("server-only")
@@ -436,9 +249,11 @@ var linkUpdateCmd = &cobra.Command{
 			return
 		}
 
-		if source != "" && source != "modrinth" && source != "curseforge" {
-			fmt.Printf(util.FormatError("invalid source: %s (must be 'modrinth' or 'curseforge')\n"), source)
-			return
+		if source != "" {
+			if _, err := api.Get(source); err != nil {
+				fmt.Printf(util.FormatError("invalid source: %s\n"), err)
+				return
+			}
 		}
 		// get current working directory and parse project
 		cwd, err := os.Getwd()
@@ -518,12 +333,20 @@ var linkUpdateCmd = &cobra.Command{
 			}
 		}
 
-		// Check for overrides changes
-		added, modified, removed, err := linkState.GetOverridesDiff(cwd)
-		overridesChanges := len(added) + len(modified) + len(removed)
-		if err != nil {
-			fmt.Printf(util.FormatWarning("warning: failed to calculate overrides diff: %s\n"), err)
-			overridesChanges = -1 // Unknown changes, assume changes exist
+		// Check for overrides changes on each linked side
+		overridesChanges := 0
+		var overridesSummary string
+		for _, side := range linkSides(linked) {
+			added, modified, removed, err := linkState.GetOverridesDiff(cwd, side)
+			if err != nil {
+				fmt.Printf(util.FormatWarning("warning: failed to calculate overrides diff: %s\n"), err)
+				overridesChanges = -1 // Unknown changes, assume changes exist
+				break
+			}
+			if changes := len(added) + len(modified) + len(removed); changes > 0 {
+				overridesChanges += changes
+				overridesSummary += fmt.Sprintf("- %s overrides changes: %d added, %d modified, %d removed\n", side, len(added), len(modified), len(removed))
+			}
 		}
 
 		// Calculate download size and total disk usage
@@ -532,7 +355,7 @@ var linkUpdateCmd = &cobra.Command{
 			downloadSize += content.File.Filesize
 		}
 
-		totalDiskUsage := downloadSize * int64(len(linked.Links)+1) // +1 for cache
+		totalDiskUsage := downloadSize * int64(len(linked.Links)+1) // +1 for the download cache
 
 		// Check if any work needs to be done
 		hasRemovedFiles := len(linkState.RemovedFiles) > 0
@@ -550,7 +373,7 @@ var linkUpdateCmd = &cobra.Command{
 			finalString += fmt.Sprintf("- files to remove: %d\n", len(linkState.RemovedFiles))
 		}
 		if overridesChanges > 0 {
-			finalString += fmt.Sprintf("- overrides changes: %d added, %d modified, %d removed\n", len(added), len(modified), len(removed))
+			finalString += overridesSummary
 		}
 		finalString += fmt.Sprintf("- download size: %s\n", formatFileSize(downloadSize))
 		finalString += fmt.Sprintf("- total disk usage after sync: %s", formatFileSize(totalDiskUsage))
@@ -576,65 +399,6 @@ var linkUpdateCmd = &cobra.Command{
 			}
 		}
 
-		// Create cache directory (needed for both downloads and file copying)
-		cacheDir := filepath.Join(cwd, ".mpcache")
-		if err := os.MkdirAll(cacheDir, 0755); err != nil {
-			fmt.Printf(util.FormatError("error creating cache directory: %s"), err)
-			return
-		}
-
-		// Download missing files to cache (if any)
-		if len(allMissingFiles) > 0 {
-			// Set up parallel download with progress bar
-			jobs := make(chan project.ContentData, len(allMissingFiles))
-			results := make(chan downloadMsg, len(allMissingFiles))
-
-			// Start 3 worker goroutines
-			const numWorkers = 3
-			var wg sync.WaitGroup
-			for i := 0; i < numWorkers; i++ {
-				wg.Add(1)
-				go func(workerID int) {
-					defer wg.Done()
-					downloadWorker(workerID, jobs, results, cacheDir)
-				}(i)
-			}
-
-			// Send jobs
-			for _, content := range allMissingFiles {
-				jobs <- content
-			}
-			close(jobs)
-
-			// Set up progress bar
-			prog := newDownloadProgress(len(allMissingFiles))
-			p := tea.NewProgram(prog)
-
-			// Monitor results and update progress
-			go func() {
-				defer func() {
-					wg.Wait()
-					p.Send(downloadCompleteMsg{})
-				}()
-
-				completed := 0
-				for completed < len(allMissingFiles) {
-					result := <-results
-					p.Send(result)
-					// Only count completed files, not progress updates
-					if result.complete {
-						completed++
-					}
-				}
-			}()
-
-			// Run the progress bar
-			if _, err := p.Run(); err != nil {
-				fmt.Printf(util.FormatError("progress bar error: %s\n"), err)
-				return
-			}
-		}
-
 		// First, delete any tracked removed files from linked instances
 		if len(linkState.RemovedFiles) > 0 {
 			fmt.Printf("\nremoving %d deleted files from linked instances...\n", len(linkState.RemovedFiles))
@@ -658,101 +422,116 @@ var linkUpdateCmd = &cobra.Command{
 			linkState.ClearRemovedFiles()
 		}
 
-		// Copy files from cache to each linked instance (if there are missing files)
+		// Download missing files straight into each linked instance, each unique file is
+		// fetched once and shared through the download cache
 		if len(allMissingFiles) > 0 {
-			fmt.Println("\nsyncing files to linked instances...")
+			var jobs []download.Job
+			var jobLinks []string
+			var jobContents []project.ContentData
+			for _, linkPath := range linked.Links {
+				for _, content := range missingFilesByLink[linkPath] {
+					jobs = append(jobs, api.DownloadJob(content, filepath.Join(linkPath, content.File.Filepath)))
+					jobLinks = append(jobLinks, linkPath)
+					jobContents = append(jobContents, content)
+				}
+			}
+
+			results, err := newDownloadManager(cmd, cwd).Run(cmd.Context(), jobs)
+			if err != nil {
+				fmt.Print(util.FormatError("download cancelled, linked instances were only partially updated\n"))
+				return
+			}
+
+			synced := make(map[string]int)
+			for i, result := range results {
+				if result.Err != nil {
+					fmt.Printf(util.FormatError("failed to sync %s to %s: %s\n"), result.Job.Name, jobLinks[i], result.Err)
+					continue
+				}
+				synced[jobLinks[i]]++
+			}
+			recordComputedHashes(cmd.Context(), packData, jobContents, results)
 
+			fmt.Println("\nsyncing files to linked instances...")
 			for _, linkPath := range linked.Links {
 				missingFiles := missingFilesByLink[linkPath]
 				if len(missingFiles) == 0 {
 					fmt.Printf(util.FormatSuccess("%s (already up to date)\n"), linkPath)
 					continue
 				}
-
-				// Copy missing files
-				successCount := 0
-				for _, content := range missingFiles {
-					cachePath := filepath.Join(cacheDir, content.File.Filepath)
-					destPath := filepath.Join(linkPath, content.File.Filepath)
-
-					// Ensure subdirectory exists
-					splitPath := filepath.Dir(destPath)
-					if err := os.MkdirAll(splitPath, 0755); err != nil {
-						fmt.Printf(util.FormatError("failed to create directory %s: %s\n"), splitPath, err)
-						continue
-					}
-
-					if err := copyFile(cachePath, destPath); err != nil {
-						fmt.Printf(util.FormatError("failed to copy %s to %s: %s\n"), content.Name, linkPath, err)
-					} else {
-						successCount++
-					}
-				}
-
-				fmt.Printf(util.FormatSuccess("%s (%d/%d files synced)\n"), linkPath, successCount, len(missingFiles))
+				fmt.Printf(util.FormatSuccess("%s (%d/%d files synced)\n"), linkPath, synced[linkPath], len(missingFiles))
 			}
 		}
 
-		// Sync overrides folder with optimization
-		overridesPath := filepath.Join(cwd, "overrides")
-		if _, err := os.Stat(overridesPath); err == nil {
-			// Get overrides diff to optimize sync
-			added, modified, removed, err := linkState.GetOverridesDiff(cwd)
-			if err != nil {
-				fmt.Printf(util.FormatWarning("warning: failed to calculate overrides diff: %s\n"), err)
-				// Fall back to full sync
-				fmt.Println("\nsyncing overrides (full sync)...")
+		// Sync override folders with optimization, each link gets the overrides of its side
+		if hasOverrideFolders(cwd) {
+			for _, side := range linkSides(linked) {
+				var sideLinks []string
 				for _, linkPath := range linked.Links {
-					if err := copyDir(overridesPath, linkPath); err != nil {
-						fmt.Printf(util.FormatError("failed to sync overrides to %s: %s\n"), linkPath, err)
-					} else {
-						fmt.Printf(util.FormatSuccess("%s (overrides synced)\n"), linkPath)
+					if linked.Side(linkPath) == side {
+						sideLinks = append(sideLinks, linkPath)
 					}
 				}
-			} else {
+
+				// Get overrides diff to optimize sync
+				added, modified, removed, err := linkState.GetOverridesDiff(cwd, side)
+				if err != nil {
+					fmt.Printf(util.FormatWarning("warning: failed to calculate overrides diff: %s\n"), err)
+					// Fall back to full sync
+					fmt.Printf("\nsyncing %s overrides (full sync)...\n", side)
+					for _, linkPath := range sideLinks {
+						if err := syncOverrideFolders(cwd, side, linkPath); err != nil {
+							fmt.Printf(util.FormatError("failed to sync overrides to %s: %s\n"), linkPath, err)
+						} else {
+							fmt.Printf(util.FormatSuccess("%s (overrides synced)\n"), linkPath)
+						}
+					}
+					continue
+				}
+
 				// Optimized sync based on diff
 				totalChanges := len(added) + len(modified) + len(removed)
 				if totalChanges == 0 {
-					fmt.Println("\noverrides already up to date")
-				} else {
-					fmt.Printf("\nsyncing overrides (%d added, %d modified, %d removed)...\n", len(added), len(modified), len(removed))
-
-					for _, linkPath := range linked.Links {
-						changeCount := 0
-
-						// Remove deleted files
-						for _, removedFile := range removed {
-							targetPath := filepath.Join(linkPath, removedFile)
-							if _, err := os.Stat(targetPath); err == nil {
-								if err := os.Remove(targetPath); err != nil {
-									fmt.Printf(util.FormatWarning("failed to remove %s from %s: %s\n"), removedFile, linkPath, err)
-								} else {
-									changeCount++
-								}
-							}
-						}
-
-						// Copy added and modified files
-						for _, file := range append(added, modified...) {
-							srcPath := filepath.Join(overridesPath, file)
-							destPath := filepath.Join(linkPath, file)
+					fmt.Printf("\n%s overrides already up to date\n", side)
+					continue
+				}
+				fmt.Printf("\nsyncing %s overrides (%d added, %d modified, %d removed)...\n", side, len(added), len(modified), len(removed))
 
-							// Ensure subdirectory exists
-							destDir := filepath.Dir(destPath)
-							if err := os.MkdirAll(destDir, 0755); err != nil {
-								fmt.Printf(util.FormatWarning("failed to create directory %s: %s\n"), destDir, err)
-								continue
-							}
+				for _, linkPath := range sideLinks {
+					changeCount := 0
 
-							if err := copyFile(srcPath, destPath); err != nil {
-								fmt.Printf(util.FormatWarning("failed to copy %s to %s: %s\n"), file, linkPath, err)
+					// Remove deleted files
+					for _, removedFile := range removed {
+						targetPath := filepath.Join(linkPath, removedFile)
+						if _, err := os.Stat(targetPath); err == nil {
+							if err := os.Remove(targetPath); err != nil {
+								fmt.Printf(util.FormatWarning("failed to remove %s from %s: %s\n"), removedFile, linkPath, err)
 							} else {
 								changeCount++
 							}
 						}
+					}
 
-						fmt.Printf(util.FormatSuccess("%s (%d changes applied)\n"), linkPath, changeCount)
+					// Copy added and modified files, from the side's own overrides when it has them
+					for _, file := range append(added, modified...) {
+						srcPath := overrideSource(cwd, side, file)
+						destPath := filepath.Join(linkPath, file)
+
+						// Ensure subdirectory exists
+						destDir := filepath.Dir(destPath)
+						if err := os.MkdirAll(destDir, 0755); err != nil {
+							fmt.Printf(util.FormatWarning("failed to create directory %s: %s\n"), destDir, err)
+							continue
+						}
+
+						if err := copyFile(srcPath, destPath); err != nil {
+							fmt.Printf(util.FormatWarning("failed to copy %s to %s: %s\n"), file, linkPath, err)
+						} else {
+							changeCount++
+						}
 					}
+
+					fmt.Printf(util.FormatSuccess("%s (%d changes applied)\n"), linkPath, changeCount)
 				}
 			}
 
@@ -780,5 +559,5 @@ func init() {
 	// Add filtering flags
 	linkUpdateCmd.Flags().Bool("server-only", false, "only download server-side mods")
 	linkUpdateCmd.Flags().Bool("client-only", false, "only download client-side mods")
-	linkUpdateCmd.Flags().String("source", "", "only download from specific source (modrinth|curseforge)")
+	linkUpdateCmd.Flags().String("source", "", "only download from specific source (e.g. modrinth, curseforge)")
 }
@@ -17,6 +17,8 @@ import (
 
 var modrinthStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#48cf7aff"))
 var curseforgeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#ef7d4fff"))
+var githubStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#b08ef0ff"))
+var mavenStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#c4566bff"))
 var customStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
 var boldStyle = lipgloss.NewStyle().Bold(true)
 var grayStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
@@ -58,6 +60,10 @@ func formatListItem(data project.ContentData, sourceWidth, nameWidth, slugWidth,
 		styledSource = modrinthStyle.Render(sourceStr)
 	case project.Curseforge:
 		styledSource = curseforgeStyle.Render(sourceStr)
+	case project.GitHub:
+		styledSource = githubStyle.Render(sourceStr)
+	case project.Maven:
+		styledSource = mavenStyle.Render(sourceStr)
 	case project.Custom:
 		styledSource = customStyle.Render(sourceStr)
 	default:
@@ -95,6 +101,10 @@ func formatListItem(data project.ContentData, sourceWidth, nameWidth, slugWidth,
 		styledUrl = modrinthStyle.Render(urlToShow)
 	case project.Curseforge:
 		styledUrl = curseforgeStyle.Render(urlToShow)
+	case project.GitHub:
+		styledUrl = githubStyle.Render(urlToShow)
+	case project.Maven:
+		styledUrl = mavenStyle.Render(urlToShow)
 	case project.Custom:
 		styledUrl = customStyle.Render(urlToShow)
 	default:
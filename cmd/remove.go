@@ -365,6 +365,14 @@ var removeCmd = &cobra.Command{
 			}
 		}
 
+		// the removals are committed together, or not at all if they stop part way
+		batch, err := packData.Begin(cmd.Context())
+		if err != nil {
+			fmt.Printf(util.FormatError("error starting changes: %s\n"), err)
+			return
+		}
+		defer batch.Rollback()
+
 		// perform the removal
 		successCount := 0
 		for _, modToRemove := range modsToRemove {
@@ -379,6 +387,9 @@ var removeCmd = &cobra.Command{
 
 			// then remove the mod itself
 			err = packData.RemoveContent(modToRemove.Slug)
+			if interrupted(cmd, "nothing was removed") {
+				return
+			}
 			if err != nil {
 				fmt.Printf(util.FormatError("error removing %s: %s\n"), modToRemove.Name, err)
 			} else {
@@ -387,6 +398,15 @@ var removeCmd = &cobra.Command{
 			}
 		}
 
+		if successCount > 0 {
+			if err := batch.Commit(fmt.Sprintf("Remove content: %d mods", successCount)); err != nil {
+				if interrupted(cmd, "nothing was removed") {
+					return
+				}
+				fmt.Printf(util.FormatWarning("warning: failed to commit changes: %s\n"), err)
+			}
+		}
+
 		// Save link state with tracked removed files
 		if err := SaveLinkState(cwd, linkState); err != nil {
 			fmt.Printf(util.FormatWarning("warning: failed to save link state: %s\n"), err)
@@ -1,6 +1,22 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"minepack/core"
+	"minepack/core/api"
+	"minepack/core/cache"
+	"minepack/core/config"
+
+	// content source providers register themselves with core/api
+	_ "minepack/core/api/curseforge"
+	_ "minepack/core/api/github"
+	_ "minepack/core/api/maven"
+	_ "minepack/core/api/modrinth"
+
+	"minepack/core/download"
+	"minepack/core/project"
+	"minepack/util"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -15,8 +31,25 @@ var rootCmd = &cobra.Command{
 	Short:   "a command line tool for managing minecraft modpacks",
 	Long: `minepack is a command line tool that provides various commands for 
 managing and processing minecraft modpacks.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		cmd.SetContext(ctx)
+		cancelCommand = cancel
+
+		offline, _ := cmd.Flags().GetBool("offline")
+		if offline || os.Getenv("MINEPACK_OFFLINE") != "" {
+			core.SetOffline(true)
+		}
+		refresh, _ := cmd.Flags().GetBool("refresh")
+		core.SetRefresh(refresh)
+		configureSources()
+	},
 }
 
+// cancelCommand cancels the running command's context, like ctrl+c does when it arrives as a
+// signal. progress bars that read ctrl+c as a key use it to stop the command
+var cancelCommand context.CancelFunc = func() {}
+
 // GetRootCmd returns the root command for use with fang
 func GetRootCmd() *cobra.Command {
 	return rootCmd
@@ -31,12 +64,141 @@ func Execute() {
 	}
 }
 
+// interrupted reports whether the user pressed ctrl+c, telling them what was left undone
+func interrupted(cmd *cobra.Command, outcome string) bool {
+	if cmd.Context().Err() == nil {
+		return false
+	}
+	fmt.Printf(util.FormatWarning("interrupted, %s\n"), outcome)
+	return true
+}
+
+// configureSources applies network settings from the user config, and API settings from it and
+// the project in the current directory, if there is one, to every source
+func configureSources() {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Printf(util.FormatWarning("warning: ignoring user config: %s\n"), err)
+		cfg = &config.Config{}
+	}
+	if err := core.ConfigureNetwork(cfg.Network); err != nil {
+		fmt.Printf(util.FormatWarning("warning: %s\n"), err)
+	}
+
+	var packData *project.Project
+	if cwd, err := os.Getwd(); err == nil {
+		packData, _ = project.ParseProject(cwd)
+	}
+
+	if err := api.Configure(cfg.Sources, packData); err != nil {
+		fmt.Printf(util.FormatWarning("warning: %s\n"), err)
+	}
+}
+
+// newDownloadManager creates a download manager using the global --concurrency flag and the
+// user download cache. projectRoot is recorded in the cache so shared files can be reported
+func newDownloadManager(cmd *cobra.Command, projectRoot string) *download.Manager {
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	manager := download.NewManager(concurrency)
+	manager.Interrupt = cancelCommand
+
+	c, err := cache.Open()
+	if err != nil {
+		fmt.Printf(util.FormatWarning("warning: download cache unavailable, files will not be shared between projects: %s\n"), err)
+		return manager
+	}
+	manager.Cache = c
+	manager.Project = projectRoot
+	return manager
+}
+
+// recordComputedHashes saves hashes worked out while downloading content that was added without
+// them, like github release assets, so later downloads can be verified. contents lines up with
+// the jobs the results came from
+func recordComputedHashes(ctx context.Context, packData *project.Project, contents []project.ContentData, results []download.Result) {
+	batch, err := packData.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer batch.Rollback()
+
+	recorded := make(map[string]bool)
+	for i, result := range results {
+		if ctx.Err() != nil {
+			return
+		}
+		content := contents[i]
+		if result.Err != nil || result.Hashes.Sha512 == "" || recorded[content.Slug] {
+			continue
+		}
+		if content.File.Hashes.Sha512 != "" || content.File.Hashes.Sha1 != "" {
+			continue
+		}
+		recorded[content.Slug] = true
+
+		content.File.Hashes = result.Hashes
+		if err := packData.UpdateContent(content); err != nil {
+			fmt.Printf(util.FormatWarning("failed to save hashes for %s: %s\n"), content.Name, err)
+		}
+	}
+	if len(recorded) > 0 {
+		_ = batch.Commit("Record computed file hashes")
+	}
+}
+
+// requireOnline prints an error and returns false when a command that needs the network runs offline
+func requireOnline(action string) bool {
+	if core.IsOffline() {
+		fmt.Printf(util.FormatError("%s needs network access and can't run in offline mode\n"), action)
+		return false
+	}
+	return true
+}
+
+// applySourceFlags overrides the pack's default source from --source, --modrinth or --curseforge,
+// printing an error and returning false if they conflict or name an unknown source
+func applySourceFlags(cmd *cobra.Command, packData *project.Project) bool {
+	source, _ := cmd.Flags().GetString("source")
+	useModrinth, _ := cmd.Flags().GetBool("modrinth")
+	useCurseforge, _ := cmd.Flags().GetBool("curseforge")
+
+	set := 0
+	for _, used := range []bool{source != "", useModrinth, useCurseforge} {
+		if used {
+			set++
+		}
+	}
+	if set > 1 {
+		fmt.Println(util.FormatError("only one of --source, --modrinth and --curseforge can be used\n"))
+		return false
+	}
+
+	if useModrinth {
+		source = "modrinth"
+	} else if useCurseforge {
+		source = "curseforge"
+	}
+	if source == "" {
+		return true
+	}
+
+	if _, err := api.Get(source); err != nil {
+		fmt.Printf(util.FormatError("invalid source: %s\n"), err)
+		return false
+	}
+	packData.DefaultSource = source
+	return true
+}
+
 func init() {
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 
 	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.minepack.yaml)")
+	rootCmd.PersistentFlags().Int("concurrency", download.DefaultConcurrency, "number of files to download in parallel")
+	rootCmd.PersistentFlags().Bool("offline", false, "never touch the network, only use cached files and metadata (also enabled by MINEPACK_OFFLINE)")
+	rootCmd.PersistentFlags().Bool("refresh", false, "revalidate cached API and version metadata with the server instead of reusing it")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
@@ -22,6 +22,10 @@ var searchCmd = &cobra.Command{
 	Short: "search for a mod",
 	Long:  `search for a minecraft mod. by default, will use project data from your current directory, otherwise will use default values.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if !requireOnline("search") {
+			return
+		}
+
 		cwd, err := os.Getwd()
 		if err != nil {
 			fmt.Printf(util.FormatError("error getting current working directory: %s"), err)
@@ -30,27 +34,17 @@ var searchCmd = &cobra.Command{
 		packData, err := project.ParseProject(cwd)
 		if err != nil {
 			packData = &project.Project{
-				DefaultSource: "modrinth",
+				DefaultSource: api.DefaultProvider,
 			}
 		}
 
 		// Get flags
 		modloader, _ := cmd.Flags().GetString("modloader")
 		version, _ := cmd.Flags().GetString("version")
-		useModrinth, _ := cmd.Flags().GetBool("modrinth")
-		useCurseforge, _ := cmd.Flags().GetBool("curseforge")
-
-		// Validate source flags
-		if useModrinth && useCurseforge {
-			fmt.Println(util.FormatError("cannot specify both --modrinth and --curseforge flags\n"))
-			return
-		}
 
 		// Override packData default source if flags are provided
-		if useModrinth {
-			packData.DefaultSource = "modrinth"
-		} else if useCurseforge {
-			packData.DefaultSource = "curseforge"
+		if !applySourceFlags(cmd, packData) {
+			return
 		}
 
 		// override packData if flags are provided
@@ -93,11 +87,15 @@ var searchCmd = &cobra.Command{
 		err = spinner.New().
 			Title("searching for mods...").
 			Type(spinner.Dots).
+			Context(cmd.Context()).
 			Action(func() {
-				result, searchErr = api.SearchAll(query, *packData)
+				result, searchErr = api.SearchAll(cmd.Context(), query, *packData)
 			}).
 			Run()
 
+		if interrupted(cmd, "search cancelled") {
+			return
+		}
 		if err != nil {
 			fmt.Printf(util.FormatError("spinner error: %s"), err)
 			return
@@ -124,6 +122,7 @@ func init() {
 	searchCmd.Flags().StringP("version", "v", "", "specify the Minecraft version (e.g. 1.20.1)")
 	searchCmd.Flags().Bool("modrinth", false, "search mods from Modrinth only")
 	searchCmd.Flags().Bool("curseforge", false, "search mods from CurseForge only")
+	searchCmd.Flags().String("source", "", "search mods from a specific source (e.g. modrinth, curseforge)")
 
 	// Here you will define your flags and configuration settings.
 
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"minepack/core"
 	"minepack/util"
 	"minepack/util/version"
 	"net/http"
@@ -124,6 +125,10 @@ var selfupdateCmd = &cobra.Command{
 	Short: "update minepack to the latest version",
 	Long:  `downloads and installs the latest version of minepack from GitHub releases`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if !requireOnline("selfupdate") {
+			return
+		}
+
 		if err := performSelfUpdate(); err != nil {
 			fmt.Printf(util.FormatError("failed to update: %s"), err)
 			return
@@ -233,7 +238,7 @@ func performSelfUpdate() error {
 func getLatestRelease() (*GitHubRelease, error) {
 	url := "https://api.github.com/repos/ayeuhugyu/minepack/releases/latest"
 
-	resp, err := http.Get(url)
+	resp, err := core.NewHTTPClient(0).Get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -300,7 +305,7 @@ func getAssetNameForPlatformLegacy() []string {
 
 // downloadBinary downloads a binary from URL to a temporary file with progress bar
 func downloadBinary(url string) (string, error) {
-	resp, err := http.Get(url)
+	resp, err := core.NewDownloadClient().Get(url)
 	if err != nil {
 		return "", err
 	}
@@ -367,7 +372,7 @@ func downloadBinary(url string) (string, error) {
 
 // downloadBinarySimple downloads without progress bar (fallback)
 func downloadBinarySimple(url string) (string, error) {
-	resp, err := http.Get(url)
+	resp, err := core.NewDownloadClient().Get(url)
 	if err != nil {
 		return "", err
 	}
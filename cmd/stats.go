@@ -70,6 +70,8 @@ var statsCmd = &cobra.Command{
 		// count mods by source
 		modrinthCount := 0
 		curseforgeCount := 0
+		githubCount := 0
+		mavenCount := 0
 		customCount := 0
 
 		for _, content := range allContent {
@@ -78,6 +80,10 @@ var statsCmd = &cobra.Command{
 				modrinthCount++
 			case project.Curseforge:
 				curseforgeCount++
+			case project.GitHub:
+				githubCount++
+			case project.Maven:
+				mavenCount++
 			case project.Custom:
 				customCount++
 			}
@@ -121,6 +127,12 @@ var statsCmd = &cobra.Command{
 			if curseforgeCount > 0 {
 				statsContent += statsLabelStyle.Render("  • CurseForge: ") + curseforgeStyle.Render(fmt.Sprintf("%d", curseforgeCount)) + "\n"
 			}
+			if githubCount > 0 {
+				statsContent += statsLabelStyle.Render("  • GitHub: ") + githubStyle.Render(fmt.Sprintf("%d", githubCount)) + "\n"
+			}
+			if mavenCount > 0 {
+				statsContent += statsLabelStyle.Render("  • Maven: ") + mavenStyle.Render(fmt.Sprintf("%d", mavenCount)) + "\n"
+			}
 			if customCount > 0 {
 				statsContent += statsLabelStyle.Render("  • Custom: ") + customStyle.Render(fmt.Sprintf("%d", customCount)) + "\n"
 			}
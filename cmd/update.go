@@ -0,0 +1,211 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"minepack/core/api"
+	"minepack/core/project"
+	"minepack/util"
+	"os"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/spf13/cobra"
+)
+
+// contentUpdate pairs installed content with the newer version that replaces it
+type contentUpdate struct {
+	current project.ContentData
+	latest  project.ContentData
+}
+
+// findUpdates asks the content's providers for the newest compatible version of each content
+func findUpdates(ctx context.Context, packData *project.Project, contents []project.ContentData) []contentUpdate {
+	latest, errs := api.LatestVersions(ctx, contents, *packData)
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	var updates []contentUpdate
+	for _, content := range contents {
+		if err := errs[content.Slug]; err != nil {
+			if !errors.Is(err, api.ErrUnsupported) {
+				fmt.Printf(util.FormatWarning("failed to check %s for updates: %s\n"), content.Name, err)
+			}
+			continue
+		}
+		if found, ok := latest[content.Slug]; ok && api.IsNewer(content, *found) {
+			updates = append(updates, contentUpdate{current: content, latest: *found})
+		}
+	}
+	return updates
+}
+
+// applyContentUpdate replaces content with its newer version. the old file is removed from linked
+// instances on the next link update
+func applyContentUpdate(packData *project.Project, u contentUpdate, linkState *LinkState) error {
+	updated := u.latest
+	// keep what the pack knows about the mod, only the version changes
+	updated.Slug = u.current.Slug
+	updated.RequiredBy = u.current.RequiredBy
+	updated.AddedAsDependency = u.current.AddedAsDependency
+	if updated.SourceOptions == nil {
+		updated.SourceOptions = u.current.SourceOptions
+	}
+
+	if u.current.File.Filepath != "" && u.current.File.Filepath != updated.File.Filepath {
+		linkState.AddRemovedFile(u.current.File.Filepath)
+	}
+	return packData.UpdateContent(updated)
+}
+
+// updateCmd represents the update command
+var updateCmd = &cobra.Command{
+	Use:   "update [slug...]",
+	Short: "update mods to their newest versions",
+	Long: `checks every mod (or only the given ones) for a newer version compatible with the pack and updates them.
+github content is compared by release tag, other sources by version id. pinned mods are never updated.`,
+	Aliases: []string{"upgrade"},
+	Run: func(cmd *cobra.Command, args []string) {
+		if !requireOnline("update") {
+			return
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting current working directory: %s"), err)
+			return
+		}
+
+		packData, err := project.ParseProject(cwd)
+		if err != nil {
+			fmt.Printf(util.FormatError("error parsing project: %s"), err)
+			return
+		}
+
+		var contents []project.ContentData
+		if len(args) > 0 {
+			for _, arg := range args {
+				content, err := packData.GetContent(arg)
+				if err != nil {
+					fmt.Printf(util.FormatError("no mod found for %s: %s\n"), arg, err)
+					return
+				}
+				if content.Pinned {
+					fmt.Printf(util.FormatWarning("%s is pinned, skipping it\n"), content.Slug)
+					continue
+				}
+				contents = append(contents, *content)
+			}
+		} else {
+			allContent, err := packData.GetAllContent()
+			if err != nil {
+				fmt.Printf(util.FormatError("error getting all content: %s"), err)
+				return
+			}
+			// pinned content stays at its version
+			for _, content := range allContent {
+				if !content.Pinned {
+					contents = append(contents, content)
+				}
+			}
+		}
+
+		var updates []contentUpdate
+		err = spinner.New().
+			Title(fmt.Sprintf("checking %d mods for updates...", len(contents))).
+			Type(spinner.Dots).
+			Context(cmd.Context()).
+			Action(func() {
+				updates = findUpdates(cmd.Context(), packData, contents)
+			}).
+			Run()
+		if interrupted(cmd, "nothing was updated") {
+			return
+		}
+		if err != nil {
+			fmt.Printf(util.FormatError("spinner error: %s"), err)
+			return
+		}
+
+		if len(updates) == 0 {
+			fmt.Println(util.FormatSuccess("everything is up to date"))
+			return
+		}
+
+		fmt.Printf("%d updates available:\n", len(updates))
+		for _, u := range updates {
+			fmt.Printf("- %s: %s -> %s\n", u.current.Name, u.current.VersionId, u.latest.VersionId)
+		}
+
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+			confirm := false
+			huh.NewConfirm().
+				Title("apply these updates?").
+				Affirmative("yes, update").
+				Negative("cancel").
+				Value(&confirm).
+				Run()
+			if !confirm {
+				fmt.Println("update cancelled.")
+				return
+			}
+		}
+
+		// old files are removed from linked instances on the next link update
+		linkState, err := LoadLinkState(cwd)
+		if err != nil {
+			fmt.Printf(util.FormatWarning("warning: failed to load link state: %s\n"), err)
+			linkState = &LinkState{
+				RemovedFiles:   []string{},
+				OverridesFiles: make(map[string]string),
+				Version:        "1.0",
+			}
+		}
+
+		// every update is committed together, or none are if applying them stops part way
+		batch, err := packData.Begin(cmd.Context())
+		if err != nil {
+			fmt.Printf(util.FormatError("error starting changes: %s\n"), err)
+			return
+		}
+		defer batch.Rollback()
+
+		successCount := 0
+		for _, u := range updates {
+			if err := applyContentUpdate(packData, u, linkState); err != nil {
+				if interrupted(cmd, "nothing was updated") {
+					return
+				}
+				fmt.Printf(util.FormatError("failed to update %s: %s\n"), u.current.Name, err)
+				continue
+			}
+			successCount++
+		}
+
+		if successCount > 0 {
+			if err := batch.Commit(fmt.Sprintf("Update content: %d mods", successCount)); err != nil {
+				if interrupted(cmd, "nothing was updated") {
+					return
+				}
+				fmt.Printf(util.FormatWarning("warning: failed to commit changes: %s\n"), err)
+			}
+		}
+
+		if err := SaveLinkState(cwd, linkState); err != nil {
+			fmt.Printf(util.FormatWarning("warning: failed to save link state: %s\n"), err)
+		}
+
+		fmt.Printf(util.FormatSuccess("successfully updated %d of %d mods\n"), successCount, len(updates))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(updateCmd)
+
+	updateCmd.Flags().BoolP("yes", "y", false, "apply updates without asking for confirmation")
+}
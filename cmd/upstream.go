@@ -0,0 +1,84 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"minepack/core"
+	"minepack/core/project"
+	"minepack/util"
+	"os"
+
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/spf13/cobra"
+)
+
+// upstreamCmd represents the upstream command
+var upstreamCmd = &cobra.Command{
+	Use:   "upstream",
+	Short: "track the published modpack the project is based on",
+	Long: `a project can be based on a modpack published on modrinth or curseforge, its upstream, and add
+or remove content of its own. importing a published modpack sets it, "minepack upstream set" links
+an existing project to one.
+
+without a subcommand, shows the upstream and whether a newer version of it is out. use
+"minepack upstream sync" to apply a newer version's changes to the project.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting current working directory: %s"), err)
+			return
+		}
+
+		packData, err := project.ParseProject(cwd)
+		if err != nil {
+			fmt.Printf(util.FormatError("error parsing project: %s"), err)
+			return
+		}
+		if packData.Upstream == nil {
+			fmt.Println("the project has no upstream, set one with \"minepack upstream set\"")
+			return
+		}
+		current := *packData.Upstream
+
+		fmt.Printf("upstream: %s on %s\n", upstreamName(current), current.Source)
+		fmt.Printf("based on: %s\n", upstreamVersionName(current))
+
+		if core.IsOffline() {
+			return
+		}
+
+		var newest project.Upstream
+		var resolveErr error
+		err = spinner.New().
+			Title("checking for a newer version...").
+			Type(spinner.Dots).
+			Context(cmd.Context()).
+			Action(func() {
+				_, newest, resolveErr = resolvePublishedPack(cmd.Context(), current.Source, current.Id, "")
+			}).
+			Run()
+		if interrupted(cmd, "nothing was checked") {
+			return
+		}
+		if err != nil {
+			fmt.Printf(util.FormatError("spinner error: %s"), err)
+			return
+		}
+		if resolveErr != nil {
+			fmt.Printf(util.FormatWarning("failed to check for a newer version: %s\n"), resolveErr)
+			return
+		}
+
+		if newest.VersionId == current.VersionId {
+			fmt.Println(util.FormatSuccess("up to date with upstream"))
+			return
+		}
+		fmt.Printf("newest: %s, run \"minepack upstream sync\" to apply its changes\n", upstreamVersionName(newest))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(upstreamCmd)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"minepack/core/project"
+	"minepack/util"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/spf13/cobra"
+)
+
+// upstreamSetCmd represents the upstream set command
+var upstreamSetCmd = &cobra.Command{
+	Use:   "set <pack>",
+	Short: "set the published modpack the project is based on",
+	Long: `links the project to a modpack published on modrinth or curseforge, given as "modrinth:<slug>[@version]"
+or "curseforge:<id>[@fileId]", at the version the project is based on. the newest version is used
+when none is given, later syncs apply what changed upstream since that version.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if !requireOnline("upstream set") {
+			return
+		}
+
+		source, ref, ok := strings.Cut(args[0], ":")
+		if !ok || (source != "modrinth" && source != "curseforge") {
+			fmt.Println(util.FormatError("expected modrinth:<slug>[@version] or curseforge:<id>[@fileId]"))
+			return
+		}
+		id, versionRef, _ := strings.Cut(ref, "@")
+		if id == "" {
+			fmt.Printf(util.FormatError("no modpack given, expected %s:<id>[@version]\n"), source)
+			return
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting current working directory: %s"), err)
+			return
+		}
+
+		packData, err := project.ParseProject(cwd)
+		if err != nil {
+			fmt.Printf(util.FormatError("error parsing project: %s"), err)
+			return
+		}
+
+		var upstream project.Upstream
+		var resolveErr error
+		err = spinner.New().
+			Title(fmt.Sprintf("looking up %s on %s...", id, source)).
+			Type(spinner.Dots).
+			Context(cmd.Context()).
+			Action(func() {
+				_, upstream, resolveErr = resolvePublishedPack(cmd.Context(), source, id, versionRef)
+			}).
+			Run()
+		if interrupted(cmd, "the upstream wasn't changed") {
+			return
+		}
+		if err != nil {
+			fmt.Printf(util.FormatError("spinner error: %s"), err)
+			return
+		}
+		if resolveErr != nil {
+			fmt.Printf(util.FormatError("failed to look up upstream: %s\n"), resolveErr)
+			return
+		}
+
+		packData.Upstream = &upstream
+		if err := packData.Save(fmt.Sprintf("Set upstream: %s %s", upstreamName(upstream), upstreamVersionName(upstream))); err != nil {
+			fmt.Printf(util.FormatError("failed to save the project: %s\n"), err)
+			return
+		}
+
+		fmt.Printf(util.FormatSuccess("upstream set to %s %s\n"), upstreamName(upstream), upstreamVersionName(upstream))
+	},
+}
+
+func init() {
+	upstreamCmd.AddCommand(upstreamSetCmd)
+}
@@ -0,0 +1,218 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"minepack/core/api/curseforge"
+	mymodrinth "minepack/core/api/modrinth"
+	"minepack/core/download"
+	"minepack/core/project"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// upstreamSnapshot is what one version of an upstream modpack contains, laid out the way
+// importing that version would lay it out in a project
+type upstreamSnapshot struct {
+	dir     string // where the pack was extracted, override files are copied from here
+	game    string
+	loader  project.ModloaderVersion
+	content map[string]project.ContentData // by contentKey
+	files   map[string]upstreamFile        // override files by slash separated path in the project, e.g. overrides/config/sodium.json
+}
+
+// upstreamFile is an override file of an upstream pack, either extracted from it or listed in its
+// index to be downloaded
+type upstreamFile struct {
+	sha1     string
+	source   string        // the extracted file, empty when the file is downloaded
+	download *download.Job // without a destination, it is set when the file is written
+}
+
+// contentKey identifies content across pack versions and projects, whatever its slug
+func contentKey(content project.ContentData) string {
+	return project.SourceToString(content.Source) + ":" + content.Id
+}
+
+// readUpstreamSnapshot extracts a downloaded modpack into dir and reads what it contains
+func readUpstreamSnapshot(ctx context.Context, packPath string, dir string) (*upstreamSnapshot, error) {
+	format, err := packArchiveFormat(packPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := extractArchive(ctx, packPath, dir); err != nil {
+		return nil, err
+	}
+
+	snapshot := &upstreamSnapshot{
+		dir:     dir,
+		content: make(map[string]project.ContentData),
+		files:   make(map[string]upstreamFile),
+	}
+	if format == "curseforge" {
+		err = snapshot.readCurseforge(ctx)
+	} else {
+		err = snapshot.readModrinth(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// readModrinth reads an extracted .mrpack. like an import, jars in mods/ that modrinth knows
+// become content and every other file in the index is downloaded into the overrides
+func (s *upstreamSnapshot) readModrinth(ctx context.Context) error {
+	indexData, err := os.ReadFile(filepath.Join(s.dir, "modrinth.index.json"))
+	if err != nil {
+		return fmt.Errorf("failed to read modrinth.index.json: %w", err)
+	}
+	var pack ModrinthPack
+	if err := json.Unmarshal(indexData, &pack); err != nil {
+		return fmt.Errorf("failed to parse modrinth.index.json: %w", err)
+	}
+	s.game = pack.Dependencies["minecraft"]
+	s.loader = modrinthPackLoader(pack.Dependencies)
+
+	// mods are matched the same way an import matches them, see lookupPackHashes
+	var fileHashes []map[string]string
+	for _, file := range pack.Files {
+		if strings.HasPrefix(file.Path, "mods/") && strings.HasSuffix(file.Path, ".jar") && packFileHash(file.Hashes) != "" {
+			fileHashes = append(fileHashes, file.Hashes)
+		}
+	}
+	versions, err := lookupPackHashes(ctx, fileHashes)
+	if err != nil {
+		return fmt.Errorf("failed to look up mods: %w", err)
+	}
+	var projectIDs []string
+	for _, version := range versions {
+		if version.ProjectID != nil {
+			projectIDs = append(projectIDs, *version.ProjectID)
+		}
+	}
+	projects, err := mymodrinth.GetProjects(ctx, projectIDs)
+	if err != nil {
+		return fmt.Errorf("failed to fetch project details: %w", err)
+	}
+	if err := prefetchVersionDependencies(ctx, versions); err != nil {
+		return fmt.Errorf("failed to fetch project details: %w", err)
+	}
+
+	for _, file := range pack.Files {
+		if version, ok := versions[packFileHash(file.Hashes)]; ok && version.ProjectID != nil && strings.HasPrefix(file.Path, "mods/") {
+			if proj, ok := projects[*version.ProjectID]; ok {
+				content := mymodrinth.ConvertProjectToContentData(ctx, proj, version)
+				s.content[contentKey(content)] = content
+				continue
+			}
+		}
+		if len(file.Downloads) == 0 || !filepath.IsLocal(filepath.FromSlash(file.Path)) {
+			continue
+		}
+		s.files[path.Join(project.OverridesFolder, file.Path)] = upstreamFile{
+			sha1: file.Hashes["sha1"],
+			download: &download.Job{
+				Name: path.Base(file.Path),
+				URL:  file.Downloads[0],
+				Size: file.FileSize,
+				Hashes: project.Hashes{
+					Sha1:   file.Hashes["sha1"],
+					Sha512: file.Hashes["sha512"],
+				},
+			},
+		}
+	}
+
+	for _, folder := range []string{project.OverridesFolder, project.ClientOverridesFolder, project.ServerOverridesFolder} {
+		if err := s.readOverrides(folder, folder); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readCurseforge reads an extracted curseforge modpack zip, every file in its manifest that
+// curseforge still knows becomes content
+func (s *upstreamSnapshot) readCurseforge(ctx context.Context) error {
+	manifestData, err := os.ReadFile(filepath.Join(s.dir, "manifest.json"))
+	if err != nil {
+		return fmt.Errorf("failed to read manifest.json: %w", err)
+	}
+	var manifest curseforgeManifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return fmt.Errorf("failed to parse manifest.json: %w", err)
+	}
+	s.game = manifest.Minecraft.Version
+	s.loader = curseforgeModLoaderVersion(manifest.Minecraft.ModLoaders)
+
+	var modIDs, fileIDs []int
+	for _, file := range manifest.Files {
+		modIDs = append(modIDs, file.ProjectID)
+		fileIDs = append(fileIDs, file.FileID)
+	}
+	mods, err := curseforge.GetMods(ctx, modIDs)
+	if err != nil {
+		return fmt.Errorf("failed to fetch project details: %w", err)
+	}
+	files, err := curseforge.GetFiles(ctx, fileIDs)
+	if err != nil {
+		return fmt.Errorf("failed to fetch project details: %w", err)
+	}
+	list := make([]*curseforge.File, 0, len(files))
+	for _, file := range files {
+		list = append(list, file)
+	}
+	if err := curseforge.PrefetchDependencies(ctx, list); err != nil {
+		return fmt.Errorf("failed to fetch project details: %w", err)
+	}
+
+	for _, file := range manifest.Files {
+		mod, modFile := mods[file.ProjectID], files[file.FileID]
+		if mod == nil || modFile == nil {
+			continue
+		}
+		content := curseforge.ConvertModToContentData(ctx, mod, modFile)
+		s.content[contentKey(content)] = content
+	}
+
+	overridesFolder := manifest.Overrides
+	if overridesFolder == "" {
+		overridesFolder = "overrides"
+	}
+	if !filepath.IsLocal(filepath.FromSlash(overridesFolder)) {
+		return nil
+	}
+	return s.readOverrides(overridesFolder, project.OverridesFolder)
+}
+
+// readOverrides records every file in one of the pack's override folders under the project's
+// override folder it is copied into
+func (s *upstreamSnapshot) readOverrides(folder string, projectFolder string) error {
+	root := filepath.Join(s.dir, filepath.FromSlash(folder))
+	if _, err := os.Stat(root); err != nil {
+		return nil
+	}
+	return filepath.WalkDir(root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, filePath)
+		if err != nil {
+			return err
+		}
+		hashes, err := download.HashFile(filePath)
+		if err != nil {
+			return err
+		}
+		s.files[path.Join(projectFolder, filepath.ToSlash(rel))] = upstreamFile{sha1: hashes.Sha1, source: filePath}
+		return nil
+	})
+}
@@ -0,0 +1,668 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"minepack/core/download"
+	"minepack/core/project"
+	"minepack/util"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/spf13/cobra"
+)
+
+// upstreamPlan is what syncing to a new upstream version changes in the project, and the local
+// changes it leaves alone because upstream changed the same thing
+type upstreamPlan struct {
+	add       []project.ContentData
+	update    []contentUpdate
+	remove    []project.ContentData
+	write     []string // override files to write from the new version, by path in the project
+	delete    []string // override files to delete, by path in the project
+	game      string   // the new game version, empty when it stays
+	loader    *project.ModloaderVersion
+	conflicts []string
+}
+
+func (p upstreamPlan) isEmpty() bool {
+	return len(p.add) == 0 && len(p.update) == 0 && len(p.remove) == 0 && len(p.write) == 0 &&
+		len(p.delete) == 0 && p.game == "" && p.loader == nil
+}
+
+// planUpstreamSync works out what upstream changed between two of its versions and how to apply
+// that on top of the project. what the project changed itself is what changed since committed, the
+// commit that based it on the older version. a change is applied where the project didn't change
+// the same thing, anything changed on both sides is a conflict and keeps the local change
+func planUpstreamSync(base, target *upstreamSnapshot, committed *project.CommittedState, packData *project.Project, local []project.ContentData) (upstreamPlan, error) {
+	var plan upstreamPlan
+
+	localByKey := make(map[string]project.ContentData, len(local))
+	localSlugs := make(map[string]bool, len(local))
+	for _, content := range local {
+		localByKey[contentKey(content)] = content
+		localSlugs[content.Slug] = true
+	}
+	committedByKey := make(map[string]project.ContentData, len(committed.Content))
+	for _, content := range committed.Content {
+		committedByKey[contentKey(content)] = content
+	}
+
+	var removals []project.ContentData
+	for _, key := range sortedKeys(base.content, target.content) {
+		before, inBase := base.content[key]
+		after, inTarget := target.content[key]
+		current, inLocal := localByKey[key]
+		was, wasCommitted := committedByKey[key]
+		addedHere := inLocal && !wasCommitted
+		removedHere := !inLocal && wasCommitted
+		changedHere := inLocal && wasCommitted && current.VersionId != was.VersionId
+
+		switch {
+		case inBase && inTarget:
+			// updated upstream
+			if before.VersionId == after.VersionId {
+				continue
+			}
+			switch {
+			case removedHere:
+				plan.conflicts = append(plan.conflicts, fmt.Sprintf("%s was updated upstream but removed here, it stays removed", after.Name))
+			case !inLocal, current.VersionId == after.VersionId:
+			case current.Pinned:
+				plan.conflicts = append(plan.conflicts, fmt.Sprintf("%s was updated upstream but is pinned here, it stays at its version", current.Name))
+			case addedHere || changedHere:
+				plan.conflicts = append(plan.conflicts, fmt.Sprintf("%s was updated upstream but its version was changed here, the local version is kept", current.Name))
+			default:
+				plan.update = append(plan.update, contentUpdate{current: current, latest: after})
+			}
+		case inBase:
+			// removed upstream
+			switch {
+			case !inLocal:
+			case current.Pinned:
+				plan.conflicts = append(plan.conflicts, fmt.Sprintf("%s was removed upstream but is pinned here, it is kept", current.Name))
+			case addedHere:
+				plan.conflicts = append(plan.conflicts, fmt.Sprintf("%s was removed upstream but added here, it is kept", current.Name))
+			case changedHere:
+				plan.conflicts = append(plan.conflicts, fmt.Sprintf("%s was removed upstream but its version was changed here, it is kept", current.Name))
+			default:
+				removals = append(removals, current)
+			}
+		default:
+			// added upstream
+			switch {
+			case inLocal && current.VersionId != after.VersionId:
+				plan.conflicts = append(plan.conflicts, fmt.Sprintf("%s was added upstream but is already here at another version, the local version is kept", current.Name))
+			case inLocal:
+			case removedHere:
+				plan.conflicts = append(plan.conflicts, fmt.Sprintf("%s was added upstream but removed here, it stays removed", after.Name))
+			case localSlugs[after.Slug]:
+				plan.conflicts = append(plan.conflicts, fmt.Sprintf("%s was added upstream but the project already has other content called %s, it isn't added", after.Name, after.Slug))
+			default:
+				plan.add = append(plan.add, after)
+			}
+		}
+	}
+
+	// content added here may still need something upstream removed
+	removing := make(map[string]bool, len(removals))
+	for _, content := range removals {
+		removing[content.Slug] = true
+	}
+	for _, content := range removals {
+		var requiredBy string
+		for _, r := range content.RequiredBy {
+			if localSlugs[r.Slug] && !removing[r.Slug] {
+				requiredBy = r.Slug
+				break
+			}
+		}
+		if requiredBy != "" {
+			plan.conflicts = append(plan.conflicts, fmt.Sprintf("%s was removed upstream but %s still requires it, it is kept", content.Name, requiredBy))
+			continue
+		}
+		plan.remove = append(plan.remove, content)
+	}
+
+	for _, filePath := range sortedKeys(base.files, target.files) {
+		before, after := base.files[filePath].sha1, target.files[filePath].sha1
+		if before == after {
+			continue
+		}
+		changed, err := committed.FileChanged(packData.Root, filePath)
+		if err != nil {
+			return upstreamPlan{}, err
+		}
+		if !changed {
+			if after == "" {
+				plan.delete = append(plan.delete, filePath)
+			} else {
+				plan.write = append(plan.write, filePath)
+			}
+			continue
+		}
+
+		current, err := localFileSHA1(packData.Root, filePath)
+		if err != nil {
+			return upstreamPlan{}, err
+		}
+		switch {
+		case current == after:
+		case after == "":
+			plan.conflicts = append(plan.conflicts, fmt.Sprintf("%s was removed upstream but changed here, it is kept", filePath))
+		case current == "":
+			plan.conflicts = append(plan.conflicts, fmt.Sprintf("%s was changed upstream but removed here, it stays removed", filePath))
+		default:
+			plan.conflicts = append(plan.conflicts, fmt.Sprintf("%s was changed both upstream and here, the local file is kept", filePath))
+		}
+	}
+
+	was := committed.Project.Versions
+	if base.game != target.game {
+		switch packData.Versions.Game {
+		case target.game:
+		case was.Game:
+			plan.game = target.game
+		default:
+			plan.conflicts = append(plan.conflicts, fmt.Sprintf("the game version changed upstream from %s to %s but was changed to %s here, it is kept", base.game, target.game, packData.Versions.Game))
+		}
+	}
+	if base.loader != target.loader {
+		switch packData.Versions.Loader {
+		case target.loader:
+		case was.Loader:
+			plan.loader = &target.loader
+		default:
+			plan.conflicts = append(plan.conflicts, fmt.Sprintf("the modloader changed upstream from %s to %s but was changed to %s here, it is kept",
+				formatLoader(base.loader), formatLoader(target.loader), formatLoader(packData.Versions.Loader)))
+		}
+	}
+
+	return plan, nil
+}
+
+// sortedKeys lists the keys found in either map, sorted
+func sortedKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// localFileSHA1 hashes a file in the project, returning an empty hash when it doesn't exist
+func localFileSHA1(root string, filePath string) (string, error) {
+	hashes, err := download.HashFile(filepath.Join(root, filepath.FromSlash(filePath)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return hashes.Sha1, nil
+}
+
+func formatLoader(loader project.ModloaderVersion) string {
+	if loader.Name == "" {
+		return "none"
+	}
+	return fmt.Sprintf("%s %s", loader.Name, loader.Version)
+}
+
+// upstreamName is how an upstream is shown, by slug where it has one
+func upstreamName(upstream project.Upstream) string {
+	if upstream.Slug != "" {
+		return upstream.Slug
+	}
+	return upstream.Id
+}
+
+// upstreamVersionName is how an upstream's version is shown, by name where it has one
+func upstreamVersionName(upstream project.Upstream) string {
+	if upstream.Version != "" {
+		return upstream.Version
+	}
+	return upstream.VersionId
+}
+
+// fetchUpstreamSnapshot downloads a version of an upstream modpack and reads what it contains.
+// cleanup removes the downloaded and extracted files, which writing overrides still copies from
+func fetchUpstreamSnapshot(ctx context.Context, manager *download.Manager, packFile project.ContentData) (*upstreamSnapshot, func(), error) {
+	packPath, cleanup, err := downloadPublishedPack(ctx, manager, packFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var snapshot *upstreamSnapshot
+	var readErr error
+	err = spinner.New().
+		Title(fmt.Sprintf("reading %s...", filepath.Base(packPath))).
+		Type(spinner.Dots).
+		Context(ctx).
+		Action(func() {
+			snapshot, readErr = readUpstreamSnapshot(ctx, packPath, filepath.Join(filepath.Dir(packPath), "extracted"))
+		}).
+		Run()
+
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err == nil {
+		err = readErr
+	}
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return snapshot, cleanup, nil
+}
+
+// fileBackup keeps the override files a sync writes or deletes as they were before, so a failed
+// sync puts back exactly those files and leaves everything else in the project alone
+type fileBackup struct {
+	files map[string][]byte
+	added map[string]bool // files that didn't exist before
+}
+
+func newFileBackup() *fileBackup {
+	return &fileBackup{files: make(map[string][]byte), added: make(map[string]bool)}
+}
+
+// save remembers a file before it is first changed
+func (b *fileBackup) save(path string) error {
+	if _, ok := b.files[path]; ok || b.added[path] {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		b.added[path] = true
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	b.files[path] = data
+	return nil
+}
+
+// restore puts every saved file back and removes the ones that didn't exist
+func (b *fileBackup) restore() error {
+	var errs []error
+	for path := range b.added {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	for path, data := range b.files {
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// applyUpstreamPlan makes the plan's changes to the project, returning how many were made. override
+// files are saved to backup before they are touched. changes that fail are reported and skipped,
+// only an interruption stops it
+func applyUpstreamPlan(ctx context.Context, manager *download.Manager, packData *project.Project, plan upstreamPlan, target *upstreamSnapshot, linkState *LinkState, backup *fileBackup) (int, error) {
+	applied := 0
+
+	for _, content := range plan.remove {
+		if content.File.Filepath != "" {
+			linkState.AddRemovedFile(content.File.Filepath)
+		}
+		if err := packData.RemoveContent(content.Slug); err != nil {
+			if ctx.Err() != nil {
+				return applied, ctx.Err()
+			}
+			fmt.Printf(util.FormatError("failed to remove %s: %s\n"), content.Name, err)
+			continue
+		}
+		applied++
+	}
+
+	for _, u := range plan.update {
+		if err := applyContentUpdate(packData, u, linkState); err != nil {
+			if ctx.Err() != nil {
+				return applied, ctx.Err()
+			}
+			fmt.Printf(util.FormatError("failed to update %s: %s\n"), u.current.Name, err)
+			continue
+		}
+		applied++
+	}
+
+	for _, content := range plan.add {
+		if err := packData.AddContent(content); err != nil {
+			if ctx.Err() != nil {
+				return applied, ctx.Err()
+			}
+			fmt.Printf(util.FormatError("failed to add %s: %s\n"), content.Name, err)
+			continue
+		}
+		applied++
+	}
+
+	for _, filePath := range plan.delete {
+		dest := filepath.Join(packData.Root, filepath.FromSlash(filePath))
+		if err := backup.save(dest); err != nil {
+			fmt.Printf(util.FormatError("failed to delete %s: %s\n"), filePath, err)
+			continue
+		}
+		if err := os.Remove(dest); err != nil && !os.IsNotExist(err) {
+			fmt.Printf(util.FormatError("failed to delete %s: %s\n"), filePath, err)
+			continue
+		}
+		applied++
+	}
+
+	// files extracted from the pack are copied, the ones its index lists are downloaded
+	var jobs []download.Job
+	for _, filePath := range plan.write {
+		file := target.files[filePath]
+		dest := filepath.Join(packData.Root, filepath.FromSlash(filePath))
+		if err := backup.save(dest); err != nil {
+			fmt.Printf(util.FormatError("failed to write %s: %s\n"), filePath, err)
+			continue
+		}
+		if file.download != nil {
+			job := *file.download
+			job.Dest = dest
+			jobs = append(jobs, job)
+			continue
+		}
+
+		data, err := os.ReadFile(file.source)
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(dest), os.ModePerm)
+		}
+		if err == nil {
+			err = os.WriteFile(dest, data, 0644)
+		}
+		if err != nil {
+			fmt.Printf(util.FormatError("failed to write %s: %s\n"), filePath, err)
+			continue
+		}
+		applied++
+	}
+	if len(jobs) > 0 {
+		fmt.Printf("downloading %d override files...\n", len(jobs))
+		results, err := manager.Run(ctx, jobs)
+		if err != nil {
+			return applied, err
+		}
+		failed := download.Failed(results)
+		for _, result := range failed {
+			fmt.Printf(util.FormatError("failed to download %s: %s\n"), result.Job.Name, result.Err)
+		}
+		applied += len(jobs) - len(failed)
+	}
+
+	if plan.game != "" {
+		packData.Versions.Game = plan.game
+		applied++
+	}
+	if plan.loader != nil {
+		packData.Versions.Loader = *plan.loader
+		applied++
+	}
+
+	return applied, ctx.Err()
+}
+
+// printUpstreamPlan lists what a sync changes and where it conflicts with local changes
+func printUpstreamPlan(plan upstreamPlan) {
+	for _, content := range plan.add {
+		fmt.Printf("- add %s\n", content.Name)
+	}
+	for _, u := range plan.update {
+		fmt.Printf("- update %s: %s -> %s\n", u.current.Name, u.current.VersionId, u.latest.VersionId)
+	}
+	for _, content := range plan.remove {
+		fmt.Printf("- remove %s\n", content.Name)
+	}
+	for _, filePath := range plan.write {
+		fmt.Printf("- write %s\n", filePath)
+	}
+	for _, filePath := range plan.delete {
+		fmt.Printf("- delete %s\n", filePath)
+	}
+	if plan.game != "" {
+		fmt.Printf("- game version: %s\n", plan.game)
+	}
+	if plan.loader != nil {
+		fmt.Printf("- modloader: %s\n", formatLoader(*plan.loader))
+	}
+	for _, conflict := range plan.conflicts {
+		fmt.Printf(util.FormatWarning("conflict: %s\n"), conflict)
+	}
+}
+
+// upstreamSyncCmd represents the upstream sync command
+var upstreamSyncCmd = &cobra.Command{
+	Use:   "sync [version]",
+	Short: "apply the changes of a newer upstream version to the project",
+	Long: `compares the upstream version the project is based on with a newer one (its newest, or the given
+version or curseforge file id) and applies what changed upstream - added, removed and updated mods,
+override files and the game and loader versions - on top of the project's own changes.
+
+the project's own changes are what changed since the commit that based it on its current upstream
+version, i.e. its import, "upstream set" or last sync. anything changed both upstream and in the
+project is reported as a conflict and keeps the local change. uncommitted changes are committed
+first and the sync gets a commit of its own, so it can be looked at or undone with git.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if !requireOnline("upstream sync") {
+			return
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting current working directory: %s"), err)
+			return
+		}
+
+		packData, err := project.ParseProject(cwd)
+		if err != nil {
+			fmt.Printf(util.FormatError("error parsing project: %s"), err)
+			return
+		}
+		if packData.Upstream == nil {
+			fmt.Println(util.FormatError("the project has no upstream, set one with \"minepack upstream set\""))
+			return
+		}
+		current := *packData.Upstream
+
+		var versionArg string
+		if len(args) > 0 {
+			versionArg = args[0]
+		}
+
+		var basePack, targetPack project.ContentData
+		var target project.Upstream
+		var resolveErr error
+		err = spinner.New().
+			Title(fmt.Sprintf("looking up %s on %s...", upstreamName(current), current.Source)).
+			Type(spinner.Dots).
+			Context(cmd.Context()).
+			Action(func() {
+				targetPack, target, resolveErr = resolvePublishedPack(cmd.Context(), current.Source, current.Id, versionArg)
+				if resolveErr != nil || target.VersionId == current.VersionId {
+					return
+				}
+				basePack, _, resolveErr = resolvePublishedPack(cmd.Context(), current.Source, current.Id, current.VersionId)
+			}).
+			Run()
+		if interrupted(cmd, "nothing was synced") {
+			return
+		}
+		if err != nil {
+			fmt.Printf(util.FormatError("spinner error: %s"), err)
+			return
+		}
+		if resolveErr != nil {
+			fmt.Printf(util.FormatError("failed to look up upstream: %s\n"), resolveErr)
+			return
+		}
+		if target.VersionId == current.VersionId {
+			fmt.Println(util.FormatSuccess(fmt.Sprintf("already on %s %s", upstreamName(current), upstreamVersionName(current))))
+			return
+		}
+
+		fmt.Printf("syncing %s %s -> %s\n", upstreamName(current), upstreamVersionName(current), upstreamVersionName(target))
+
+		// local changes are what changed since the upstream version was set, uncommitted ones included
+		committed, err := project.UpstreamBase(packData.Root, current)
+		if err != nil {
+			fmt.Printf(util.FormatError("failed to find what changed since %s: %s\n"), upstreamVersionName(current), err)
+			return
+		}
+
+		manager := newDownloadManager(cmd, "")
+		base, cleanupBase, err := fetchUpstreamSnapshot(cmd.Context(), manager, basePack)
+		if interrupted(cmd, "nothing was synced") {
+			return
+		}
+		if err != nil {
+			fmt.Printf(util.FormatError("failed to read %s: %s\n"), upstreamVersionName(current), err)
+			return
+		}
+		defer cleanupBase()
+		next, cleanupNext, err := fetchUpstreamSnapshot(cmd.Context(), manager, targetPack)
+		if interrupted(cmd, "nothing was synced") {
+			return
+		}
+		if err != nil {
+			fmt.Printf(util.FormatError("failed to read %s: %s\n"), upstreamVersionName(target), err)
+			return
+		}
+		defer cleanupNext()
+
+		local, err := packData.GetAllContent()
+		if err != nil {
+			fmt.Printf(util.FormatError("error getting all content: %s"), err)
+			return
+		}
+		plan, err := planUpstreamSync(base, next, committed, packData, local)
+		if err != nil {
+			fmt.Printf(util.FormatError("failed to compare with upstream: %s\n"), err)
+			return
+		}
+
+		if plan.isEmpty() {
+			fmt.Println("nothing upstream changed needs to be applied")
+		} else {
+			fmt.Printf("changes from upstream:\n")
+		}
+		printUpstreamPlan(plan)
+
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes && !plan.isEmpty() {
+			confirm := false
+			huh.NewConfirm().
+				Title("apply these changes?").
+				Affirmative("yes, sync").
+				Negative("cancel").
+				Value(&confirm).
+				Run()
+			if !confirm {
+				fmt.Println("sync cancelled.")
+				return
+			}
+		}
+
+		// the sync gets a commit of its own
+		if err := project.CommitChanges(packData.Root, "Save changes before upstream sync"); err != nil {
+			fmt.Printf(util.FormatError("failed to commit local changes: %s\n"), err)
+			return
+		}
+
+		// old files are removed from linked instances on the next link update
+		linkState, err := LoadLinkState(cwd)
+		if err != nil {
+			fmt.Printf(util.FormatWarning("warning: failed to load link state: %s\n"), err)
+			linkState = &LinkState{
+				RemovedFiles:   []string{},
+				OverridesFiles: make(map[string]string),
+				Version:        "1.0",
+			}
+		}
+
+		batch, err := packData.Begin(cmd.Context())
+		if err != nil {
+			fmt.Printf(util.FormatError("error starting changes: %s\n"), err)
+			return
+		}
+		// an unfinished sync puts back the content and override files it changed, nothing else
+		backup := newFileBackup()
+		synced := false
+		defer func() {
+			if synced {
+				return
+			}
+			if err := errors.Join(batch.Rollback(), backup.restore()); err != nil {
+				fmt.Printf(util.FormatWarning("warning: failed to undo the sync: %s\n"), err)
+			}
+		}()
+
+		manager.Project = packData.Root
+		applied, err := applyUpstreamPlan(cmd.Context(), manager, packData, plan, next, linkState, backup)
+		if interrupted(cmd, "nothing was synced") {
+			return
+		}
+		if err != nil {
+			fmt.Printf(util.FormatError("failed to sync: %s\n"), err)
+			return
+		}
+
+		message := fmt.Sprintf("Sync upstream: %s %s -> %s", upstreamName(current), upstreamVersionName(current), upstreamVersionName(target))
+		packData.Upstream = &target
+		if err := packData.Save(message); err != nil {
+			if interrupted(cmd, "nothing was synced") {
+				return
+			}
+			fmt.Printf(util.FormatError("failed to save the project: %s\n"), err)
+			return
+		}
+
+		if err := batch.Commit(message); err != nil {
+			if interrupted(cmd, "nothing was synced") {
+				return
+			}
+			fmt.Printf(util.FormatWarning("warning: failed to commit changes: %s\n"), err)
+		}
+		synced = true
+
+		if err := SaveLinkState(cwd, linkState); err != nil {
+			fmt.Printf(util.FormatWarning("warning: failed to save link state: %s\n"), err)
+		}
+
+		fmt.Printf(util.FormatSuccess("synced to %s %s, %d changes applied\n"), upstreamName(target), upstreamVersionName(target), applied)
+		if len(plan.conflicts) > 0 {
+			fmt.Printf(util.FormatWarning("%d conflicts kept their local changes, see above\n"), len(plan.conflicts))
+		}
+	},
+}
+
+func init() {
+	upstreamCmd.AddCommand(upstreamSyncCmd)
+
+	upstreamSyncCmd.Flags().BoolP("yes", "y", false, "apply changes without asking for confirmation")
+}
@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"minepack/core/project"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func mod(slug, versionId string) project.ContentData {
+	return project.ContentData{Name: slug, Slug: slug, Id: slug, VersionId: versionId, Source: project.Modrinth}
+}
+
+func requiredBy(content project.ContentData, slugs ...string) project.ContentData {
+	for _, slug := range slugs {
+		content.RequiredBy = append(content.RequiredBy, project.RequiredBy{Slug: slug})
+	}
+	return content
+}
+
+func pinned(content project.ContentData) project.ContentData {
+	content.Pinned = true
+	return content
+}
+
+func fileSHA1(data string) string {
+	sum := sha1.Sum([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// snapshot stands in for an upstream version, files are their contents by path
+func snapshot(game string, files map[string]string, contents ...project.ContentData) *upstreamSnapshot {
+	s := &upstreamSnapshot{
+		game:    game,
+		content: make(map[string]project.ContentData),
+		files:   make(map[string]upstreamFile),
+	}
+	for _, content := range contents {
+		s.content[contentKey(content)] = content
+	}
+	for filePath, data := range files {
+		s.files[filePath] = upstreamFile{sha1: fileSHA1(data)}
+	}
+	return s
+}
+
+func slugs(contents []project.ContentData) []string {
+	var result []string
+	for _, content := range contents {
+		result = append(result, content.Slug)
+	}
+	return result
+}
+
+func TestPlanUpstreamSync(t *testing.T) {
+	const config = "overrides/config/mod.json"
+
+	tests := []struct {
+		name           string
+		base, target   *upstreamSnapshot
+		committed      []project.ContentData
+		local          []project.ContentData
+		committedFiles map[string]string // file contents by path, as the base commit recorded them
+		localFiles     map[string]string // file contents by path, as they are in the project now
+		localGame      string            // the project's game version, the base one when empty
+
+		add, update, remove []string // slugs
+		write, delete       []string // paths
+		game                string
+		conflicts           int
+	}{
+		{
+			name:      "update upstream applies",
+			base:      snapshot("1.21.1", nil, mod("sodium", "1")),
+			target:    snapshot("1.21.1", nil, mod("sodium", "2")),
+			committed: []project.ContentData{mod("sodium", "1")},
+			local:     []project.ContentData{mod("sodium", "1")},
+			update:    []string{"sodium"},
+		},
+		{
+			name:      "update of content removed here conflicts",
+			base:      snapshot("1.21.1", nil, mod("sodium", "1")),
+			target:    snapshot("1.21.1", nil, mod("sodium", "2")),
+			committed: []project.ContentData{mod("sodium", "1")},
+			conflicts: 1,
+		},
+		{
+			name:      "update of pinned content conflicts",
+			base:      snapshot("1.21.1", nil, mod("sodium", "1")),
+			target:    snapshot("1.21.1", nil, mod("sodium", "2")),
+			committed: []project.ContentData{mod("sodium", "1")},
+			local:     []project.ContentData{pinned(mod("sodium", "1"))},
+			conflicts: 1,
+		},
+		{
+			name:      "update of content changed here conflicts",
+			base:      snapshot("1.21.1", nil, mod("sodium", "1")),
+			target:    snapshot("1.21.1", nil, mod("sodium", "2")),
+			committed: []project.ContentData{mod("sodium", "1")},
+			local:     []project.ContentData{mod("sodium", "3")},
+			conflicts: 1,
+		},
+		{
+			name:      "update to the local version does nothing",
+			base:      snapshot("1.21.1", nil, mod("sodium", "1")),
+			target:    snapshot("1.21.1", nil, mod("sodium", "2")),
+			committed: []project.ContentData{mod("sodium", "1")},
+			local:     []project.ContentData{mod("sodium", "2")},
+		},
+		{
+			name:      "removal upstream applies",
+			base:      snapshot("1.21.1", nil, mod("sodium", "1"), mod("lithium", "1")),
+			target:    snapshot("1.21.1", nil, mod("lithium", "1")),
+			committed: []project.ContentData{mod("sodium", "1"), mod("lithium", "1")},
+			local:     []project.ContentData{mod("sodium", "1"), mod("lithium", "1")},
+			remove:    []string{"sodium"},
+		},
+		{
+			name:      "removal of content added here conflicts",
+			base:      snapshot("1.21.1", nil, mod("sodium", "1")),
+			target:    snapshot("1.21.1", nil),
+			local:     []project.ContentData{mod("sodium", "1")},
+			conflicts: 1,
+		},
+		{
+			name:      "removal of content still required here conflicts",
+			base:      snapshot("1.21.1", nil, mod("fabric-api", "1")),
+			target:    snapshot("1.21.1", nil),
+			committed: []project.ContentData{mod("fabric-api", "1")},
+			local:     []project.ContentData{requiredBy(mod("fabric-api", "1"), "modmenu"), mod("modmenu", "1")},
+			conflicts: 1,
+		},
+		{
+			name:      "removal of content required by other removed content applies",
+			base:      snapshot("1.21.1", nil, mod("fabric-api", "1"), mod("modmenu", "1")),
+			target:    snapshot("1.21.1", nil),
+			committed: []project.ContentData{mod("fabric-api", "1"), mod("modmenu", "1")},
+			local:     []project.ContentData{requiredBy(mod("fabric-api", "1"), "modmenu"), mod("modmenu", "1")},
+			remove:    []string{"fabric-api", "modmenu"},
+		},
+		{
+			name:   "addition upstream applies",
+			base:   snapshot("1.21.1", nil),
+			target: snapshot("1.21.1", nil, mod("sodium", "1")),
+			add:    []string{"sodium"},
+		},
+		{
+			name:      "addition of content removed here conflicts",
+			base:      snapshot("1.21.1", nil),
+			target:    snapshot("1.21.1", nil, mod("sodium", "1")),
+			committed: []project.ContentData{mod("sodium", "1")},
+			conflicts: 1,
+		},
+		{
+			name:      "addition of content already here at another version conflicts",
+			base:      snapshot("1.21.1", nil),
+			target:    snapshot("1.21.1", nil, mod("sodium", "2")),
+			local:     []project.ContentData{mod("sodium", "1")},
+			conflicts: 1,
+		},
+		{
+			name:   "addition with a slug taken here conflicts",
+			base:   snapshot("1.21.1", nil),
+			target: snapshot("1.21.1", nil, mod("sodium", "1")),
+			local: []project.ContentData{{
+				Name: "sodium", Slug: "sodium", Id: "394468", VersionId: "1", Source: project.Curseforge,
+			}},
+			conflicts: 1,
+		},
+		{
+			name:           "file changed upstream is written",
+			base:           snapshot("1.21.1", map[string]string{config: "a"}),
+			target:         snapshot("1.21.1", map[string]string{config: "b"}),
+			committedFiles: map[string]string{config: "a"},
+			localFiles:     map[string]string{config: "a"},
+			write:          []string{config},
+		},
+		{
+			name:           "file removed upstream is deleted",
+			base:           snapshot("1.21.1", map[string]string{config: "a"}),
+			target:         snapshot("1.21.1", nil),
+			committedFiles: map[string]string{config: "a"},
+			localFiles:     map[string]string{config: "a"},
+			delete:         []string{config},
+		},
+		{
+			name:           "file changed on both sides conflicts",
+			base:           snapshot("1.21.1", map[string]string{config: "a"}),
+			target:         snapshot("1.21.1", map[string]string{config: "b"}),
+			committedFiles: map[string]string{config: "a"},
+			localFiles:     map[string]string{config: "c"},
+			conflicts:      1,
+		},
+		{
+			name:           "file changed the same way on both sides does nothing",
+			base:           snapshot("1.21.1", map[string]string{config: "a"}),
+			target:         snapshot("1.21.1", map[string]string{config: "b"}),
+			committedFiles: map[string]string{config: "a"},
+			localFiles:     map[string]string{config: "b"},
+		},
+		{
+			name:   "game version changed upstream applies",
+			base:   snapshot("1.21.1", nil),
+			target: snapshot("1.21.4", nil),
+			game:   "1.21.4",
+		},
+		{
+			name:      "game version changed on both sides conflicts",
+			base:      snapshot("1.21.1", nil),
+			target:    snapshot("1.21.4", nil),
+			localGame: "1.21.3",
+			conflicts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for filePath, data := range tt.localFiles {
+				dest := filepath.Join(root, filepath.FromSlash(filePath))
+				if err := os.MkdirAll(filepath.Dir(dest), os.ModePerm); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(dest, []byte(data), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			committed := &project.CommittedState{
+				Project: project.Project{Versions: project.ProjectVersions{Game: tt.base.game}},
+				Content: make(map[string]project.ContentData),
+				Files:   make(map[string]plumbing.Hash),
+			}
+			for _, content := range tt.committed {
+				committed.Content[content.Slug] = content
+			}
+			for filePath, data := range tt.committedFiles {
+				committed.Files[filePath] = plumbing.ComputeHash(plumbing.BlobObject, []byte(data))
+			}
+
+			game := tt.localGame
+			if game == "" {
+				game = tt.base.game
+			}
+			packData := &project.Project{Root: root, Versions: project.ProjectVersions{Game: game}}
+
+			plan, err := planUpstreamSync(tt.base, tt.target, committed, packData, tt.local)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var updated []string
+			for _, u := range plan.update {
+				updated = append(updated, u.current.Slug)
+			}
+			check := func(what string, got, want []string) {
+				t.Helper()
+				slices.Sort(got)
+				if !slices.Equal(got, want) {
+					t.Errorf("%s: got %v, want %v", what, got, want)
+				}
+			}
+			check("add", slugs(plan.add), tt.add)
+			check("update", updated, tt.update)
+			check("remove", slugs(plan.remove), tt.remove)
+			check("write", plan.write, tt.write)
+			check("delete", plan.delete, tt.delete)
+			if plan.game != tt.game {
+				t.Errorf("game: got %q, want %q", plan.game, tt.game)
+			}
+			if len(plan.conflicts) != tt.conflicts {
+				t.Errorf("conflicts: got %v, want %d", plan.conflicts, tt.conflicts)
+			}
+		})
+	}
+}
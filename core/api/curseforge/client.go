@@ -1,18 +1,26 @@
 package curseforge
 
 import (
+	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
+	"minepack/core"
+	"minepack/core/config"
+	"minepack/core/project"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 )
 
 // curseforge API client with API key support
 var CurseForgeClient *Client
 
-const BaseURL = "https://api.curseforge.com/v1"
+// BaseURL is the root of the curseforge API, it can be changed with the source's settings
+var BaseURL = "https://api.curseforge.com/v1"
 
 type Client struct {
 	httpClient *http.Client
@@ -28,20 +36,65 @@ func base64decode(input string) (string, error) {
 	return string(decoded), nil
 }
 
-var apiKey, _ = base64decode("JDJhJDEwJGJMNGJJTDVwVVdxZmNPN0tRdG5NUmVha3d0ZkhiTktoNnYxdVRwS2x6aHdvdWVFSlFuUG5t") // please avoid using this api key if you are forking this, curseforge rate limits are stupidly low
+// builtinAPIKey is only used when no key is configured, see "minepack auth login curseforge"
+var builtinAPIKey, _ = base64decode("JDJhJDEwJGJMNGJJTDVwVVdxZmNPN0tRdG5NUmVha3d0ZkhiTktoNnYxdVRwS2x6aHdvdWVFSlFuUG5t") // please avoid using this api key if you are forking this, curseforge rate limits are stupidly low
+
+// requestInterval spaces out curseforge requests, the API has no rate limit headers to follow and
+// blocks keys that send too much at once
+const requestInterval = 100 * time.Millisecond
 
 func init() {
+	httpClient := core.NewSourceClient("curseforge", 0)
+	core.Throttle(httpClient, requestInterval)
 	CurseForgeClient = &Client{
-		httpClient: &http.Client{},
-		userAgent:  "Minepack/1.0 (+https://github.com/ayeuhugyu/minepack)",
-		apiKey:     apiKey,
+		httpClient: httpClient,
+		userAgent:  core.UserAgent(),
+		apiKey:     builtinAPIKey,
+	}
+}
+
+// Configure points the curseforge client at a different API, e.g. a proxy, applies HTTP settings
+// and switches to the user's own API key when one is configured
+func (Provider) Configure(settings project.SourceSettings) error {
+	if key, _ := config.Token("curseforge"); key != "" {
+		CurseForgeClient.apiKey = key
 	}
+	if settings.BaseURL != "" {
+		if _, err := url.Parse(settings.BaseURL); err != nil {
+			return fmt.Errorf("invalid base url %s: %w", settings.BaseURL, err)
+		}
+		BaseURL = strings.TrimSuffix(settings.BaseURL, "/")
+	}
+	if settings.UserAgent != "" {
+		CurseForgeClient.userAgent = settings.UserAgent
+	}
+	core.ApplySettings(CurseForgeClient.httpClient, settings)
+	return nil
 }
 
 // makes an authenticated request to the curseforge API
-func (c *Client) makeRequest(endpoint string, target interface{}) error {
+func (c *Client) makeRequest(ctx context.Context, endpoint string, target interface{}) error {
+	return c.do(ctx, "GET", endpoint, nil, target)
+}
+
+// makes an authenticated POST request with a JSON body, used by the bulk endpoints
+func (c *Client) makePostRequest(ctx context.Context, endpoint string, body interface{}, target interface{}) error {
+	return c.do(ctx, "POST", endpoint, body, target)
+}
+
+func (c *Client) do(ctx context.Context, method string, endpoint string, body interface{}, target interface{}) error {
 	url := BaseURL + endpoint
-	req, err := http.NewRequest("GET", url, nil)
+
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -49,6 +102,9 @@ func (c *Client) makeRequest(endpoint string, target interface{}) error {
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("x-api-key", c.apiKey)
 	req.Header.Set("User-Agent", c.userAgent)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -1,6 +1,7 @@
 package curseforge
 
 import (
+	"context"
 	"fmt"
 	"minepack/core/project"
 )
@@ -52,7 +53,7 @@ func ConvertToContentData(mod *Mod) project.ContentData {
 }
 
 // converts a full Mod with specific file to ContentData
-func ConvertModToContentData(mod *Mod, file *File) project.ContentData {
+func ConvertModToContentData(ctx context.Context, mod *Mod, file *File) project.ContentData {
 	contentData := project.ContentData{
 		ContentType: getContentType(mod.ClassID),
 		Name:        mod.Name,
@@ -92,6 +93,11 @@ func ConvertModToContentData(mod *Mod, file *File) project.ContentData {
 			contentData.File.Hashes = hashes
 		}
 
+		// look up every dependency in one request instead of one per dependency
+		if err := PrefetchDependencies(ctx, []*File{file}); err != nil {
+			fmt.Printf("error fetching dependency data for %s: %v\n", mod.Name, err)
+		}
+
 		// convert dependencies
 		for _, dep := range file.Dependencies {
 			var depType project.DependencyType
@@ -112,17 +118,18 @@ func ConvertModToContentData(mod *Mod, file *File) project.ContentData {
 				depType = project.Optional
 			}
 
-			modData, err := GetProject(fmt.Sprintf("%d", dep.ModID))
-			if err != nil {
+			dependency := project.Dependency{
+				Id:             fmt.Sprintf("%d", dep.ModID),
+				DependencyType: depType,
+			}
+			if modData, err := GetProject(ctx, dependency.Id); err == nil {
+				dependency.Name = modData.Name
+				dependency.Slug = modData.Slug
+			} else {
 				fmt.Printf("error fetching dependency data for mod ID %d: %v\n", dep.ModID, err)
 			}
 
-			contentData.Dependencies = append(contentData.Dependencies, project.Dependency{
-				Id:             fmt.Sprintf("%d", dep.ModID),
-				DependencyType: depType,
-				Name:           modData.Name,
-				Slug:           modData.Slug,
-			})
+			contentData.Dependencies = append(contentData.Dependencies, dependency)
 		}
 	}
 
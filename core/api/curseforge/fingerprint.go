@@ -0,0 +1,86 @@
+package curseforge
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+)
+
+// Fingerprint computes curseforge's fingerprint of a file: murmur2 with seed 1 over its bytes,
+// leaving out tabs, newlines, carriage returns and spaces
+func Fingerprint(data []byte) uint32 {
+	normalized := make([]byte, 0, len(data))
+	for _, b := range data {
+		if b != '\t' && b != '\n' && b != '\r' && b != ' ' {
+			normalized = append(normalized, b)
+		}
+	}
+	return murmur2(normalized, 1)
+}
+
+func murmur2(data []byte, seed uint32) uint32 {
+	const m = 0x5bd1e995
+	const r = 24
+
+	h := seed ^ uint32(len(data))
+	for len(data) >= 4 {
+		k := binary.LittleEndian.Uint32(data)
+		k *= m
+		k ^= k >> r
+		k *= m
+		h *= m
+		h ^= k
+		data = data[4:]
+	}
+
+	switch len(data) {
+	case 3:
+		h ^= uint32(data[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(data[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(data[0])
+		h *= m
+	}
+
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return h
+}
+
+// identifies files by fingerprint using POST /fingerprints, keyed by fingerprint. only exact
+// matches are returned
+func GetFingerprintMatches(ctx context.Context, fingerprints []uint32) (map[uint32]*File, error) {
+	result := make(map[uint32]*File)
+	if len(fingerprints) == 0 {
+		return result, nil
+	}
+
+	request := struct {
+		Fingerprints []uint32 `json:"fingerprints"`
+	}{Fingerprints: fingerprints}
+
+	var response struct {
+		Data struct {
+			ExactMatches []struct {
+				ID   int  `json:"id"`
+				File File `json:"file"`
+			} `json:"exactMatches"`
+		} `json:"data"`
+	}
+
+	endpoint := fmt.Sprintf("/fingerprints/%d", GameMinecraft)
+	if err := CurseForgeClient.makePostRequest(ctx, endpoint, request, &response); err != nil {
+		return nil, fmt.Errorf("failed to match %d curseforge fingerprints: %w", len(fingerprints), err)
+	}
+
+	for i := range response.Data.ExactMatches {
+		file := &response.Data.ExactMatches[i].File
+		result[uint32(file.FileFingerprint)] = file
+	}
+
+	return result, nil
+}
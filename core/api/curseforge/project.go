@@ -1,27 +1,268 @@
 package curseforge
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"minepack/core"
 	"minepack/core/project"
+	"strconv"
+	"sync"
 )
 
+// bulkChunkSize keeps bulk requests within the number of ids the API accepts at once
+const bulkChunkSize = 100
+
+// mods already fetched during this run, keyed by id
+var knownMods sync.Map
+
+// newest compatible files found by GetLatestFiles, keyed by latestFileKey
+var knownLatestFiles sync.Map
+
+func latestFileKey(modID int, packData project.Project) string {
+	return fmt.Sprintf("%d/%s/%s", modID, packData.Versions.Game, packData.Versions.Loader.Name)
+}
+
 // fetches detailed project information from CurseForge
-func GetProject(projectID string) (*Mod, error) {
+func GetProject(ctx context.Context, projectID string) (*Mod, error) {
+	if id, err := strconv.Atoi(projectID); err == nil {
+		if known, ok := knownMods.Load(id); ok {
+			return known.(*Mod), nil
+		}
+	}
+
 	endpoint := "/mods/" + projectID
 
 	var response struct {
 		Data Mod `json:"data"`
 	}
 
-	if err := CurseForgeClient.makeRequest(endpoint, &response); err != nil {
+	if err := CurseForgeClient.makeRequest(ctx, endpoint, &response); err != nil {
 		return nil, fmt.Errorf("failed to get curseforge project %s: %w", projectID, err)
 	}
 
+	knownMods.Store(response.Data.ID, &response.Data)
 	return &response.Data, nil
 }
 
+// fetches many mods using POST /mods, up to bulkChunkSize per request, keyed by mod id
+func GetMods(ctx context.Context, modIDs []int) (map[int]*Mod, error) {
+	result := make(map[int]*Mod)
+
+	var missing []int
+	requested := make(map[int]bool)
+	for _, id := range modIDs {
+		if _, done := result[id]; done || requested[id] {
+			continue
+		}
+		requested[id] = true
+		if known, ok := knownMods.Load(id); ok {
+			result[id] = known.(*Mod)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	for start := 0; start < len(missing); start += bulkChunkSize {
+		chunk := missing[start:min(start+bulkChunkSize, len(missing))]
+
+		request := struct {
+			ModIDs       []int `json:"modIds"`
+			FilterPcOnly bool  `json:"filterPcOnly"`
+		}{ModIDs: chunk, FilterPcOnly: true}
+
+		var response struct {
+			Data []Mod `json:"data"`
+		}
+
+		if err := CurseForgeClient.makePostRequest(ctx, "/mods", request, &response); err != nil {
+			if !errors.Is(err, core.ErrOffline) {
+				return nil, fmt.Errorf("failed to get %d curseforge projects: %w", len(chunk), err)
+			}
+			// this exact lookup was never stored, but the projects may have been fetched one by one
+			for _, id := range chunk {
+				mod, err := GetProject(ctx, strconv.Itoa(id))
+				if err != nil {
+					return nil, err
+				}
+				result[id] = mod
+			}
+			continue
+		}
+
+		for i := range response.Data {
+			mod := &response.Data[i]
+			knownMods.Store(mod.ID, mod)
+			result[mod.ID] = mod
+		}
+	}
+
+	return result, nil
+}
+
+// fetches many files using POST /mods/files, up to bulkChunkSize per request, keyed by file id
+func GetFiles(ctx context.Context, fileIDs []int) (map[int]*File, error) {
+	result := make(map[int]*File)
+	if len(fileIDs) == 0 {
+		return result, nil
+	}
+
+	seen := make(map[int]bool)
+	unique := make([]int, 0, len(fileIDs))
+	for _, id := range fileIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	for start := 0; start < len(unique); start += bulkChunkSize {
+		chunk := unique[start:min(start+bulkChunkSize, len(unique))]
+
+		request := struct {
+			FileIDs []int `json:"fileIds"`
+		}{FileIDs: chunk}
+
+		var response struct {
+			Data []File `json:"data"`
+		}
+
+		if err := CurseForgeClient.makePostRequest(ctx, "/mods/files", request, &response); err != nil {
+			return nil, fmt.Errorf("failed to get %d curseforge files: %w", len(chunk), err)
+		}
+
+		for i := range response.Data {
+			result[response.Data[i].ID] = &response.Data[i]
+		}
+	}
+
+	return result, nil
+}
+
+// fetches a modpack by id or slug with one of its files, its main file when fileID is 0
+func GetModpackFile(ctx context.Context, id string, fileID int) (*Mod, *File, error) {
+	mod, err := getProjectByIdOrSlug(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if mod.ClassID != ClassModpacks {
+		return nil, nil, fmt.Errorf("%s is not a modpack", mod.Name)
+	}
+	if fileID == 0 {
+		fileID = mod.MainFileID
+	}
+
+	files, err := GetFiles(ctx, []int{fileID})
+	if err != nil {
+		return nil, nil, err
+	}
+	file, ok := files[fileID]
+	if !ok || file.ModID != mod.ID {
+		return nil, nil, fmt.Errorf("%s has no file %d", mod.Name, fileID)
+	}
+	return mod, file, nil
+}
+
+// fetches the mods every dependency of the given files points at in one request, so
+// converting the files afterwards doesn't need any more lookups
+func PrefetchDependencies(ctx context.Context, files []*File) error {
+	var modIDs []int
+	for _, file := range files {
+		if file == nil {
+			continue
+		}
+		for _, dep := range file.Dependencies {
+			modIDs = append(modIDs, dep.ModID)
+		}
+	}
+	_, err := GetMods(ctx, modIDs)
+	return err
+}
+
+// latestFileIndex picks the newest file id a mod lists for the pack's game version and loader
+func latestFileIndex(mod *Mod, packData project.Project) (int, bool) {
+	loaderID := getModLoaderID(packData.Versions.Loader.Name)
+	for _, index := range mod.LatestFilesIndexes {
+		if packData.Versions.Game != "" && index.GameVersion != packData.Versions.Game {
+			continue
+		}
+		if loaderID != ModLoaderAny && index.ModLoader != loaderID {
+			continue
+		}
+		// indexes are sorted newest first
+		return index.FileID, true
+	}
+	return 0, false
+}
+
+// fetches the newest compatible file for many mods using the bulk endpoints, falling back to
+// listing files for mods whose index doesn't mention the pack's versions. keyed by mod id
+func GetLatestFiles(ctx context.Context, modIDs []int, packData project.Project) (map[int]*File, error) {
+	mods, err := GetMods(ctx, modIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var fileIDs []int
+	var unindexed []int
+	for _, id := range modIDs {
+		mod, ok := mods[id]
+		if !ok {
+			continue
+		}
+		if fileID, ok := latestFileIndex(mod, packData); ok {
+			fileIDs = append(fileIDs, fileID)
+		} else {
+			unindexed = append(unindexed, id)
+		}
+	}
+
+	files, err := GetFiles(ctx, fileIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int]*File)
+	for _, file := range files {
+		result[file.ModID] = file
+	}
+	for _, id := range unindexed {
+		list, err := GetProjectFiles(ctx, strconv.Itoa(id), packData)
+		if err != nil || len(list) == 0 {
+			continue
+		}
+		result[id] = &list[0]
+	}
+
+	for id, file := range result {
+		knownLatestFiles.Store(latestFileKey(id, packData), file)
+	}
+	return result, nil
+}
+
+// fetches the newest compatible file for a single mod, reusing anything GetLatestFiles found
+func GetLatestFile(ctx context.Context, projectID string, packData project.Project) (*File, error) {
+	if id, err := strconv.Atoi(projectID); err == nil {
+		if known, ok := knownLatestFiles.Load(latestFileKey(id, packData)); ok {
+			return known.(*File), nil
+		}
+	}
+
+	files, err := GetProjectVersions(ctx, projectID, packData)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no compatible versions found for curseforge project %s", projectID)
+	}
+	return &files[0], nil
+}
+
 // fetches all files for a project from CurseForge
-func GetProjectFiles(projectID string, packData project.Project) ([]File, error) {
+func GetProjectFiles(ctx context.Context, projectID string, packData project.Project) ([]File, error) {
 	endpoint := "/mods/" + projectID + "/files"
 	params := ""
 	if packData.Versions.Game != "" {
@@ -40,7 +281,7 @@ func GetProjectFiles(projectID string, packData project.Project) ([]File, error)
 		Data []File `json:"data"`
 	}
 
-	if err := CurseForgeClient.makeRequest(endpoint+params, &response); err != nil {
+	if err := CurseForgeClient.makeRequest(ctx, endpoint+params, &response); err != nil {
 		return nil, fmt.Errorf("failed to get files for curseforge project %s: %w", projectID, err)
 	}
 
@@ -48,9 +289,9 @@ func GetProjectFiles(projectID string, packData project.Project) ([]File, error)
 }
 
 // fetches filtered versions for a project (matching modrinth signature)
-func GetProjectVersions(projectID string, packData project.Project) ([]File, error) {
+func GetProjectVersions(ctx context.Context, projectID string, packData project.Project) ([]File, error) {
 	// get all files first
-	files, err := GetProjectFiles(projectID, packData)
+	files, err := GetProjectFiles(ctx, projectID, packData)
 	if err != nil {
 		return nil, err
 	}
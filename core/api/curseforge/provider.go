@@ -0,0 +1,317 @@
+package curseforge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"minepack/core"
+	"minepack/core/api"
+	"minepack/core/download"
+	"minepack/core/project"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Provider exposes CurseForge through the core/api provider interface
+type Provider struct{}
+
+func init() {
+	api.Register(Provider{})
+}
+
+func (Provider) Name() string {
+	return "curseforge"
+}
+
+func (Provider) Source() project.Source {
+	return project.Curseforge
+}
+
+// ParseURL extracts the slug from links like https://www.curseforge.com/minecraft/mc-mods/<slug>
+func (Provider) ParseURL(link string) (string, bool) {
+	if !strings.Contains(link, "curseforge.com/minecraft/") {
+		return "", false
+	}
+	parts := strings.Split(strings.TrimSuffix(link, "/"), "/")
+	for i, part := range parts {
+		if part == "minecraft" && i+2 < len(parts) {
+			return parts[i+2], true
+		}
+	}
+	// fallback: last part
+	return parts[len(parts)-1], true
+}
+
+func (Provider) Search(ctx context.Context, query string, packData project.Project) ([]api.SearchHit, error) {
+	results, err := SearchProjects(ctx, query, packData, false)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]api.SearchHit, 0, len(results))
+	for _, r := range results {
+		knownMods.Store(r.ID, &r)
+		hits = append(hits, api.SearchHit{
+			Id:      strconv.Itoa(r.ID),
+			Slug:    r.Slug,
+			Name:    r.Name,
+			Summary: r.Summary,
+		})
+	}
+	return hits, nil
+}
+
+// getProjectByIdOrSlug looks a mod up by numeric id, or by searching for an exact slug
+func getProjectByIdOrSlug(ctx context.Context, id string) (*Mod, error) {
+	if _, err := strconv.Atoi(id); err == nil {
+		return GetProject(ctx, id)
+	}
+
+	params := url.Values{}
+	params.Set("gameId", strconv.Itoa(GameMinecraft))
+	params.Set("slug", id)
+
+	var response SearchResponse
+	if err := CurseForgeClient.makeRequest(ctx, "/mods/search?"+params.Encode(), &response); err != nil {
+		return nil, fmt.Errorf("failed to get curseforge project %s: %w", id, err)
+	}
+	for i := range response.Data {
+		if response.Data[i].Slug == id {
+			knownMods.Store(response.Data[i].ID, &response.Data[i])
+			return &response.Data[i], nil
+		}
+	}
+	return nil, fmt.Errorf("curseforge project %s not found", id)
+}
+
+func (Provider) GetProject(ctx context.Context, id string, packData project.Project) (*project.ContentData, error) {
+	mod, err := getProjectByIdOrSlug(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	file, err := GetLatestFile(ctx, strconv.Itoa(mod.ID), packData)
+	if err != nil {
+		return nil, err
+	}
+	content := ConvertModToContentData(ctx, mod, file)
+	return &content, nil
+}
+
+func (Provider) ListVersions(ctx context.Context, id string, packData project.Project) ([]project.ContentData, error) {
+	mod, err := getProjectByIdOrSlug(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	files, err := GetProjectVersions(ctx, strconv.Itoa(mod.ID), packData)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*File, len(files))
+	for i := range files {
+		list[i] = &files[i]
+	}
+	if err := PrefetchDependencies(ctx, list); err != nil {
+		return nil, err
+	}
+
+	contents := make([]project.ContentData, 0, len(files))
+	for _, file := range list {
+		contents = append(contents, ConvertModToContentData(ctx, mod, file))
+	}
+	return contents, nil
+}
+
+func (Provider) ResolveDependencies(ctx context.Context, deps []project.Dependency, packData project.Project) (map[string]*project.ContentData, error) {
+	var ids []int
+	for _, dep := range deps {
+		if id, err := strconv.Atoi(dep.Id); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	files, err := GetLatestFiles(ctx, ids, packData)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*File, 0, len(files))
+	for _, file := range files {
+		list = append(list, file)
+	}
+	if err := PrefetchDependencies(ctx, list); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*project.ContentData)
+	for id, file := range files {
+		mod, err := GetProject(ctx, strconv.Itoa(id))
+		if err != nil {
+			continue
+		}
+		content := ConvertModToContentData(ctx, mod, file)
+		result[content.Id] = &content
+	}
+	return result, nil
+}
+
+// LatestVersions finds the newest compatible file of many mods with the bulk endpoints
+func (Provider) LatestVersions(ctx context.Context, contents []project.ContentData, packData project.Project) (map[string]*project.ContentData, error) {
+	var ids []int
+	for _, content := range contents {
+		if id, err := strconv.Atoi(content.Id); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	files, err := GetLatestFiles(ctx, ids, packData)
+	if err != nil {
+		return nil, err
+	}
+	mods, err := GetMods(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*File, 0, len(files))
+	for _, file := range files {
+		list = append(list, file)
+	}
+	if err := PrefetchDependencies(ctx, list); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*project.ContentData, len(files))
+	for id, file := range files {
+		mod, ok := mods[id]
+		if !ok {
+			continue
+		}
+		content := ConvertModToContentData(ctx, mod, file)
+		result[content.Id] = &content
+	}
+	return result, nil
+}
+
+// DownloadJob falls back to the CDN path for files whose authors disabled third party
+// downloads, which the API reports with an empty download URL
+func (Provider) DownloadJob(content project.ContentData, dest string) download.Job {
+	job := download.JobFromContent(content, dest)
+	if job.URL == "" {
+		if fileID, err := strconv.Atoi(content.VersionId); err == nil && content.File.Filename != "" {
+			job.URL = fmt.Sprintf("https://edge.forgecdn.net/files/%d/%d/%s", fileID/1000, fileID%1000, url.PathEscape(content.File.Filename))
+		}
+	}
+	return job
+}
+
+// LookupHashes identifies files by their fingerprint, the only hash curseforge can search by.
+// hashes are fingerprints in decimal, see Fingerprint
+func (Provider) LookupHashes(ctx context.Context, hashes []string, format project.HashFormat) (map[string]project.ContentData, error) {
+	if format != project.Murmur2 {
+		return nil, fmt.Errorf("curseforge can't look files up by %s: %w", project.HashFormatToString(format), api.ErrUnsupported)
+	}
+
+	var fingerprints []uint32
+	for _, hash := range hashes {
+		if fingerprint, err := strconv.ParseUint(hash, 10, 32); err == nil {
+			fingerprints = append(fingerprints, uint32(fingerprint))
+		}
+	}
+	files, err := GetFingerprintMatches(ctx, fingerprints)
+	if err != nil {
+		return nil, err
+	}
+
+	var modIDs []int
+	list := make([]*File, 0, len(files))
+	for _, file := range files {
+		modIDs = append(modIDs, file.ModID)
+		list = append(list, file)
+	}
+	mods, err := GetMods(ctx, modIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := PrefetchDependencies(ctx, list); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]project.ContentData)
+	for fingerprint, file := range files {
+		mod, ok := mods[file.ModID]
+		if !ok {
+			continue
+		}
+		result[strconv.FormatUint(uint64(fingerprint), 10)] = ConvertModToContentData(ctx, mod, file)
+	}
+	return result, nil
+}
+
+// Redistribution allows mods unless their author turned off distribution outside of curseforge,
+// which the API reports as allowModDistribution false
+func (Provider) Redistribution(ctx context.Context, ids []string) (map[string]api.Redistribution, error) {
+	modIDs := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if modID, err := strconv.Atoi(id); err == nil {
+			modIDs = append(modIDs, modID)
+		}
+	}
+	mods, err := GetMods(ctx, modIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]api.Redistribution, len(mods))
+	for id, mod := range mods {
+		if mod.AllowModDistribution != nil && !*mod.AllowModDistribution {
+			result[strconv.Itoa(id)] = api.Redistribution{Allowed: false, Reason: "distribution disabled by the author"}
+		} else {
+			result[strconv.Itoa(id)] = api.Redistribution{Allowed: true, Reason: "distribution allowed by the author"}
+		}
+	}
+	return result, nil
+}
+
+// Describe looks up the categories of mods and the display names of their files. curseforge
+// doesn't report licenses
+func (Provider) Describe(ctx context.Context, contents []project.ContentData) (map[string]api.Details, error) {
+	var modIDs, fileIDs []int
+	for _, content := range contents {
+		if modID, err := strconv.Atoi(content.Id); err == nil {
+			modIDs = append(modIDs, modID)
+		}
+		if fileID, err := strconv.Atoi(content.VersionId); err == nil {
+			fileIDs = append(fileIDs, fileID)
+		}
+	}
+	mods, err := GetMods(ctx, modIDs)
+	if err != nil {
+		return nil, err
+	}
+	// file names are only for display, offline they may not have been stored
+	files, err := GetFiles(ctx, fileIDs)
+	if err != nil && !errors.Is(err, core.ErrOffline) {
+		return nil, err
+	}
+
+	result := make(map[string]api.Details, len(contents))
+	for _, content := range contents {
+		var details api.Details
+		if modID, err := strconv.Atoi(content.Id); err == nil {
+			if mod, ok := mods[modID]; ok {
+				for _, category := range mod.Categories {
+					details.Tags = append(details.Tags, category.Slug)
+				}
+			}
+		}
+		if fileID, err := strconv.Atoi(content.VersionId); err == nil {
+			if file, ok := files[fileID]; ok {
+				details.Version = file.DisplayName
+			}
+		}
+		result[content.Id] = details
+	}
+	return result, nil
+}
@@ -1,6 +1,7 @@
 package curseforge
 
 import (
+	"context"
 	"fmt"
 	"minepack/core/project"
 	"net/url"
@@ -9,7 +10,7 @@ import (
 )
 
 // searches curseforge for projects matching the query and optional pack data
-func SearchProjects(query string, projectData project.Project, verbose bool) ([]Mod, error) {
+func SearchProjects(ctx context.Context, query string, projectData project.Project, verbose bool) ([]Mod, error) {
 	if verbose {
 		fmt.Printf("starting CurseForge search for query: %s\n", query)
 		if projectData.Versions.Game != "" || projectData.Versions.Loader.Name != "" {
@@ -31,7 +32,7 @@ func SearchProjects(query string, projectData project.Project, verbose bool) ([]
 	params.Set("sortOrder", "desc")
 
 	// add class filter (project types) - only add mods for now to test
-	if projectData != (project.Project{}) {
+	if !projectData.IsEmpty() {
 		// start with just mods to test
 		params.Add("classId", strconv.Itoa(ClassMods))
 		if verbose {
@@ -67,7 +68,7 @@ func SearchProjects(query string, projectData project.Project, verbose bool) ([]
 	}
 
 	var response SearchResponse
-	if err := CurseForgeClient.makeRequest(endpoint, &response); err != nil {
+	if err := CurseForgeClient.makeRequest(ctx, endpoint, &response); err != nil {
 		return nil, fmt.Errorf("failed to search curseforge: %w", err)
 	}
 
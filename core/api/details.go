@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"minepack/core/project"
+)
+
+// Details is information about content that isn't stored in the project, like its license
+type Details struct {
+	Version string   // human readable version, e.g. "0.5.11" instead of a version id
+	License string   // SPDX id, empty when unknown
+	Tags    []string // categories the project is listed under on its source
+}
+
+// Describer is implemented by providers that can look up Details, keyed by content id
+type Describer interface {
+	Describe(ctx context.Context, contents []project.ContentData) (map[string]Details, error)
+}
+
+// Describe looks up Details for content from each provider, keyed by slug. content whose provider
+// can't describe it only gets its version id as the version. a provider that fails doesn't keep the
+// others from describing their content, the result is complete apart from it and the error names it
+func Describe(ctx context.Context, contents []project.ContentData) (map[string]Details, error) {
+	result := make(map[string]Details, len(contents))
+	var errs []error
+	for source, sourceContents := range groupBySource(contents) {
+		var found map[string]Details
+		if p, ok := ForSource(source); ok {
+			if describer, ok := p.(Describer); ok {
+				var err error
+				found, err = describer.Describe(ctx, sourceContents)
+				if err != nil {
+					if ctx.Err() != nil {
+						return nil, ctx.Err()
+					}
+					errs = append(errs, fmt.Errorf("failed to describe %s content: %w", project.SourceToString(source), err))
+				}
+			}
+		}
+		for _, content := range sourceContents {
+			details := found[content.Id]
+			if details.Version == "" {
+				details.Version = content.VersionId
+			}
+			result[content.Slug] = details
+		}
+	}
+	return result, errors.Join(errs...)
+}
@@ -0,0 +1,92 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"minepack/core"
+	"minepack/core/config"
+	"minepack/core/project"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// github API client, authenticated when a token is configured
+var GitHubClient *Client
+
+// BaseURL is the root of the github REST API, it can be changed with the source's settings, e.g.
+// for github enterprise or a stand-in server
+var BaseURL = "https://api.github.com"
+
+type Client struct {
+	httpClient *http.Client
+	token      string
+	userAgent  string
+}
+
+func init() {
+	GitHubClient = &Client{
+		httpClient: core.NewSourceClient("github", 0),
+		userAgent:  core.UserAgent(),
+	}
+}
+
+// Configure points the github client at a different API, applies HTTP settings and picks up a
+// token, which raises the rate limit and allows private repositories
+func (Provider) Configure(settings project.SourceSettings) error {
+	if token, _ := config.Token("github"); token != "" {
+		GitHubClient.token = token
+	}
+	if settings.BaseURL != "" {
+		if _, err := url.Parse(settings.BaseURL); err != nil {
+			return fmt.Errorf("invalid base url %s: %w", settings.BaseURL, err)
+		}
+		if baseURL := strings.TrimSuffix(settings.BaseURL, "/"); baseURL != BaseURL {
+			// repositories fetched from another server don't describe this one's
+			knownRepositories.Clear()
+			BaseURL = baseURL
+		}
+	}
+	if settings.UserAgent != "" {
+		GitHubClient.userAgent = settings.UserAgent
+	}
+	core.ApplySettings(GitHubClient.httpClient, settings)
+	return nil
+}
+
+// makes a request to the github API
+func (c *Client) makeRequest(ctx context.Context, endpoint string, target interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", BaseURL+endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("User-Agent", c.userAgent)
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API request failed with status %d: %s - Response: %s", resp.StatusCode, resp.Status, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
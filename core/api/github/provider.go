@@ -0,0 +1,242 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"minepack/core/api"
+	"minepack/core/download"
+	"minepack/core/project"
+	"slices"
+	"strings"
+
+	"github.com/unascribed/FlexVer/go/flexver"
+)
+
+// Provider exposes GitHub Releases through the core/api provider interface. projects are
+// identified as "owner/repo", optionally followed by "#<asset pattern>"
+type Provider struct{}
+
+func init() {
+	api.Register(Provider{})
+}
+
+func (Provider) Name() string {
+	return "github"
+}
+
+func (Provider) Source() project.Source {
+	return project.GitHub
+}
+
+// splitID separates an "owner/repo#pattern" identifier into the repository and asset pattern
+func splitID(id string) (string, string, error) {
+	repo, pattern, _ := strings.Cut(id, "#")
+	repo = strings.TrimSuffix(strings.TrimSpace(repo), ".git")
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("invalid github repository %q, expected owner/repo", id)
+	}
+	return repo, pattern, nil
+}
+
+// ParseURL extracts owner/repo from links like https://github.com/<owner>/<repo>/releases,
+// keeping an asset pattern appended after "#"
+func (Provider) ParseURL(url string) (string, bool) {
+	url, pattern, hasPattern := strings.Cut(url, "#")
+	_, rest, found := strings.Cut(url, "github.com/")
+	if !found {
+		return "", false
+	}
+	parts := strings.Split(strings.Trim(rest, "/"), "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", false
+	}
+	id := parts[0] + "/" + strings.TrimSuffix(parts[1], ".git")
+	if hasPattern {
+		id += "#" + pattern
+	}
+	return id, true
+}
+
+func (Provider) Search(ctx context.Context, query string, packData project.Project) ([]api.SearchHit, error) {
+	repos, err := SearchRepositories(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]api.SearchHit, 0, len(repos))
+	for _, repo := range repos {
+		if repo.Archived {
+			continue
+		}
+		hits = append(hits, api.SearchHit{
+			Id:      repo.FullName,
+			Slug:    repo.FullName,
+			Name:    repo.Name,
+			Summary: repo.Description,
+		})
+	}
+	return hits, nil
+}
+
+func (Provider) GetProject(ctx context.Context, id string, packData project.Project) (*project.ContentData, error) {
+	repoName, pattern, err := splitID(id)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := GetRepository(ctx, repoName)
+	if err != nil {
+		return nil, err
+	}
+	release, asset, err := LatestRelease(ctx, repo.FullName, pattern, packData)
+	if err != nil {
+		return nil, err
+	}
+	content := ConvertReleaseToContentData(repo, release, asset, pattern)
+	return &content, nil
+}
+
+func (Provider) ListVersions(ctx context.Context, id string, packData project.Project) ([]project.ContentData, error) {
+	repoName, pattern, err := splitID(id)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := GetRepository(ctx, repoName)
+	if err != nil {
+		return nil, err
+	}
+	releases, err := GetReleases(ctx, repo.FullName)
+	if err != nil {
+		return nil, err
+	}
+
+	var contents []project.ContentData
+	for i, release := range releases {
+		if release.Draft {
+			continue
+		}
+		if asset, ok := PickAsset(release, pattern, packData); ok {
+			contents = append(contents, ConvertReleaseToContentData(repo, &releases[i], asset, pattern))
+		}
+	}
+	return contents, nil
+}
+
+// releases don't declare dependencies, so there is never anything to resolve
+func (Provider) ResolveDependencies(ctx context.Context, deps []project.Dependency, packData project.Project) (map[string]*project.ContentData, error) {
+	return map[string]*project.ContentData{}, nil
+}
+
+func (Provider) DownloadJob(content project.ContentData, dest string) download.Job {
+	return download.JobFromContent(content, dest)
+}
+
+func (Provider) LookupHashes(ctx context.Context, hashes []string, format project.HashFormat) (map[string]project.ContentData, error) {
+	return nil, fmt.Errorf("github can't look files up by hash: %w", api.ErrUnsupported)
+}
+
+// LatestVersion finds the newest release for content, using the asset pattern it was added with
+func (p Provider) LatestVersion(ctx context.Context, content project.ContentData, packData project.Project) (*project.ContentData, error) {
+	id := content.Id
+	if pattern := content.SourceOptions["asset"]; pattern != "" {
+		id += "#" + pattern
+	}
+	return p.GetProject(ctx, id, packData)
+}
+
+// IsNewer compares release tags, ignoring a leading "v"
+func (Provider) IsNewer(current project.ContentData, candidate project.ContentData) bool {
+	return flexver.Compare(trimTag(candidate.VersionId), trimTag(current.VersionId)) > 0
+}
+
+func trimTag(tag string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(tag, "v"), "V")
+}
+
+// ConvertReleaseToContentData converts a release asset to minepack's content format
+func ConvertReleaseToContentData(repo *Repository, release *Release, asset *Asset, pattern string) project.ContentData {
+	content := project.ContentData{
+		ContentType: project.Mod,
+		Name:        repo.Name,
+		Id:          repo.FullName,
+		Slug:        strings.ToLower(strings.ReplaceAll(repo.FullName, "/", "-")), // owner qualified, forks and unrelated repos share names
+		Side: project.ModSide{
+			Client: project.SideUnknown,
+			Server: project.SideUnknown,
+		},
+		PageUrl:     repo.HTMLURL,
+		DownloadUrl: asset.BrowserDownloadURL,
+		VersionId:   release.TagName,
+		Source:      project.GitHub,
+		File: project.FileData{
+			Filename: asset.Name,
+			Filesize: asset.Size,
+			Filepath: "mods/" + asset.Name,
+		},
+		Dependencies: []project.Dependency{},
+	}
+
+	// newer releases publish a sha256 digest, everything else is computed on first download
+	if algorithm, sum, ok := strings.Cut(asset.Digest, ":"); ok && algorithm == "sha256" {
+		content.File.Hashes.Sha256 = sum
+	}
+
+	if pattern != "" {
+		content.SourceOptions = map[string]string{"asset": pattern}
+	}
+	return content
+}
+
+// Redistribution allows repositories whose license permits sharing their releases
+func (Provider) Redistribution(ctx context.Context, ids []string) (map[string]api.Redistribution, error) {
+	repoNames := make(map[string]string, len(ids))
+	for _, id := range ids {
+		repoName, _, err := splitID(id)
+		if err != nil {
+			return nil, err
+		}
+		repoNames[id] = repoName
+	}
+	repos, err := GetRepositories(ctx, slices.Collect(maps.Values(repoNames)))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]api.Redistribution, len(ids))
+	for id, repoName := range repoNames {
+		result[id] = api.LicenseRedistribution(repositoryLicense(repos[repoName]))
+	}
+	return result, nil
+}
+
+// Describe looks up the license and topics of repositories, release tags are already readable
+func (Provider) Describe(ctx context.Context, contents []project.ContentData) (map[string]api.Details, error) {
+	repoNames := make(map[string]string, len(contents))
+	for _, content := range contents {
+		repoName, _, err := splitID(content.Id)
+		if err != nil {
+			return nil, err
+		}
+		repoNames[content.Id] = repoName
+	}
+	repos, err := GetRepositories(ctx, slices.Collect(maps.Values(repoNames)))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]api.Details, len(contents))
+	for _, content := range contents {
+		repo := repos[repoNames[content.Id]]
+		result[content.Id] = api.Details{Version: content.VersionId, Tags: repo.Topics, License: repositoryLicense(repo)}
+	}
+	return result, nil
+}
+
+// repositoryLicense is the SPDX id of a repository's license, empty when github couldn't tell
+func repositoryLicense(repo *Repository) string {
+	if repo.License == nil || repo.License.SpdxID == "NOASSERTION" {
+		return ""
+	}
+	return repo.License.SpdxID
+}
@@ -0,0 +1,168 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"minepack/core"
+	"minepack/core/cache"
+	"minepack/core/project"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var testPack = project.Project{
+	Versions: project.ProjectVersions{
+		Game:   "1.21.1",
+		Loader: project.ModloaderVersion{Name: "fabric"},
+	},
+}
+
+func asset(name string) Asset {
+	return Asset{Name: name, BrowserDownloadURL: "https://example.com/" + name, Size: 1}
+}
+
+// serveReleases stands in for the github API of a single repository
+func serveReleases(t *testing.T, releases []Release) {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Repository{
+			Name:     "repo",
+			FullName: "owner/repo",
+			HTMLURL:  "https://github.com/owner/repo",
+		})
+	})
+	mux.HandleFunc("/repos/owner/repo/releases", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(releases)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	// responses of the stand-in server must never end up in the user's metadata cache
+	store, err := cache.OpenMetadataAt(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	core.UseStore(GitHubClient.httpClient, store)
+	t.Cleanup(func() { core.UseStore(GitHubClient.httpClient, core.MetadataStore()) })
+
+	previous := BaseURL
+	t.Cleanup(func() { BaseURL = previous })
+	if err := (Provider{}).Configure(project.SourceSettings{BaseURL: srv.URL}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPickAsset(t *testing.T) {
+	release := Release{TagName: "v1.0.0", Assets: []Asset{
+		asset("repo-1.0.0-sources.jar"),
+		asset("repo-forge-1.21.1-1.0.0.jar"),
+		asset("repo-neoforge-1.21.1-1.0.0.jar"),
+		asset("repo-fabric-1.20.1-1.0.0.jar"),
+		asset("repo-fabric-1.21.1-1.0.0.jar"),
+		asset("repo-extras-1.21.1.zip"),
+	}}
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    string
+	}{
+		{"prefers loader and game version", "", "repo-fabric-1.21.1-1.0.0.jar"},
+		{"pattern with placeholders", "repo-{loader}-1.20.1-*.jar", "repo-fabric-1.20.1-1.0.0.jar"},
+		{"pattern is case insensitive", "REPO-NEOFORGE-{mc}-*.jar", "repo-neoforge-1.21.1-1.0.0.jar"},
+		{"pattern matches non jar assets", "*-extras-{mc}.zip", "repo-extras-1.21.1.zip"},
+		{"pattern without a match", "*-quilt-*.jar", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := PickAsset(release, tt.pattern, testPack)
+			if tt.want == "" {
+				if ok {
+					t.Fatalf("expected no asset, got %s", got.Name)
+				}
+				return
+			}
+			if !ok || got.Name != tt.want {
+				t.Fatalf("expected %s, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		current, candidate string
+		want               bool
+	}{
+		{"v1.2.0", "v1.10.0", true},
+		{"1.2.0", "V1.2.1", true},
+		{"v1.2.0", "1.2.0", false},
+		{"v2.0.0", "v1.9.9", false},
+		{"1.0.0-beta.2", "1.0.0", true},
+	}
+	for _, tt := range tests {
+		current := project.ContentData{VersionId: tt.current}
+		candidate := project.ContentData{VersionId: tt.candidate}
+		if got := (Provider{}).IsNewer(current, candidate); got != tt.want {
+			t.Errorf("IsNewer(%s, %s) = %v, want %v", tt.current, tt.candidate, got, tt.want)
+		}
+	}
+}
+
+func TestGetProjectPicksLatestRelease(t *testing.T) {
+	serveReleases(t, []Release{
+		{TagName: "v3.0.0", Draft: true, Assets: []Asset{asset("repo-fabric-1.21.1-3.0.0.jar")}},
+		{TagName: "v2.1.0-beta", Prerelease: true, Assets: []Asset{asset("repo-fabric-1.21.1-2.1.0.jar")}},
+		{TagName: "v2.0.0", Assets: []Asset{asset("repo-forge-1.21.1-2.0.0.jar")}},
+		{TagName: "v1.5.0", Assets: []Asset{asset("repo-fabric-1.21.1-1.5.0.jar"), asset("repo-forge-1.21.1-1.5.0.jar")}},
+		{TagName: "v1.0.0", Assets: []Asset{asset("repo-fabric-1.21.1-1.0.0.jar")}},
+	})
+
+	content, err := Provider{}.GetProject(context.Background(), "owner/repo", testPack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content.VersionId != "v1.5.0" || content.File.Filename != "repo-fabric-1.21.1-1.5.0.jar" {
+		t.Fatalf("expected v1.5.0 fabric jar, got %s %s", content.VersionId, content.File.Filename)
+	}
+	if content.Slug != "owner-repo" {
+		t.Fatalf("expected slug owner-repo, got %s", content.Slug)
+	}
+
+	content, err = Provider{}.GetProject(context.Background(), "owner/repo#repo-forge-{mc}-*.jar", testPack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content.VersionId != "v2.0.0" || content.SourceOptions["asset"] != "repo-forge-{mc}-*.jar" {
+		t.Fatalf("expected v2.0.0 with the asset pattern kept, got %s %v", content.VersionId, content.SourceOptions)
+	}
+
+	if _, err := (Provider{}).GetProject(context.Background(), "owner/repo#*-quilt-*.jar", testPack); err == nil {
+		t.Fatal("expected an error when no release has a matching asset")
+	}
+}
+
+func TestLatestVersionKeepsPattern(t *testing.T) {
+	serveReleases(t, []Release{
+		{TagName: "v2.0.0", Assets: []Asset{asset("repo-fabric-1.21.1-2.0.0.jar"), asset("repo-forge-1.21.1-2.0.0.jar")}},
+		{TagName: "v1.0.0", Assets: []Asset{asset("repo-forge-1.21.1-1.0.0.jar")}},
+	})
+
+	current := project.ContentData{
+		Id:            "owner/repo",
+		VersionId:     "v1.0.0",
+		SourceOptions: map[string]string{"asset": "repo-forge-*.jar"},
+	}
+	latest, err := Provider{}.LatestVersion(context.Background(), current, testPack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.File.Filename != "repo-forge-1.21.1-2.0.0.jar" {
+		t.Fatalf("expected the forge jar, got %s", latest.File.Filename)
+	}
+	if !(Provider{}).IsNewer(current, *latest) {
+		t.Fatalf("expected %s to be newer than %s", latest.VersionId, current.VersionId)
+	}
+}
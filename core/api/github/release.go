@@ -0,0 +1,186 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"minepack/core/project"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// loaders that can show up in asset names, used to skip assets built for a different loader
+var knownLoaders = []string{"fabric", "forge", "quilt", "neoforge", "liteloader"}
+
+// how many repositories GetRepositories fetches at once
+const repositoryConcurrency = 4
+
+// repositories already fetched during this run, keyed by lower case "owner/repo"
+var knownRepositories sync.Map
+
+// fetches a repository by its "owner/repo" name
+func GetRepository(ctx context.Context, repo string) (*Repository, error) {
+	if known, ok := knownRepositories.Load(strings.ToLower(repo)); ok {
+		return known.(*Repository), nil
+	}
+
+	var result Repository
+	if err := GitHubClient.makeRequest(ctx, "/repos/"+repo, &result); err != nil {
+		return nil, fmt.Errorf("failed to get repository %s: %w", repo, err)
+	}
+	knownRepositories.Store(strings.ToLower(repo), &result)
+	return &result, nil
+}
+
+// fetches many repositories concurrently, keyed by the names they were asked for. the requests
+// still go through the client's rate limiter
+func GetRepositories(ctx context.Context, repos []string) (map[string]*Repository, error) {
+	result := make(map[string]*Repository, len(repos))
+	var mu sync.Mutex
+	var firstErr error
+	var wg sync.WaitGroup
+	limit := make(chan struct{}, repositoryConcurrency)
+	for _, name := range slices.Compact(slices.Sorted(slices.Values(repos))) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+
+			repo, err := GetRepository(ctx, name)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			result[name] = repo
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return result, nil
+}
+
+// fetches the most recent releases of a repository, newest first
+func GetReleases(ctx context.Context, repo string) ([]Release, error) {
+	var result []Release
+	if err := GitHubClient.makeRequest(ctx, "/repos/"+repo+"/releases?per_page=50", &result); err != nil {
+		return nil, fmt.Errorf("failed to get releases for %s: %w", repo, err)
+	}
+	return result, nil
+}
+
+// searches github for repositories matching the query
+func SearchRepositories(ctx context.Context, query string) ([]Repository, error) {
+	params := url.Values{}
+	params.Set("q", query+" minecraft in:name,description,topics")
+	params.Set("per_page", "20")
+
+	var result SearchResponse
+	if err := GitHubClient.makeRequest(ctx, "/search/repositories?"+params.Encode(), &result); err != nil {
+		return nil, fmt.Errorf("failed to search repositories: %w", err)
+	}
+	return result.Items, nil
+}
+
+// mentionsLoader reports whether an asset name refers to a loader, so "neoforge" doesn't count as "forge"
+func mentionsLoader(name string, loader string) bool {
+	if loader == "forge" {
+		name = strings.ReplaceAll(name, "neoforge", "")
+	}
+	return strings.Contains(name, loader)
+}
+
+// isModJar filters out assets that are never what a pack wants, like sources and javadoc jars
+func isModJar(name string) bool {
+	if !strings.HasSuffix(name, ".jar") {
+		return false
+	}
+	for _, suffix := range []string{"-sources.jar", "-javadoc.jar", "-dev.jar", "-api.jar"} {
+		if strings.HasSuffix(name, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// expandPattern fills in the {mc} and {loader} placeholders of an asset pattern
+func expandPattern(pattern string, packData project.Project) string {
+	pattern = strings.ReplaceAll(pattern, "{mc}", packData.Versions.Game)
+	pattern = strings.ReplaceAll(pattern, "{loader}", packData.Versions.Loader.Name)
+	return strings.ToLower(pattern)
+}
+
+// PickAsset chooses the asset of a release to use for a pack. with a pattern, the first asset whose
+// name matches it is used. without one, jars built for another loader are skipped and the asset
+// naming the pack's game version and loader is preferred
+func PickAsset(release Release, pattern string, packData project.Project) (*Asset, bool) {
+	if pattern != "" {
+		expanded := expandPattern(pattern, packData)
+		for i, asset := range release.Assets {
+			if ok, _ := path.Match(expanded, strings.ToLower(asset.Name)); ok {
+				return &release.Assets[i], true
+			}
+		}
+		return nil, false
+	}
+
+	game := packData.Versions.Game
+	loader := strings.ToLower(packData.Versions.Loader.Name)
+
+	var best *Asset
+	bestScore := -1
+	for i, asset := range release.Assets {
+		name := strings.ToLower(asset.Name)
+		if !isModJar(name) {
+			continue
+		}
+
+		score := 0
+		otherLoader := false
+		for _, l := range knownLoaders {
+			if l != loader && mentionsLoader(name, l) {
+				otherLoader = true
+			}
+		}
+		if loader != "" && mentionsLoader(name, loader) {
+			score += 2
+			otherLoader = false
+		}
+		if otherLoader {
+			continue
+		}
+		if game != "" && strings.Contains(name, game) {
+			score += 3
+		}
+
+		if score > bestScore {
+			best, bestScore = &release.Assets[i], score
+		}
+	}
+	return best, best != nil
+}
+
+// LatestRelease finds the newest full release with an asset usable by the pack
+func LatestRelease(ctx context.Context, repo string, pattern string, packData project.Project) (*Release, *Asset, error) {
+	releases, err := GetReleases(ctx, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, release := range releases {
+		if release.Draft || release.Prerelease {
+			continue
+		}
+		if asset, ok := PickAsset(release, pattern, packData); ok {
+			return &releases[i], asset, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("no release of %s has an asset for %s %s", repo, packData.Versions.Loader.Name, packData.Versions.Game)
+}
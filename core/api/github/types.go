@@ -0,0 +1,51 @@
+package github
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when a repository or release doesn't exist
+var ErrNotFound = errors.New("not found on github")
+
+// github API response types
+type Repository struct {
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	FullName    string   `json:"full_name"`
+	Description string   `json:"description"`
+	HTMLURL     string   `json:"html_url"`
+	Archived    bool     `json:"archived"`
+	License     *License `json:"license"`
+	Topics      []string `json:"topics"`
+}
+
+type License struct {
+	Key    string `json:"key"`
+	Name   string `json:"name"`
+	SpdxID string `json:"spdx_id"` // "NOASSERTION" when github doesn't recognise the license
+}
+
+type SearchResponse struct {
+	TotalCount int          `json:"total_count"`
+	Items      []Repository `json:"items"`
+}
+
+type Release struct {
+	ID          int64     `json:"id"`
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	HTMLURL     string    `json:"html_url"`
+	PublishedAt time.Time `json:"published_at"`
+	Assets      []Asset   `json:"assets"`
+}
+
+type Asset struct {
+	ID                 int64  `json:"id"`
+	Name               string `json:"name"`
+	Size               int64  `json:"size"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+	Digest             string `json:"digest"` // "sha256:<hex>", only set on newer releases
+}
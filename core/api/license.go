@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"minepack/core/project"
+	"strings"
+)
+
+// Redistribution tells whether a file may be shipped inside a pack instead of being downloaded
+// from its source by the launcher
+type Redistribution struct {
+	Allowed bool
+	Reason  string // why it may or may not be shipped, e.g. the license, shown to the user
+}
+
+// Distributor is implemented by providers that know whether their content may be redistributed,
+// e.g. from its license. results are keyed by content id
+type Distributor interface {
+	Redistribution(ctx context.Context, ids []string) (map[string]Redistribution, error)
+}
+
+// licenses that allow a mod's jar to be shipped inside a pack, by SPDX id without the
+// -only/-or-later suffix
+var redistributableLicenses = map[string]bool{
+	"0BSD":         true,
+	"AGPL-3.0":     true,
+	"Apache-2.0":   true,
+	"BSD-2-Clause": true,
+	"BSD-3-Clause": true,
+	"BSL-1.0":      true,
+	"CC-BY-4.0":    true,
+	"CC-BY-SA-4.0": true,
+	"CC0-1.0":      true,
+	"EPL-2.0":      true,
+	"EUPL-1.2":     true,
+	"GPL-2.0":      true,
+	"GPL-3.0":      true,
+	"ISC":          true,
+	"LGPL-2.1":     true,
+	"LGPL-3.0":     true,
+	"MIT":          true,
+	"MIT-0":        true,
+	"MPL-2.0":      true,
+	"OSL-3.0":      true,
+	"Unlicense":    true,
+	"WTFPL":        true,
+	"Zlib":         true,
+}
+
+// LicenseRedistribution reports whether a license, given as an SPDX id, allows redistribution
+func LicenseRedistribution(spdx string) Redistribution {
+	if spdx == "" {
+		return Redistribution{Allowed: false, Reason: "unknown license"}
+	}
+	id := strings.TrimSuffix(strings.TrimSuffix(spdx, "-only"), "-or-later")
+	if redistributableLicenses[id] {
+		return Redistribution{Allowed: true, Reason: spdx}
+	}
+	return Redistribution{Allowed: false, Reason: "licensed " + spdx}
+}
+
+// CanRedistribute asks each content's provider whether it may be shipped inside a pack, keyed by
+// slug. content whose provider can't tell is never allowed
+func CanRedistribute(ctx context.Context, contents []project.ContentData) (map[string]Redistribution, error) {
+	result := make(map[string]Redistribution, len(contents))
+
+	for source, sourceContents := range groupBySource(contents) {
+		var found map[string]Redistribution
+		if p, ok := ForSource(source); ok {
+			if distributor, ok := p.(Distributor); ok {
+				var err error
+				found, err = distributor.Redistribution(ctx, contentIDs(sourceContents))
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		for _, content := range sourceContents {
+			if r, ok := found[content.Id]; ok {
+				result[content.Slug] = r
+			} else {
+				result[content.Slug] = Redistribution{Allowed: false, Reason: "unknown license"}
+			}
+		}
+	}
+	return result, nil
+}
+
+// groupBySource splits content by source, so each provider can be asked about all of its content at once
+func groupBySource(contents []project.ContentData) map[project.Source][]project.ContentData {
+	bySource := make(map[project.Source][]project.ContentData)
+	for _, content := range contents {
+		bySource[content.Source] = append(bySource[content.Source], content)
+	}
+	return bySource
+}
+
+func contentIDs(contents []project.ContentData) []string {
+	ids := make([]string, len(contents))
+	for i, content := range contents {
+		ids[i] = content.Id
+	}
+	return ids
+}
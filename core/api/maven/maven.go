@@ -0,0 +1,163 @@
+package maven
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"minepack/core"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/unascribed/FlexVer/go/flexver"
+)
+
+// Coordinates point at an artifact in a maven repository
+type Coordinates struct {
+	Repository string // base URL of the repository, without a trailing slash
+	Group      string
+	Artifact   string
+	Version    string // empty for the newest compatible version
+}
+
+var portPattern = regexp.MustCompile(`^\d+(/|$)`)
+
+// ParseCoordinates parses "https://maven.example.com:group:artifact[:version]". the repository URL
+// may contain a port, which is told apart from the group by being numeric
+func ParseCoordinates(id string) (Coordinates, error) {
+	scheme, rest, found := strings.Cut(id, "://")
+	if !found {
+		return Coordinates{}, fmt.Errorf("invalid maven coordinates %q, expected url:group:artifact[:version]", id)
+	}
+
+	parts := strings.Split(rest, ":")
+	if len(parts) > 1 && portPattern.MatchString(parts[1]) {
+		parts = append([]string{parts[0] + ":" + parts[1]}, parts[2:]...)
+	}
+	if len(parts) < 3 || len(parts) > 4 || parts[1] == "" || parts[2] == "" {
+		return Coordinates{}, fmt.Errorf("invalid maven coordinates %q, expected url:group:artifact[:version]", id)
+	}
+
+	coords := Coordinates{
+		Repository: scheme + "://" + strings.TrimSuffix(parts[0], "/"),
+		Group:      parts[1],
+		Artifact:   parts[2],
+	}
+	if len(parts) == 4 {
+		coords.Version = parts[3]
+	}
+	return coords, nil
+}
+
+// ID is the identifier stored in content files, it leaves out the version so updates can change it
+func (c Coordinates) ID() string {
+	return c.Repository + ":" + c.Group + ":" + c.Artifact
+}
+
+// ArtifactURL is the directory holding every version of the artifact
+func (c Coordinates) ArtifactURL() string {
+	return c.Repository + "/" + strings.ReplaceAll(c.Group, ".", "/") + "/" + c.Artifact + "/"
+}
+
+// MetadataURL is where the artifact's maven-metadata.xml lives
+func (c Coordinates) MetadataURL() string {
+	return c.ArtifactURL() + "maven-metadata.xml"
+}
+
+// JarURL is the primary jar of a version
+func (c Coordinates) JarURL(version string) string {
+	return c.ArtifactURL() + version + "/" + c.Artifact + "-" + version + ".jar"
+}
+
+// versionComponents splits a version like "1.2.0+1.20.1" into its parts
+func versionComponents(version string) []string {
+	return strings.FieldsFunc(version, func(r rune) bool { return r == '-' || r == '+' || r == '_' })
+}
+
+// mentionsGameVersion reports whether a version was built for a minecraft version, like
+// "1.2.0+1.20.1" or "1.20.1-1.2.0"
+func mentionsGameVersion(version string, mcVersion string) bool {
+	for _, component := range versionComponents(version) {
+		if component == mcVersion || component == "mc"+mcVersion {
+			return true
+		}
+	}
+	return false
+}
+
+// ListVersions fetches the versions of an artifact usable with a minecraft version, newest first.
+// artifacts that never mention a minecraft version, like libraries, list every version
+func ListVersions(ctx context.Context, coords Coordinates, mcVersion string) ([]string, error) {
+	metadata, err := core.FetchMavenMetadata(ctx, coords.MetadataURL())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get versions of %s: %w", coords.ID(), err)
+	}
+
+	var versions []string
+	for _, v := range metadata.Versioning.Versions.Version {
+		if mentionsGameVersion(v, mcVersion) {
+			versions = append(versions, v)
+		}
+	}
+	if len(versions) == 0 {
+		for _, v := range metadata.Versioning.Versions.Version {
+			if looksGameVersioned(v) {
+				return nil, fmt.Errorf("no versions of %s available for minecraft %s", coords.Artifact, mcVersion)
+			}
+		}
+		versions = metadata.Versioning.Versions.Version
+	}
+
+	sorted := append([]string{}, versions...)
+	flexver.VersionSlice(sorted).Sort()
+	for i, j := 0, len(sorted)-1; i < j; i, j = i+1, j-1 {
+		sorted[i], sorted[j] = sorted[j], sorted[i]
+	}
+	return sorted, nil
+}
+
+var gameVersionPattern = regexp.MustCompile(`^(mc)?1\.\d+(\.\d+)?$`)
+
+// looksGameVersioned reports whether a version names some minecraft version next to its own
+// version number, so "1.2.0+1.20.1" does but plain "1.2.0" or "1.2.0-beta" don't
+func looksGameVersioned(version string) bool {
+	numeric := 0
+	gameVersion := false
+	for _, component := range versionComponents(version) {
+		if component[0] >= '0' && component[0] <= '9' || strings.HasPrefix(component, "mc") {
+			numeric++
+		}
+		if gameVersionPattern.MatchString(component) {
+			gameVersion = true
+		}
+	}
+	return gameVersion && numeric >= 2
+}
+
+// FetchChecksum reads a published .sha1 or .sha512 file next to an artifact, returning an empty
+// string if the repository doesn't publish one
+func FetchChecksum(ctx context.Context, fileURL string, extension string) (string, error) {
+	res, err := core.GetWithContext(ctx, fileURL+"."+extension, "text/plain")
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s checksum: %w", extension, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get %s checksum: %s", extension, res.Status)
+	}
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s checksum: %w", extension, err)
+	}
+	// some repositories append the filename after the hash
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return strings.ToLower(fields[0]), nil
+}
@@ -0,0 +1,152 @@
+package maven
+
+import (
+	"context"
+	"fmt"
+	"minepack/core/api"
+	"minepack/core/download"
+	"minepack/core/project"
+	"strings"
+
+	"github.com/unascribed/FlexVer/go/flexver"
+)
+
+// Provider exposes maven repositories through the core/api provider interface. projects are
+// identified by "https://repo.example.com:group:artifact", optionally followed by ":version"
+type Provider struct{}
+
+func init() {
+	api.Register(Provider{})
+}
+
+func (Provider) Name() string {
+	return "maven"
+}
+
+func (Provider) Source() project.Source {
+	return project.Maven
+}
+
+// maven repositories have no common website layout, so links are never recognised
+func (Provider) ParseURL(url string) (string, bool) {
+	return "", false
+}
+
+func (Provider) Search(ctx context.Context, query string, packData project.Project) ([]api.SearchHit, error) {
+	return nil, fmt.Errorf("maven repositories can't be searched, use maven:url:group:artifact: %w", api.ErrUnsupported)
+}
+
+func (Provider) GetProject(ctx context.Context, id string, packData project.Project) (*project.ContentData, error) {
+	coords, err := ParseCoordinates(id)
+	if err != nil {
+		return nil, err
+	}
+
+	version := coords.Version
+	if version == "" {
+		versions, err := ListVersions(ctx, coords, packData.Versions.Game)
+		if err != nil {
+			return nil, err
+		}
+		if len(versions) == 0 {
+			return nil, fmt.Errorf("no versions of %s found", coords.ID())
+		}
+		version = versions[0]
+	}
+
+	content, err := ConvertArtifactToContentData(ctx, coords, version)
+	if err != nil {
+		return nil, err
+	}
+	return &content, nil
+}
+
+func (Provider) ListVersions(ctx context.Context, id string, packData project.Project) ([]project.ContentData, error) {
+	coords, err := ParseCoordinates(id)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := ListVersions(ctx, coords, packData.Versions.Game)
+	if err != nil {
+		return nil, err
+	}
+
+	contents := make([]project.ContentData, 0, len(versions))
+	for _, version := range versions {
+		content, err := ConvertArtifactToContentData(ctx, coords, version)
+		if err != nil {
+			return nil, err
+		}
+		contents = append(contents, content)
+	}
+	return contents, nil
+}
+
+// poms can declare dependencies, but they name libraries rather than mods so they aren't followed
+func (Provider) ResolveDependencies(ctx context.Context, deps []project.Dependency, packData project.Project) (map[string]*project.ContentData, error) {
+	return map[string]*project.ContentData{}, nil
+}
+
+func (Provider) DownloadJob(content project.ContentData, dest string) download.Job {
+	return download.JobFromContent(content, dest)
+}
+
+func (Provider) LookupHashes(ctx context.Context, hashes []string, format project.HashFormat) (map[string]project.ContentData, error) {
+	return nil, fmt.Errorf("maven can't look files up by hash: %w", api.ErrUnsupported)
+}
+
+// LatestVersion keeps content that was added with a fixed version on that version
+func (p Provider) LatestVersion(ctx context.Context, content project.ContentData, packData project.Project) (*project.ContentData, error) {
+	id := content.Id
+	if version := content.SourceOptions["version"]; version != "" {
+		id += ":" + version
+	}
+	return p.GetProject(ctx, id, packData)
+}
+
+func (Provider) IsNewer(current project.ContentData, candidate project.ContentData) bool {
+	return flexver.Compare(candidate.VersionId, current.VersionId) > 0
+}
+
+// ConvertArtifactToContentData builds content for a version of an artifact, reading the checksums
+// the repository publishes so the download can be verified
+func ConvertArtifactToContentData(ctx context.Context, coords Coordinates, version string) (project.ContentData, error) {
+	jarURL := coords.JarURL(version)
+	filename := coords.Artifact + "-" + version + ".jar"
+
+	content := project.ContentData{
+		ContentType: project.Mod,
+		Name:        coords.Artifact,
+		Id:          coords.ID(),
+		Slug:        strings.ToLower(coords.Artifact),
+		Side: project.ModSide{
+			Client: project.SideUnknown,
+			Server: project.SideUnknown,
+		},
+		PageUrl:     coords.ArtifactURL(),
+		DownloadUrl: jarURL,
+		VersionId:   version,
+		Source:      project.Maven,
+		File: project.FileData{
+			Filename: filename,
+			Filepath: "mods/" + filename,
+		},
+		Dependencies: []project.Dependency{},
+	}
+
+	sha512, err := FetchChecksum(ctx, jarURL, "sha512")
+	if err != nil {
+		return content, err
+	}
+	sha1, err := FetchChecksum(ctx, jarURL, "sha1")
+	if err != nil {
+		return content, err
+	}
+	content.File.Hashes.Sha512 = sha512
+	content.File.Hashes.Sha1 = sha1
+
+	if coords.Version != "" {
+		content.SourceOptions = map[string]string{"version": coords.Version}
+	}
+	return content, nil
+}
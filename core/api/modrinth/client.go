@@ -1,9 +1,54 @@
 package modrinth
 
 import (
-	"net/http"
+	"context"
+	"fmt"
+	"minepack/core"
+	"minepack/core/config"
+	"minepack/core/project"
+	"net/url"
+	"strings"
 
 	"codeberg.org/jmansfield/go-modrinth/modrinth"
 )
 
-var ModrinthClient = modrinth.NewClient(&http.Client{})
+var httpClient = core.NewSourceClient("modrinth", 0)
+
+var ModrinthClient = newModrinthClient()
+
+func newModrinthClient() *modrinth.Client {
+	client := modrinth.NewClient(httpClient)
+	client.UserAgent = core.UserAgent()
+	return client
+}
+
+// clientFor returns a client with ModrinthClient's settings whose requests stop when ctx is
+// cancelled, go-modrinth has no way to pass a context per call
+func clientFor(ctx context.Context) *modrinth.Client {
+	client := modrinth.NewClient(core.WithContext(ctx, httpClient))
+	client.BaseURL = ModrinthClient.BaseURL
+	client.UserAgent = ModrinthClient.UserAgent
+	client.Token = ModrinthClient.Token
+	return client
+}
+
+// Configure points the modrinth client at a different API, e.g. a mirror, applies HTTP settings
+// and picks up a personal access token for private and unlisted projects
+func (Provider) Configure(settings project.SourceSettings) error {
+	if token, _ := config.Token("modrinth"); token != "" {
+		ModrinthClient.Token = token
+	}
+	if settings.BaseURL != "" {
+		// the client resolves endpoints relative to the base, so it needs the trailing slash
+		baseURL, err := url.Parse(strings.TrimSuffix(settings.BaseURL, "/") + "/")
+		if err != nil {
+			return fmt.Errorf("invalid base url %s: %w", settings.BaseURL, err)
+		}
+		ModrinthClient.BaseURL = baseURL
+	}
+	if settings.UserAgent != "" {
+		ModrinthClient.UserAgent = settings.UserAgent
+	}
+	core.ApplySettings(httpClient, settings)
+	return nil
+}
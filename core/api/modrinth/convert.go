@@ -1,6 +1,7 @@
 package modrinth
 
 import (
+	"context"
 	"fmt"
 	"minepack/core/project"
 
@@ -49,7 +50,7 @@ func modrinthProjectSideToModSideData(pt string) project.ModSideData {
 }
 
 // converts a full project to ContentData with version info
-func ConvertProjectToContentData(proj *modrinth.Project, version *modrinth.Version) project.ContentData {
+func ConvertProjectToContentData(ctx context.Context, proj *modrinth.Project, version *modrinth.Version) project.ContentData {
 	// handle pointer dereferences safely
 	name := ""
 	if proj.Title != nil {
@@ -137,9 +138,16 @@ func ConvertProjectToContentData(proj *modrinth.Project, version *modrinth.Versi
 
 		// add dependencies if available
 		if len(version.Dependencies) > 0 {
+			// look up every dependency project in one request instead of one per dependency
+			if err := PrefetchDependencies(ctx, []*modrinth.Version{version}); err != nil {
+				fmt.Printf("error fetching dependency data for %s: %v\n", name, err)
+			}
+
 			for _, dep := range version.Dependencies {
-				// fetch the dependency project to get its slug and name
-				depProj, err := GetProject(*dep.ProjectID)
+				if dep.ProjectID == nil {
+					continue
+				}
+				depProj, err := GetProject(ctx, *dep.ProjectID)
 				if err != nil {
 					continue
 				}
@@ -166,10 +174,16 @@ func ConvertProjectToContentData(proj *modrinth.Project, version *modrinth.Versi
 					}
 				}
 
+				depVersion := ""
+				if dep.VersionID != nil {
+					depVersion = *dep.VersionID
+				}
+
 				contentData.Dependencies = append(contentData.Dependencies, project.Dependency{
 					Name:           depName,
 					Slug:           depSlug,
 					Id:             *dep.ProjectID,
+					VersionId:      depVersion,
 					DependencyType: depType,
 				})
 			}
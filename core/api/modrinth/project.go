@@ -1,24 +1,156 @@
 package modrinth
 
 import (
+	"context"
 	"fmt"
 	"minepack/core/project"
+	"sync"
 
 	"codeberg.org/jmansfield/go-modrinth/modrinth"
 )
 
+// bulkChunkSize keeps bulk request URLs well below common length limits
+const bulkChunkSize = 100
+
+// projects already fetched during this run, keyed by both id and slug
+var knownProjects sync.Map
+
+func rememberProject(proj *modrinth.Project) {
+	if proj.ID != nil {
+		knownProjects.Store(*proj.ID, proj)
+	}
+	if proj.Slug != nil {
+		knownProjects.Store(*proj.Slug, proj)
+	}
+}
+
 // fetches detailed project information
-func GetProject(projectID string) (*modrinth.Project, error) {
-	project, err := ModrinthClient.Projects.Get(projectID)
+func GetProject(ctx context.Context, projectID string) (*modrinth.Project, error) {
+	if known, ok := knownProjects.Load(projectID); ok {
+		return known.(*modrinth.Project), nil
+	}
+	project, err := clientFor(ctx).Projects.Get(projectID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get project %s: %w", projectID, err)
 	}
+	if project.ID == nil {
+		return nil, fmt.Errorf("failed to get project %s: response has no project id", projectID)
+	}
+	rememberProject(project)
 	return project, nil
 }
 
+// fetches many projects by id or slug using the bulk /projects endpoint, keyed by the
+// identifier they were requested with. unknown projects are left out of the result
+func GetProjects(ctx context.Context, projectIDs []string) (map[string]*modrinth.Project, error) {
+	result := make(map[string]*modrinth.Project)
+
+	var missing []string
+	seen := make(map[string]bool)
+	for _, id := range projectIDs {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if known, ok := knownProjects.Load(id); ok {
+			result[id] = known.(*modrinth.Project)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+
+	client := clientFor(ctx)
+	for start := 0; start < len(missing); start += bulkChunkSize {
+		chunk := missing[start:min(start+bulkChunkSize, len(missing))]
+		projects, err := client.Projects.GetMultiple(chunk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %d projects: %w", len(chunk), err)
+		}
+		for _, proj := range projects {
+			rememberProject(proj)
+		}
+		for _, id := range chunk {
+			if known, ok := knownProjects.Load(id); ok {
+				result[id] = known.(*modrinth.Project)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// fetches many versions by id using the bulk /versions endpoint, keyed by version id
+func GetVersions(ctx context.Context, versionIDs []string) (map[string]*modrinth.Version, error) {
+	result := make(map[string]*modrinth.Version)
+	client := clientFor(ctx)
+	for start := 0; start < len(versionIDs); start += bulkChunkSize {
+		chunk := versionIDs[start:min(start+bulkChunkSize, len(versionIDs))]
+		versions, err := client.Versions.GetMultiple(chunk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %d versions: %w", len(chunk), err)
+		}
+		for _, version := range versions {
+			if version.ID != nil {
+				result[*version.ID] = version
+			}
+		}
+	}
+	return result, nil
+}
+
+// identifies files by their sha1 or sha512 hash, keyed by the hash a version was found with
+func GetVersionsFromHashes(ctx context.Context, hashes []string, algorithm string) (map[string]*modrinth.Version, error) {
+	return clientFor(ctx).VersionFiles.GetFromHashes(hashes, algorithm)
+}
+
+// fetches the projects every dependency of the given versions points at in as few requests
+// as possible, so converting the versions afterwards doesn't need any more lookups
+func PrefetchDependencies(ctx context.Context, versions []*modrinth.Version) error {
+	var projectIDs []string
+	var versionOnly []string
+	for _, version := range versions {
+		if version == nil {
+			continue
+		}
+		for _, dep := range version.Dependencies {
+			switch {
+			case dep.ProjectID != nil:
+				projectIDs = append(projectIDs, *dep.ProjectID)
+			case dep.VersionID != nil:
+				versionOnly = append(versionOnly, *dep.VersionID)
+			}
+		}
+	}
+
+	// some dependencies only name a version, find out which project it belongs to
+	if len(versionOnly) > 0 {
+		depVersions, err := GetVersions(ctx, versionOnly)
+		if err != nil {
+			return err
+		}
+		for _, version := range versions {
+			if version == nil {
+				continue
+			}
+			for _, dep := range version.Dependencies {
+				if dep.ProjectID != nil || dep.VersionID == nil {
+					continue
+				}
+				if depVersion, ok := depVersions[*dep.VersionID]; ok && depVersion.ProjectID != nil {
+					dep.ProjectID = depVersion.ProjectID
+					projectIDs = append(projectIDs, *depVersion.ProjectID)
+				}
+			}
+		}
+	}
+
+	_, err := GetProjects(ctx, projectIDs)
+	return err
+}
+
 // fetches all versions for a project
-func GetProjectVersions(projectID string, packData project.Project) ([]*modrinth.Version, error) {
-	versions, err := ModrinthClient.Versions.ListVersions(projectID, modrinth.ListVersionsOptions{
+func GetProjectVersions(ctx context.Context, projectID string, packData project.Project) ([]*modrinth.Version, error) {
+	versions, err := clientFor(ctx).Versions.ListVersions(projectID, modrinth.ListVersionsOptions{
 		GameVersions: []string{packData.Versions.Game},
 		Loaders:      []string{packData.Versions.Loader.Name},
 	})
@@ -27,3 +159,24 @@ func GetProjectVersions(projectID string, packData project.Project) ([]*modrinth
 	}
 	return versions, nil
 }
+
+// fetches a modpack's version by id or version number, or its newest version when version is
+// empty. modpacks pick their own game version, so versions aren't filtered by the pack's
+func GetModpackVersion(ctx context.Context, projectID string, version string) (*modrinth.Version, error) {
+	versions, err := clientFor(ctx).Versions.ListVersions(projectID, modrinth.ListVersionsOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get versions for project %s: %w", projectID, err)
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("project %s has no versions", projectID)
+	}
+	if version == "" {
+		return versions[0], nil
+	}
+	for _, v := range versions {
+		if (v.ID != nil && *v.ID == version) || (v.VersionNumber != nil && *v.VersionNumber == version) {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("project %s has no version %s", projectID, version)
+}
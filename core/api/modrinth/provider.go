@@ -0,0 +1,294 @@
+package modrinth
+
+import (
+	"context"
+	"fmt"
+	"minepack/core/api"
+	"minepack/core/download"
+	"minepack/core/project"
+	"strings"
+	"sync"
+
+	"codeberg.org/jmansfield/go-modrinth/modrinth"
+)
+
+// how many projects ResolveDependencies lists versions for at once
+const dependencyConcurrency = 4
+
+// Provider exposes Modrinth through the core/api provider interface
+type Provider struct{}
+
+func init() {
+	api.Register(Provider{})
+}
+
+func (Provider) Name() string {
+	return "modrinth"
+}
+
+func (Provider) Source() project.Source {
+	return project.Modrinth
+}
+
+// ParseURL extracts the slug from links like https://modrinth.com/mod/<slug>
+func (Provider) ParseURL(url string) (string, bool) {
+	if !strings.Contains(url, "modrinth.com/") {
+		return "", false
+	}
+	parts := strings.Split(strings.TrimSuffix(url, "/"), "/")
+	for i, part := range parts {
+		if strings.HasSuffix(part, "modrinth.com") && i+2 < len(parts) {
+			return parts[i+2], true
+		}
+	}
+	// fallback: last part
+	return parts[len(parts)-1], true
+}
+
+func (Provider) Search(ctx context.Context, query string, packData project.Project) ([]api.SearchHit, error) {
+	results, err := SearchProjects(ctx, query, packData, false)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]api.SearchHit, 0, len(results))
+	for _, r := range results {
+		hit := api.SearchHit{}
+		if r.ProjectID != nil {
+			hit.Id = *r.ProjectID
+		}
+		if r.Slug != nil {
+			hit.Slug = *r.Slug
+		}
+		if r.Title != nil {
+			hit.Name = *r.Title
+		}
+		if r.Description != nil {
+			hit.Summary = *r.Description
+		}
+		hits = append(hits, hit)
+	}
+	return hits, nil
+}
+
+func (p Provider) GetProject(ctx context.Context, id string, packData project.Project) (*project.ContentData, error) {
+	proj, err := GetProject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := GetProjectVersions(ctx, *proj.ID, packData)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no compatible versions found for modrinth project %s", id)
+	}
+	content := ConvertProjectToContentData(ctx, proj, versions[0])
+	return &content, nil
+}
+
+func (Provider) ListVersions(ctx context.Context, id string, packData project.Project) ([]project.ContentData, error) {
+	proj, err := GetProject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := GetProjectVersions(ctx, *proj.ID, packData)
+	if err != nil {
+		return nil, err
+	}
+	if err := PrefetchDependencies(ctx, versions); err != nil {
+		return nil, err
+	}
+
+	contents := make([]project.ContentData, 0, len(versions))
+	for _, version := range versions {
+		contents = append(contents, ConvertProjectToContentData(ctx, proj, version))
+	}
+	return contents, nil
+}
+
+func (Provider) ResolveDependencies(ctx context.Context, deps []project.Dependency, packData project.Project) (map[string]*project.ContentData, error) {
+	ids := make([]string, 0, len(deps))
+	for _, dep := range deps {
+		ids = append(ids, dep.Id)
+	}
+
+	projects, err := GetProjects(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// dependencies on an exact version are fetched together, the newest compatible version of
+	// the others has to be listed per project, which happens concurrently
+	latest := make(map[string]*modrinth.Version)
+	var pinned []string
+	for _, dep := range deps {
+		if dep.VersionId != "" {
+			pinned = append(pinned, dep.VersionId)
+		}
+	}
+	if len(pinned) > 0 {
+		pinnedVersions, err := GetVersions(ctx, pinned)
+		if err != nil {
+			return nil, err
+		}
+		for _, version := range pinnedVersions {
+			if version.ProjectID == nil {
+				continue
+			}
+			if _, ok := projects[*version.ProjectID]; ok {
+				latest[*version.ProjectID] = version
+			}
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	limit := make(chan struct{}, dependencyConcurrency)
+	for id, proj := range projects {
+		if _, ok := latest[id]; ok {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+
+			projectVersions, err := GetProjectVersions(ctx, *proj.ID, packData)
+			if err != nil || len(projectVersions) == 0 {
+				return
+			}
+			mu.Lock()
+			latest[id] = projectVersions[0]
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	versions := make([]*modrinth.Version, 0, len(latest))
+	for _, version := range latest {
+		versions = append(versions, version)
+	}
+	if err := PrefetchDependencies(ctx, versions); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*project.ContentData)
+	for id, version := range latest {
+		content := ConvertProjectToContentData(ctx, projects[id], version)
+		result[id] = &content
+	}
+	return result, nil
+}
+
+// LatestVersions finds the newest compatible version of many projects the way dependencies are
+// resolved, fetching the projects together and listing their versions concurrently
+func (p Provider) LatestVersions(ctx context.Context, contents []project.ContentData, packData project.Project) (map[string]*project.ContentData, error) {
+	deps := make([]project.Dependency, 0, len(contents))
+	for _, content := range contents {
+		deps = append(deps, project.Dependency{Id: content.Id})
+	}
+	return p.ResolveDependencies(ctx, deps, packData)
+}
+
+func (Provider) DownloadJob(content project.ContentData, dest string) download.Job {
+	return download.JobFromContent(content, dest)
+}
+
+func (Provider) LookupHashes(ctx context.Context, hashes []string, format project.HashFormat) (map[string]project.ContentData, error) {
+	algorithm := project.HashFormatToString(format)
+	if format != project.SHA1 && format != project.SHA512 {
+		return nil, fmt.Errorf("modrinth can't look files up by %s: %w", algorithm, api.ErrUnsupported)
+	}
+
+	versions, err := GetVersionsFromHashes(ctx, hashes, algorithm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up hashes: %w", err)
+	}
+
+	var projectIDs []string
+	var versionList []*modrinth.Version
+	for _, version := range versions {
+		if version.ProjectID != nil {
+			projectIDs = append(projectIDs, *version.ProjectID)
+			versionList = append(versionList, version)
+		}
+	}
+	projects, err := GetProjects(ctx, projectIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := PrefetchDependencies(ctx, versionList); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]project.ContentData)
+	for hash, version := range versions {
+		if version.ProjectID == nil {
+			continue
+		}
+		proj, ok := projects[*version.ProjectID]
+		if !ok {
+			continue
+		}
+		result[hash] = ConvertProjectToContentData(ctx, proj, version)
+	}
+	return result, nil
+}
+
+// Redistribution allows projects whose license permits sharing their files
+func (Provider) Redistribution(ctx context.Context, ids []string) (map[string]api.Redistribution, error) {
+	projects, err := GetProjects(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]api.Redistribution, len(projects))
+	for id, proj := range projects {
+		license := ""
+		if proj.Licence != nil && proj.Licence.ID != nil {
+			license = *proj.Licence.ID
+		}
+		result[id] = api.LicenseRedistribution(license)
+	}
+	return result, nil
+}
+
+// Describe looks up licenses and categories of projects and the version numbers of their versions
+func (Provider) Describe(ctx context.Context, contents []project.ContentData) (map[string]api.Details, error) {
+	var ids, versionIDs []string
+	for _, content := range contents {
+		ids = append(ids, content.Id)
+		if content.VersionId != "" {
+			versionIDs = append(versionIDs, content.VersionId)
+		}
+	}
+	projects, err := GetProjects(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := GetVersions(ctx, versionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]api.Details, len(contents))
+	for _, content := range contents {
+		var details api.Details
+		if proj, ok := projects[content.Id]; ok {
+			if proj.Licence != nil && proj.Licence.ID != nil {
+				details.License = *proj.Licence.ID
+			}
+			details.Tags = append(append(details.Tags, proj.Categories...), proj.AdditionalCategories...)
+		}
+		if version, ok := versions[content.VersionId]; ok && version.VersionNumber != nil {
+			details.Version = *version.VersionNumber
+		}
+		result[content.Id] = details
+	}
+	return result, nil
+}
@@ -1,6 +1,7 @@
 package modrinth
 
 import (
+	"context"
 	"fmt"
 	"minepack/core/project"
 
@@ -8,7 +9,7 @@ import (
 )
 
 // searches modrinth for projects matching the query and optional pack data
-func SearchProjects(query string, projectData project.Project, verbose bool) ([]*modrinth.SearchResult, error) {
+func SearchProjects(ctx context.Context, query string, projectData project.Project, verbose bool) ([]*modrinth.SearchResult, error) {
 	if verbose {
 		fmt.Printf("starting search for query: %s\n", query)
 		if projectData.Versions.Game != "" || projectData.Versions.Loader.Name != "" {
@@ -22,7 +23,7 @@ func SearchProjects(query string, projectData project.Project, verbose bool) ([]
 	}
 
 	// add facets if project data is provided
-	if projectData != (project.Project{}) {
+	if !projectData.IsEmpty() {
 		var facets [][]string
 
 		// add game version facet
@@ -45,7 +46,7 @@ func SearchProjects(query string, projectData project.Project, verbose bool) ([]
 		}
 	}
 
-	response, err := ModrinthClient.Projects.Search(searchOptions)
+	response, err := clientFor(ctx).Projects.Search(searchOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search projects: %w", err)
 	}
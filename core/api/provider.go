@@ -0,0 +1,234 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"minepack/core/download"
+	"minepack/core/project"
+	"sort"
+	"sync"
+)
+
+// DefaultProvider is used when a project doesn't name a default source
+const DefaultProvider = "modrinth"
+
+// ErrUnsupported is returned by providers for operations their source can't do
+var ErrUnsupported = errors.New("not supported by this source")
+
+// SearchHit is a search result, only detailed enough to let the user pick one
+type SearchHit struct {
+	Id      string
+	Slug    string
+	Name    string
+	Summary string
+}
+
+// Provider is a source minepack can add content from. implementations register themselves
+// with Register from an init function. methods that talk to the source take a context that
+// stops their requests when cancelled, e.g. when the user presses ctrl+c
+type Provider interface {
+	// Name is the identifier used in flags and project files, e.g. "modrinth"
+	Name() string
+	// Source is the value stored in ContentData for content from this provider
+	Source() project.Source
+	// ParseURL extracts a project identifier from a link to the source's website
+	ParseURL(url string) (string, bool)
+	// Search finds projects matching a query, filtered by the pack's versions
+	Search(ctx context.Context, query string, packData project.Project) ([]SearchHit, error)
+	// GetProject fetches a project by id or slug with its newest compatible version
+	GetProject(ctx context.Context, id string, packData project.Project) (*project.ContentData, error)
+	// ListVersions fetches every compatible version of a project, newest first
+	ListVersions(ctx context.Context, id string, packData project.Project) ([]project.ContentData, error)
+	// ResolveDependencies fetches the newest compatible version of many dependencies at once, keyed by dependency id
+	ResolveDependencies(ctx context.Context, deps []project.Dependency, packData project.Project) (map[string]*project.ContentData, error)
+	// DownloadJob describes how to download a piece of content from this source
+	DownloadJob(content project.ContentData, dest string) download.Job
+	// LookupHashes identifies files by hash, keyed by the hash they were found with
+	LookupHashes(ctx context.Context, hashes []string, format project.HashFormat) (map[string]project.ContentData, error)
+}
+
+// Updater is implemented by providers that need more than the content id to find its newest
+// version, like github where the asset pattern has to be reused
+type Updater interface {
+	LatestVersion(ctx context.Context, content project.ContentData, packData project.Project) (*project.ContentData, error)
+}
+
+// BulkUpdater is implemented by providers that can find the newest version of many pieces of
+// content at once, instead of asking about each of them
+type BulkUpdater interface {
+	// LatestVersions is keyed by content id, content without a compatible version is left out
+	LatestVersions(ctx context.Context, contents []project.ContentData, packData project.Project) (map[string]*project.ContentData, error)
+}
+
+// VersionComparer is implemented by providers whose version ids can be ordered, like release
+// tags. without it, any different version id counts as an update
+type VersionComparer interface {
+	IsNewer(current project.ContentData, candidate project.ContentData) bool
+}
+
+// Configurable is implemented by providers whose API location and HTTP settings can be changed,
+// e.g. to use a mirror or a local stand-in server
+type Configurable interface {
+	Configure(settings project.SourceSettings) error
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = make(map[string]Provider)
+)
+
+// Register makes a provider available by name, replacing any provider with the same name
+func Register(p Provider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[p.Name()] = p
+}
+
+// Get returns the provider registered under a name
+func Get(name string) (Provider, error) {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	p, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown source: %s (must be one of %v)", name, namesLocked())
+	}
+	return p, nil
+}
+
+// ForSource returns the provider content from a source belongs to
+func ForSource(source project.Source) (Provider, bool) {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	for _, p := range providers {
+		if p.Source() == source {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// ForPack returns the provider for a project's default source
+func ForPack(packData project.Project) (Provider, error) {
+	name := packData.DefaultSource
+	if name == "" {
+		name = DefaultProvider
+	}
+	return Get(name)
+}
+
+// Names lists every registered provider, the default one first
+func Names() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	return namesLocked()
+}
+
+func namesLocked() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == DefaultProvider) != (names[j] == DefaultProvider) {
+			return names[i] == DefaultProvider
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// DownloadJob builds a download job for content using its provider, falling back to the
+// stored download URL for content no provider claims
+func DownloadJob(content project.ContentData, dest string) download.Job {
+	if p, ok := ForSource(content.Source); ok {
+		return p.DownloadJob(content, dest)
+	}
+	return download.JobFromContent(content, dest)
+}
+
+// LatestVersion fetches the newest compatible version of content from its provider
+func LatestVersion(ctx context.Context, content project.ContentData, packData project.Project) (*project.ContentData, error) {
+	p, ok := ForSource(content.Source)
+	if !ok {
+		return nil, fmt.Errorf("%s content can't be updated: %w", project.SourceToString(content.Source), ErrUnsupported)
+	}
+	if updater, ok := p.(Updater); ok {
+		return updater.LatestVersion(ctx, content, packData)
+	}
+	return p.GetProject(ctx, content.Id, packData)
+}
+
+// LatestVersions fetches the newest compatible version of many pieces of content, keyed by slug.
+// providers that implement BulkUpdater are asked about all of their content at once, the others
+// about each content. content that couldn't be checked has its error in errs instead
+func LatestVersions(ctx context.Context, contents []project.ContentData, packData project.Project) (latest map[string]*project.ContentData, errs map[string]error) {
+	latest = make(map[string]*project.ContentData, len(contents))
+	errs = make(map[string]error)
+
+	for source, sourceContents := range groupBySource(contents) {
+		p, ok := ForSource(source)
+		bulk, isBulk := p.(BulkUpdater)
+		if !ok || !isBulk {
+			for _, content := range sourceContents {
+				if ctx.Err() != nil {
+					return latest, errs
+				}
+				if found, err := LatestVersion(ctx, content, packData); err != nil {
+					errs[content.Slug] = err
+				} else {
+					latest[content.Slug] = found
+				}
+			}
+			continue
+		}
+
+		found, err := bulk.LatestVersions(ctx, sourceContents, packData)
+		for _, content := range sourceContents {
+			switch {
+			case err != nil:
+				errs[content.Slug] = err
+			case found[content.Id] == nil:
+				errs[content.Slug] = fmt.Errorf("no compatible versions found for %s project %s", p.Name(), content.Id)
+			default:
+				latest[content.Slug] = found[content.Id]
+			}
+		}
+	}
+	return latest, errs
+}
+
+// IsNewer reports whether a candidate version should replace the current one
+func IsNewer(current project.ContentData, candidate project.ContentData) bool {
+	if candidate.VersionId == "" || candidate.VersionId == current.VersionId {
+		return false
+	}
+	if p, ok := ForSource(current.Source); ok {
+		if comparer, ok := p.(VersionComparer); ok {
+			return comparer.IsNewer(current, candidate)
+		}
+	}
+	return true
+}
+
+// Configure applies the user's settings for each source, overridden by the project's own, to
+// every provider that supports it. packData may be nil outside a project
+func Configure(global map[string]project.SourceSettings, packData *project.Project) error {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
+	for name, p := range providers {
+		configurable, ok := p.(Configurable)
+		if !ok {
+			continue
+		}
+		settings := global[name]
+		if packData != nil {
+			settings = settings.Merge(packData.Sources[name])
+		}
+		if err := configurable.Configure(settings); err != nil {
+			return fmt.Errorf("invalid settings for %s: %w", name, err)
+		}
+	}
+	return nil
+}
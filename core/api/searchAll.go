@@ -1,227 +1,101 @@
 package api
 
 import (
-	"minepack/core/api/curseforge"
-	"minepack/core/api/modrinth"
 	"minepack/core/project"
 
-	modrinthApi "codeberg.org/jmansfield/go-modrinth/modrinth"
-
+	"context"
 	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/huh"
 )
 
-func extractSlugFromURL(url string) (string, error) {
-	if url == "" {
-		return "", fmt.Errorf("empty URL")
-	}
-	// Modrinth: https://modrinth.com/mod/<slug>
-	if strings.Contains(url, "modrinth.com/mod/") {
-		parts := strings.Split(url, "/")
-		for i, part := range parts {
-			if part == "mod" && i > 0 && parts[i-1] == "modrinth.com" {
-				if i+1 < len(parts) {
-					return parts[i+1], nil
-				}
-			}
-			if part == "mod" && i+1 < len(parts) {
-				return parts[i+1], nil
-			}
-		}
-		// fallback: last part
-		return parts[len(parts)-1], nil
-	}
-
-	// CurseForge: https://www.curseforge.com/minecraft/mc-mods/<slug>
-	if strings.Contains(url, "curseforge.com/minecraft/mc-mods/") {
-		parts := strings.Split(url, "/")
-		for i, part := range parts {
-			if part == "mc-mods" && i+1 < len(parts) {
-				return parts[i+1], nil
-			}
+// providerForURL finds the provider whose website a link points at, returning the project identifier from it
+func providerForURL(url string) (Provider, string) {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	for _, p := range providers {
+		if id, ok := p.ParseURL(url); ok {
+			return p, id
 		}
-		// fallback: last part
-		return parts[len(parts)-1], nil
 	}
-
-	// Not a supported URL
-	return "", nil
+	return nil, ""
 }
 
-func findBySlugMatch(query string, packData project.Project) (*project.ContentData, error) {
-	var defaultSource = packData.DefaultSource
-	if defaultSource == "" {
-		defaultSource = "modrinth"
-	}
-
-	var result project.ContentData
-	var foundResult bool = false
-	if defaultSource == "modrinth" {
-		mrresult, err := modrinth.GetProject(query)
-		if err != nil || mrresult == nil {
-			return nil, nil
-		}
-		mrversions, err := modrinth.GetProjectVersions(*mrresult.ID, packData)
-		if err != nil || len(mrversions) == 0 {
-			return nil, nil
-		}
-		result = modrinth.ConvertProjectToContentData(mrresult, mrversions[0])
-		foundResult = true
-	}
-	if defaultSource == "curseforge" {
-		cfresult, err := curseforge.GetProject(query)
-		if err != nil || cfresult == nil {
-			return nil, nil
-		}
-		var stringid string = fmt.Sprintf("%d", cfresult.ID)
-		cfversions, err := curseforge.GetProjectVersions(stringid, packData)
-		if err != nil || len(cfversions) == 0 {
-			return nil, nil
-		}
-		result = curseforge.ConvertModToContentData(cfresult, &cfversions[0])
-		foundResult = true
+func findBySlugMatch(ctx context.Context, provider Provider, query string, packData project.Project) (*project.ContentData, error) {
+	result, err := provider.GetProject(ctx, query, packData)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
 	}
-	if foundResult {
-		return &result, nil
+	if err != nil || result == nil {
+		return nil, nil
 	}
-	return nil, nil
+	return result, nil
 }
 
-func findBySearch(query string, packData project.Project) (*project.ContentData, error) {
-	var defaultSource = packData.DefaultSource
-	if defaultSource == "" {
-		defaultSource = "modrinth"
+func findBySearch(ctx context.Context, provider Provider, query string, packData project.Project) (*project.ContentData, error) {
+	hits, err := provider.Search(ctx, query, packData)
+	if err != nil {
+		return nil, err
+	}
+	if len(hits) == 0 {
+		return nil, nil
 	}
 
-	var result *project.ContentData
-	var resultFound bool = false
-
-	if defaultSource == "modrinth" {
-		mrresults, err := modrinth.SearchProjects(query, packData, false)
-		if err != nil {
-			return nil, err
-		}
-		if len(mrresults) > 0 {
-			// if multiple results, use huh to ask the user to pick one
-			var opts []huh.Option[*modrinthApi.SearchResult]
-
-			for _, r := range mrresults {
-				opts = append(opts, huh.NewOption(
-					fmt.Sprintf("%s \033[90m(%s)\033[0m", *r.Title, *r.Slug), r),
-				)
-			}
-
-			var chosen *modrinthApi.SearchResult
-
-			searchResultsForm := huh.NewForm(
-				huh.NewGroup(
-					huh.NewSelect[*modrinthApi.SearchResult]().
-						Title("multiple modrinth search results found").
-						Description("please select the best matching mod (or press ctrl+c to cancel)").
-						Options(opts...).
-						Value(&chosen),
-				),
+	chosen := hits[0]
+	if len(hits) > 1 {
+		// if multiple results, use huh to ask the user to pick one
+		var opts []huh.Option[SearchHit]
+		for _, hit := range hits {
+			opts = append(opts, huh.NewOption(
+				fmt.Sprintf("%s \033[90m(%s)\033[0m", hit.Name, hit.Slug), hit),
 			)
-
-			err := searchResultsForm.Run()
-			if err != nil {
-				return nil, fmt.Errorf("prompt failed %v", err)
-			}
-
-			mrversions, err := modrinth.GetProjectVersions(*chosen.Slug, packData)
-			if err != nil || len(mrversions) == 0 {
-				return nil, err
-			}
-			mrproject, err := modrinth.GetProject(*chosen.Slug)
-			if err != nil || mrproject == nil {
-				return nil, err
-			}
-			resultData := modrinth.ConvertProjectToContentData(mrproject, mrversions[0])
-			result = &resultData
-			resultFound = true
 		}
-	}
-	if defaultSource == "curseforge" {
-		cfresults, err := curseforge.SearchProjects(query, packData, false)
-		if err != nil {
-			return nil, err
-		}
-		if len(cfresults) == 1 {
-			var stringid string = fmt.Sprintf("%d", cfresults[0].ID)
-			cfversions, err := curseforge.GetProjectVersions(stringid, packData)
-			if err != nil || len(cfversions) == 0 {
-				return nil, err
-			}
-			cfproject, err := curseforge.GetProject(stringid)
-			if err != nil || cfproject == nil {
-				return nil, err
-			}
-			resultData := curseforge.ConvertModToContentData(cfproject, &cfversions[0])
-			result = &resultData
-			resultFound = true
-		}
-		if len(cfresults) > 1 {
-			// if multiple results, use huh to ask the user to pick one
-			var opts []huh.Option[*curseforge.Mod]
-
-			for _, r := range cfresults {
-				opts = append(opts, huh.NewOption(r.Name, &r))
-			}
-
-			var chosen *curseforge.Mod
-
-			searchResultsForm := huh.NewForm(
-				huh.NewGroup(
-					huh.NewSelect[*curseforge.Mod]().
-						Title("multiple curseforge search results found").
-						Description("please select the best matching mod").
-						Options(opts...).
-						Value(&chosen),
-				),
-			)
 
-			err := searchResultsForm.Run()
-			if err != nil {
-				return nil, fmt.Errorf("prompt failed %v", err)
-			}
-
-			var stringid string = fmt.Sprintf("%d", chosen.ID)
-			cfversions, err := curseforge.GetProjectVersions(stringid, packData)
-			if err != nil || len(cfversions) == 0 {
-				return nil, err
-			}
-			cfproject, err := curseforge.GetProject(stringid)
-			if err != nil || cfproject == nil {
-				return nil, err
-			}
-			resultData := curseforge.ConvertModToContentData(cfproject, &cfversions[0])
-			result = &resultData
-			resultFound = true
+		searchResultsForm := huh.NewForm(
+			huh.NewGroup(
+				huh.NewSelect[SearchHit]().
+					Title(fmt.Sprintf("multiple %s search results found", provider.Name())).
+					Description("please select the best matching mod (or press ctrl+c to cancel)").
+					Options(opts...).
+					Value(&chosen),
+			),
+		)
+
+		if err := searchResultsForm.RunWithContext(ctx); err != nil {
+			return nil, fmt.Errorf("prompt failed %v", err)
 		}
 	}
-	if resultFound {
-		return result, nil
-	}
-	return nil, nil
+
+	return provider.GetProject(ctx, chosen.Id, packData)
 }
 
-func SearchAll(query string, packData project.Project) (*project.ContentData, error) {
+func SearchAll(ctx context.Context, query string, packData project.Project) (*project.ContentData, error) {
 	if query == "" {
 		return nil, fmt.Errorf("empty query")
 	}
-	// first, see if the input is a URL and if so, extract the slug from it
-	slug, err := extractSlugFromURL(query)
+
+	provider, err := ForPack(packData)
 	if err != nil {
 		return nil, err
 	}
-	if slug != "" {
+
+	// first, see if the input names a source like "github:owner/repo" or is a URL, and if so
+	// extract the identifier from it
+	if name, rest, found := strings.Cut(query, ":"); found && !strings.HasPrefix(rest, "//") {
+		if named, err := Get(name); err == nil {
+			// an explicit source is only ever looked up directly, so errors like a missing
+			// release asset aren't hidden behind a search
+			return named.GetProject(ctx, rest, packData)
+		}
+	}
+	if urlProvider, slug := providerForURL(query); urlProvider != nil {
+		provider = urlProvider
 		query = slug
 	}
 
 	// second, try directly matching the slug with the project's default source
-	result, err := findBySlugMatch(query, packData)
+	result, err := findBySlugMatch(ctx, provider, query, packData)
 	if err != nil {
 		return nil, err
 	}
@@ -230,7 +104,7 @@ func SearchAll(query string, packData project.Project) (*project.ContentData, er
 	}
 
 	// third, search default source for the query
-	result, err = findBySearch(query, packData)
+	result, err = findBySearch(ctx, provider, query, packData)
 	if err != nil {
 		return nil, err
 	}
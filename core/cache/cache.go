@@ -0,0 +1,487 @@
+package cache
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"minepack/core/project"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Entry describes a single file stored in the cache
+type Entry struct {
+	Sha512     string    `yaml:"sha512"`
+	Sha1       string    `yaml:"sha1"`
+	Sha256     string    `yaml:"sha256,omitempty"` // the only hash github content has
+	Md5        string    `yaml:"md5"`
+	Filename   string    `yaml:"filename"`
+	Size       int64     `yaml:"size"`
+	URLs       []string  `yaml:"urls,omitempty"`
+	Added      time.Time `yaml:"added"`
+	LastAccess time.Time `yaml:"last_access"`
+	Projects   []string  `yaml:"projects"` // roots of the projects that have used this file
+}
+
+// index is the on-disk record of everything in the cache
+type index struct {
+	Entries map[string]*Entry `yaml:"entries"` // sha512 -> entry
+}
+
+// Cache is a user-level, content-addressed store of downloaded files shared by every project
+type Cache struct {
+	root     string
+	mu       sync.Mutex
+	entries  map[string]*Entry
+	bySha1   map[string]*Entry
+	bySha256 map[string]*Entry
+	byMd5    map[string]*Entry
+	byURL    map[string]*Entry
+	dirty    bool
+}
+
+// Dir returns the cache location, honouring $XDG_CACHE_HOME
+func Dir() (string, error) {
+	if xdg := os.Getenv("XDG_CACHE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "minepack"), nil
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find user cache directory: %w", err)
+	}
+	return filepath.Join(base, "minepack"), nil
+}
+
+// Open loads the user cache, creating it if needed
+func Open() (*Cache, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	return OpenAt(dir)
+}
+
+// OpenAt loads a cache rooted at the given directory
+func OpenAt(root string) (*Cache, error) {
+	if err := os.MkdirAll(filepath.Join(root, "files"), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "tmp"), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	c := &Cache{root: root}
+	idx, err := readIndex(c.indexPath())
+	if err != nil {
+		return nil, err
+	}
+	c.load(idx)
+	return c, nil
+}
+
+// Root returns the directory the cache lives in
+func (c *Cache) Root() string {
+	return c.root
+}
+
+func (c *Cache) indexPath() string {
+	return filepath.Join(c.root, "index.mp.yaml")
+}
+
+// Path returns where the file for an entry is stored
+func (c *Cache) Path(e *Entry) string {
+	return filepath.Join(c.root, "files", e.Sha512[:2], e.Sha512)
+}
+
+// TempFile creates a file inside the cache for an in-progress download
+func (c *Cache) TempFile() (*os.File, error) {
+	return os.CreateTemp(filepath.Join(c.root, "tmp"), "download-*.part")
+}
+
+func (c *Cache) load(idx *index) {
+	c.entries = make(map[string]*Entry)
+	c.bySha1 = make(map[string]*Entry)
+	c.bySha256 = make(map[string]*Entry)
+	c.byMd5 = make(map[string]*Entry)
+	c.byURL = make(map[string]*Entry)
+	for sha, e := range idx.Entries {
+		e.Sha512 = sha
+		c.insert(e)
+	}
+}
+
+func (c *Cache) insert(e *Entry) {
+	c.entries[e.Sha512] = e
+	if e.Sha1 != "" {
+		c.bySha1[e.Sha1] = e
+	}
+	if e.Sha256 != "" {
+		c.bySha256[e.Sha256] = e
+	}
+	if e.Md5 != "" {
+		c.byMd5[e.Md5] = e
+	}
+	for _, u := range e.URLs {
+		c.byURL[u] = e
+	}
+}
+
+// Lookup finds a cached file by any of its known hashes, or by URL when no hashes are known
+func (c *Cache) Lookup(hashes project.Hashes, url string) (*Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var e *Entry
+	switch {
+	case hashes.Sha512 != "":
+		e = c.entries[strings.ToLower(hashes.Sha512)]
+	case hashes.Sha1 != "":
+		e = c.bySha1[strings.ToLower(hashes.Sha1)]
+	case hashes.Sha256 != "":
+		e = c.bySha256[strings.ToLower(hashes.Sha256)]
+	case hashes.Md5 != "":
+		e = c.byMd5[strings.ToLower(hashes.Md5)]
+	case url != "":
+		e = c.byURL[url]
+	}
+	if e == nil {
+		return nil, false
+	}
+
+	// the index can outlive the file if someone cleaned the directory by hand
+	if _, err := os.Stat(c.Path(e)); err != nil {
+		c.remove(e)
+		return nil, false
+	}
+	return e, true
+}
+
+// Use marks an entry as accessed by a project and returns its path
+func (c *Cache) Use(e *Entry, projectRoot string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e.LastAccess = time.Now()
+	if projectRoot != "" && !contains(e.Projects, projectRoot) {
+		e.Projects = append(e.Projects, projectRoot)
+	}
+	c.dirty = true
+	return c.Path(e)
+}
+
+// Add hashes a finished download, moves it into the store and records it
+func (c *Cache) Add(srcPath, filename, url, projectRoot string) (*Entry, error) {
+	sha512Sum, sha1Sum, sha256Sum, md5Sum, size, err := hashFile(srcPath)
+	if err != nil {
+		os.Remove(srcPath)
+		return nil, fmt.Errorf("failed to hash %s: %w", filename, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	e, exists := c.entries[sha512Sum]
+	if !exists {
+		e = &Entry{
+			Sha512:   sha512Sum,
+			Sha1:     sha1Sum,
+			Sha256:   sha256Sum,
+			Md5:      md5Sum,
+			Filename: filename,
+			Size:     size,
+			Added:    now,
+		}
+	}
+	// entries cached before sha256 was recorded get it now
+	e.Sha256 = sha256Sum
+	e.LastAccess = now
+	if projectRoot != "" && !contains(e.Projects, projectRoot) {
+		e.Projects = append(e.Projects, projectRoot)
+	}
+	if url != "" && !contains(e.URLs, url) {
+		e.URLs = append(e.URLs, url)
+	}
+
+	dest := c.Path(e)
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		os.Remove(srcPath)
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.Rename(srcPath, dest); err != nil {
+		os.Remove(srcPath)
+		return nil, fmt.Errorf("failed to store %s in cache: %w", filename, err)
+	}
+
+	c.insert(e)
+	c.dirty = true
+	return e, nil
+}
+
+// Entries returns every cached file, most recently used first
+func (c *Cache) Entries() []*Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries := make([]*Entry, 0, len(c.entries))
+	for _, e := range c.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastAccess.After(entries[j].LastAccess)
+	})
+	return entries
+}
+
+// Remove deletes a single entry and its file
+func (c *Cache) Remove(e *Entry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.Remove(c.Path(e)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	c.remove(e)
+	return nil
+}
+
+func (c *Cache) remove(e *Entry) {
+	delete(c.entries, e.Sha512)
+	if c.bySha1[e.Sha1] == e {
+		delete(c.bySha1, e.Sha1)
+	}
+	if c.bySha256[e.Sha256] == e {
+		delete(c.bySha256, e.Sha256)
+	}
+	if c.byMd5[e.Md5] == e {
+		delete(c.byMd5, e.Md5)
+	}
+	for _, u := range e.URLs {
+		if c.byURL[u] == e {
+			delete(c.byURL, u)
+		}
+	}
+	c.dirty = true
+}
+
+// Clear removes every cached file
+func (c *Cache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, dir := range []string{"files", "tmp"} {
+		if err := os.RemoveAll(filepath.Join(c.root, dir)); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Join(c.root, dir), 0755); err != nil {
+			return err
+		}
+	}
+	c.load(&index{})
+	if err := os.Remove(c.indexPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	c.dirty = false
+	return nil
+}
+
+// Prune removes entries not used within olderThan, then evicts the least recently used
+// entries until the cache fits in maxSize. zero values disable either limit
+func (c *Cache) Prune(olderThan time.Duration, maxSize int64, dryRun bool) ([]*Entry, error) {
+	entries := c.Entries()
+
+	var removed []*Entry
+	var kept []*Entry
+	cutoff := time.Now().Add(-olderThan)
+	for _, e := range entries {
+		if olderThan > 0 && e.LastAccess.Before(cutoff) {
+			removed = append(removed, e)
+		} else {
+			kept = append(kept, e)
+		}
+	}
+
+	if maxSize > 0 {
+		var total int64
+		var fits []*Entry
+		// kept is ordered most recently used first, so the oldest spill over
+		for _, e := range kept {
+			if total+e.Size <= maxSize {
+				total += e.Size
+				fits = append(fits, e)
+			} else {
+				removed = append(removed, e)
+			}
+		}
+		kept = fits
+	}
+
+	if dryRun {
+		return removed, nil
+	}
+
+	for _, e := range removed {
+		if err := c.Remove(e); err != nil {
+			return nil, fmt.Errorf("failed to remove %s: %w", e.Filename, err)
+		}
+	}
+	return removed, c.Save()
+}
+
+// Save writes the index back to disk, merging with changes made by other processes
+func (c *Cache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty {
+		return nil
+	}
+
+	onDisk, err := readIndex(c.indexPath())
+	if err != nil {
+		return err
+	}
+	for sha, other := range onDisk.Entries {
+		mine, exists := c.entries[sha]
+		if !exists {
+			// keep entries another process added, as long as the file is still there
+			other.Sha512 = sha
+			if _, err := os.Stat(c.Path(other)); err == nil {
+				c.insert(other)
+			}
+			continue
+		}
+		if other.LastAccess.After(mine.LastAccess) {
+			mine.LastAccess = other.LastAccess
+		}
+		for _, p := range other.Projects {
+			if !contains(mine.Projects, p) {
+				mine.Projects = append(mine.Projects, p)
+			}
+		}
+	}
+
+	data, err := yaml.Marshal(&index{Entries: c.entries})
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache index: %w", err)
+	}
+
+	tmp := c.indexPath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache index: %w", err)
+	}
+	if err := os.Rename(tmp, c.indexPath()); err != nil {
+		return fmt.Errorf("failed to write cache index: %w", err)
+	}
+
+	c.dirty = false
+	return nil
+}
+
+// LinkOrCopy places a cached file at dest, hardlinking when possible and copying otherwise. a
+// hardlinked file shares its contents with the cache, so this is only for folders minepack owns
+// and removes again, anywhere else use Copy
+func LinkOrCopy(src, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	if err := os.Remove(dest); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Link(src, dest); err == nil {
+		return nil
+	}
+	return copyFile(src, dest)
+}
+
+// Copy places a cached file at dest as a copy of its own, so editing it in place, e.g. from a
+// launcher, leaves the cache untouched
+func Copy(src, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	if err := os.Remove(dest); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return copyFile(src, dest)
+}
+
+func copyFile(src, dest string) error {
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dest)
+		return err
+	}
+	return out.Close()
+}
+
+func readIndex(path string) (*index, error) {
+	idx := &index{Entries: make(map[string]*Entry)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache index: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, idx); err != nil {
+		return nil, fmt.Errorf("failed to parse cache index: %w", err)
+	}
+	if idx.Entries == nil {
+		idx.Entries = make(map[string]*Entry)
+	}
+	return idx, nil
+}
+
+// hashFile computes every hash the cache indexes by in a single pass
+func hashFile(path string) (sha512Sum, sha1Sum, sha256Sum, md5Sum string, size int64, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", "", "", "", 0, err
+	}
+	defer file.Close()
+
+	h512 := sha512.New()
+	h1 := sha1.New()
+	h256 := sha256.New()
+	hmd5 := md5.New()
+	size, err = io.Copy(io.MultiWriter(h512, h1, h256, hmd5), file)
+	if err != nil {
+		return "", "", "", "", 0, err
+	}
+
+	return hex.EncodeToString(h512.Sum(nil)), hex.EncodeToString(h1.Sum(nil)), hex.EncodeToString(h256.Sum(nil)), hex.EncodeToString(hmd5.Sum(nil)), size, nil
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Response is an HTTP response body stored for later use
+type Response struct {
+	URL        string      `json:"url"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	Stored     time.Time   `json:"stored"`
+}
+
+// HTTPResponse rebuilds an *http.Response for a request from the stored copy
+func (r *Response) HTTPResponse(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+// MetadataStore keeps API and manifest responses on disk, separately from downloaded files
+type MetadataStore struct {
+	dir string
+}
+
+// OpenMetadata opens the metadata store inside the user cache
+func OpenMetadata() (*MetadataStore, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	return OpenMetadataAt(filepath.Join(dir, "metadata"))
+}
+
+// OpenMetadataAt opens a metadata store in the given directory
+func OpenMetadataAt(dir string) (*MetadataStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create metadata cache directory: %w", err)
+	}
+	return &MetadataStore{dir: dir}, nil
+}
+
+// Dir returns the directory the store lives in
+func (s *MetadataStore) Dir() string {
+	return s.dir
+}
+
+func (s *MetadataStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(s.dir, name[:2], name+".json")
+}
+
+// Get returns the stored response for a key
+func (s *MetadataStore) Get(key string) (*Response, bool) {
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var resp Response
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, false
+	}
+	return &resp, true
+}
+
+// Put stores a response under a key, replacing any previous one
+func (s *MetadataStore) Put(key string, resp *Response) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	// write then rename so concurrent readers never see half a response
+	tmp, err := os.CreateTemp(filepath.Dir(path), "*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Clear removes every stored response
+func (s *MetadataStore) Clear() error {
+	if err := os.RemoveAll(s.dir); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return os.MkdirAll(s.dir, 0755)
+}
+
+// Responses returns every stored response, newest first
+func (s *MetadataStore) Responses() ([]*Response, error) {
+	var responses []*Response
+	err := filepath.WalkDir(s.dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var resp Response
+		if err := json.Unmarshal(data, &resp); err != nil {
+			// a corrupt entry is just a cache miss, skip it
+			return nil
+		}
+		responses = append(responses, &resp)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata cache: %w", err)
+	}
+
+	sort.Slice(responses, func(i, j int) bool {
+		return responses[i].Stored.After(responses[j].Stored)
+	})
+	return responses, nil
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"minepack/core/project"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Dir returns the user configuration directory, honouring $XDG_CONFIG_HOME
+func Dir() (string, error) {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "minepack"), nil
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find user config directory: %w", err)
+	}
+	return filepath.Join(base, "minepack"), nil
+}
+
+// Config is the user's config.mp.yaml, settings in it apply to every project
+type Config struct {
+	Network NetworkSettings                   `yaml:"network,omitempty"`
+	Sources map[string]project.SourceSettings `yaml:"sources,omitempty"` // keyed by source name, e.g. "modrinth"
+}
+
+// NetworkSettings apply to every request minepack makes
+type NetworkSettings struct {
+	Proxy    string `yaml:"proxy,omitempty"`     // overrides the HTTP_PROXY and HTTPS_PROXY environment variables
+	CABundle string `yaml:"ca_bundle,omitempty"` // PEM file of extra trusted certificates
+}
+
+// Path returns where the user config file lives
+func Path() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.mp.yaml"), nil
+}
+
+// Load reads the user config, a missing file is the same as an empty one
+func Load() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &cfg, nil
+}
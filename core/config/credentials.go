@@ -0,0 +1,240 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"minepack/util"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MavenCredential authenticates requests to a private maven repository. it applies to every URL
+// on the same scheme and host at or below URL's path, so one entry can cover a whole repository
+type MavenCredential struct {
+	URL      string `yaml:"url"`
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	Token    string `yaml:"token,omitempty"` // sent as a bearer token instead of basic auth
+}
+
+// Credentials is the user's credentials.mp.yaml, kept apart from projects so secrets never end up in a pack
+type Credentials struct {
+	Tokens map[string]string `yaml:"tokens,omitempty"` // API keys and tokens keyed by source name, e.g. "curseforge"
+	Maven  []MavenCredential `yaml:"maven,omitempty"`
+}
+
+// CredentialsPath returns where the credentials file lives
+func CredentialsPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "credentials.mp.yaml"), nil
+}
+
+// LoadCredentials reads the credentials file, a missing file is the same as an empty one
+func LoadCredentials() (*Credentials, error) {
+	path, err := CredentialsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Credentials{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials: %w", err)
+	}
+
+	var creds Credentials
+	if err := yaml.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &creds, nil
+}
+
+// SaveCredentials writes the credentials file, readable only by the current user
+func SaveCredentials(creds *Credentials) error {
+	path, err := CredentialsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	data, err := yaml.Marshal(creds)
+	if err != nil {
+		return fmt.Errorf("failed to encode credentials: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write credentials: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write credentials: %w", err)
+	}
+	return nil
+}
+
+// MavenFor returns the credential with the longest URL prefix covering a URL
+func (c *Credentials) MavenFor(rawURL string) (MavenCredential, bool) {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return MavenCredential{}, false
+	}
+
+	var best MavenCredential
+	bestLength := -1
+	for _, cred := range c.Maven {
+		prefix, err := url.Parse(cred.URL)
+		if err != nil || prefix.Host == "" || !covers(prefix, target) {
+			continue
+		}
+		if length := len(strings.TrimSuffix(prefix.Path, "/")); length > bestLength {
+			best, bestLength = cred, length
+		}
+	}
+	return best, bestLength >= 0
+}
+
+// covers reports whether target is on the same scheme and host as prefix and below its path.
+// paths only match at a "/", so https://maven.example.com/private doesn't cover
+// https://maven.example.com/private-other or https://maven.example.com.evil.org
+func covers(prefix *url.URL, target *url.URL) bool {
+	if !strings.EqualFold(prefix.Scheme, target.Scheme) || !strings.EqualFold(prefix.Host, target.Host) {
+		return false
+	}
+	base := strings.TrimSuffix(prefix.Path, "/")
+	return base == "" || target.Path == base || strings.HasPrefix(target.Path, base+"/")
+}
+
+var (
+	credentials     *Credentials
+	credentialsOnce sync.Once
+)
+
+// loadedCredentials reads the credentials file once per run
+func loadedCredentials() *Credentials {
+	credentialsOnce.Do(func() {
+		creds, err := LoadCredentials()
+		if err != nil {
+			fmt.Printf(util.FormatWarning("warning: %s\n"), err)
+			creds = &Credentials{}
+		}
+		credentials = creds
+	})
+	return credentials
+}
+
+// Authorize adds maven credentials to a request whose URL is covered by the credentials file
+func Authorize(req *http.Request) {
+	cred, ok := loadedCredentials().MavenFor(req.URL.String())
+	if !ok {
+		return
+	}
+	if cred.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+cred.Token)
+	} else if cred.Username != "" {
+		req.SetBasicAuth(cred.Username, cred.Password)
+	}
+}
+
+// Origin describes where a token was found
+type Origin string
+
+const (
+	OriginNone    Origin = ""
+	OriginEnv     Origin = "environment"
+	OriginFile    Origin = "credentials file"
+	OriginKeyring Origin = "keyring"
+)
+
+// EnvVars lists the environment variables checked for a source's token, in order
+func EnvVars(source string) []string {
+	vars := []string{"MINEPACK_" + strings.ToUpper(source) + "_TOKEN"}
+	switch source {
+	case "curseforge":
+		vars = append(vars, "CURSEFORGE_API_KEY")
+	case "modrinth":
+		vars = append(vars, "MODRINTH_TOKEN")
+	case "github":
+		vars = append(vars, "GITHUB_TOKEN")
+	}
+	return vars
+}
+
+// Token looks up a source's API key or token in environment variables, then the credentials
+// file, then the OS keyring, reporting where it came from
+func Token(source string) (string, Origin) {
+	for _, name := range EnvVars(source) {
+		if token := os.Getenv(name); token != "" {
+			return token, OriginEnv
+		}
+	}
+	if token := loadedCredentials().Tokens[source]; token != "" {
+		return token, OriginFile
+	}
+	if token, err := keyringGet(source); err == nil && token != "" {
+		return token, OriginKeyring
+	}
+	return "", OriginNone
+}
+
+// SaveToken stores a token in the OS keyring when one is available, otherwise in the credentials
+// file, and returns where it went
+func SaveToken(source string, token string) (Origin, error) {
+	if KeyringAvailable() {
+		if err := keyringSet(source, token); err == nil {
+			return OriginKeyring, nil
+		}
+	}
+
+	creds, err := LoadCredentials()
+	if err != nil {
+		return OriginNone, err
+	}
+	if creds.Tokens == nil {
+		creds.Tokens = make(map[string]string)
+	}
+	creds.Tokens[source] = token
+	if err := SaveCredentials(creds); err != nil {
+		return OriginNone, err
+	}
+	return OriginFile, nil
+}
+
+// DeleteToken removes a source's token from both the keyring and the credentials file, reporting
+// whether anything was removed. tokens from environment variables can't be removed
+func DeleteToken(source string) (bool, error) {
+	removed := false
+	if KeyringAvailable() {
+		if token, err := keyringGet(source); err == nil && token != "" {
+			if err := keyringDelete(source); err != nil {
+				return false, err
+			}
+			removed = true
+		}
+	}
+
+	creds, err := LoadCredentials()
+	if err != nil {
+		return removed, err
+	}
+	if _, ok := creds.Tokens[source]; ok {
+		delete(creds.Tokens, source)
+		if err := SaveCredentials(creds); err != nil {
+			return removed, err
+		}
+		removed = true
+	}
+	return removed, nil
+}
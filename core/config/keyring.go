@@ -0,0 +1,119 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// the keyring is reached through the tools each OS ships, so no cgo or extra libraries are needed:
+// "security" on macOS and "secret-tool" (libsecret) on linux
+
+const keyringService = "minepack"
+
+// keyringTimeout keeps a locked or missing keyring daemon from hanging a command
+const keyringTimeout = 5 * time.Second
+
+var errNoKeyring = errors.New("no supported keyring available")
+
+// keyringTool returns the command used to talk to the OS keyring, or "" if there is none
+func keyringTool() string {
+	var tool string
+	switch runtime.GOOS {
+	case "darwin":
+		tool = "security"
+	case "linux", "freebsd", "openbsd":
+		tool = "secret-tool"
+	default:
+		return ""
+	}
+	if _, err := exec.LookPath(tool); err != nil {
+		return ""
+	}
+	return tool
+}
+
+// KeyringAvailable reports whether tokens can be stored in the OS keyring
+func KeyringAvailable() bool {
+	return keyringTool() != ""
+}
+
+func runKeyring(stdin string, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), keyringTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %s", name, msg)
+		}
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+func keyringGet(source string) (string, error) {
+	switch keyringTool() {
+	case "security":
+		return runKeyring("", "security", "find-generic-password", "-s", keyringService, "-a", source, "-w")
+	case "secret-tool":
+		return runKeyring("", "secret-tool", "lookup", "service", keyringService, "account", source)
+	}
+	return "", errNoKeyring
+}
+
+func keyringSet(source string, token string) error {
+	var err error
+	switch keyringTool() {
+	case "security":
+		err = securitySet(source, token)
+	case "secret-tool":
+		_, err = runKeyring(token, "secret-tool", "store", "--label", "minepack "+source+" token", "service", keyringService, "account", source)
+	default:
+		err = errNoKeyring
+	}
+	return err
+}
+
+// securitySet stores a token with macOS's security tool. arguments are visible to every user in
+// the process list, so the command is fed to "security -i" on stdin instead of passing the token
+// as an argument. that input is split on whitespace and quotes, so tokens containing them are
+// refused and end up in the credentials file
+func securitySet(source string, token string) error {
+	if token == "" || strings.ContainsAny(token, " \t\r\n\"'\\") {
+		return errors.New("token can't be passed to security safely")
+	}
+	command := fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n", keyringService, source, token)
+	if _, err := runKeyring(command, "security", "-i"); err != nil {
+		return err
+	}
+	// interactive mode doesn't fail when a command does, so check that the token was stored
+	if stored, err := keyringGet(source); err != nil || stored != token {
+		return errors.New("security didn't store the token")
+	}
+	return nil
+}
+
+func keyringDelete(source string) error {
+	var err error
+	switch keyringTool() {
+	case "security":
+		_, err = runKeyring("", "security", "delete-generic-password", "-s", keyringService, "-a", source)
+	case "secret-tool":
+		_, err = runKeyring("", "secret-tool", "clear", "service", keyringService, "account", source)
+	default:
+		err = errNoKeyring
+	}
+	return err
+}
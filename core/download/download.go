@@ -0,0 +1,463 @@
+package download
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"minepack/core"
+	"minepack/core/cache"
+	"minepack/core/config"
+	"minepack/core/project"
+	"minepack/util"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DefaultConcurrency is the number of parallel downloads used when none is configured
+const DefaultConcurrency = 3
+
+// Job describes a single file that should end up at Dest. an empty Dest only fills the cache
+type Job struct {
+	Name   string
+	URL    string
+	Dest   string
+	Size   int64
+	Hashes project.Hashes
+}
+
+// Result reports what happened to a single job
+type Result struct {
+	Job     Job
+	Err     error
+	Skipped bool           // the destination already existed and matched
+	Cached  bool           // the file came from the download cache instead of the network
+	Hashes  project.Hashes // hashes of the downloaded file, only computed for jobs without sha1 or sha512
+	done    bool
+}
+
+// Manager downloads batches of jobs with a shared worker pool and progress display
+type Manager struct {
+	Concurrency int
+	Cache       *cache.Cache // optional, downloads are shared through it when set
+	Project     string       // root of the project the downloads are for, recorded in the cache
+	LinkFiles   bool         // hardlink cached files into place, only for scratch folders minepack removes again
+	Interrupt   func()       // optional, called when ctrl+c is pressed in the progress bar so the command stops too
+	client      *http.Client
+}
+
+// NewManager creates a download manager, falling back to DefaultConcurrency for values below 1
+func NewManager(concurrency int) *Manager {
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
+	}
+	return &Manager{
+		Concurrency: concurrency,
+		client:      core.NewDownloadClient(),
+	}
+}
+
+// JobFromContent builds a job for a ContentData entry
+func JobFromContent(content project.ContentData, dest string) Job {
+	return Job{
+		Name:   content.Name,
+		URL:    content.DownloadUrl,
+		Dest:   dest,
+		Size:   content.File.Filesize,
+		Hashes: content.File.Hashes,
+	}
+}
+
+// group is a set of jobs that resolve to the same file and are only downloaded once
+type group struct {
+	key  string
+	jobs []int // indexes into the original job slice
+}
+
+// dedupeKey returns the identity used to merge jobs that point at the same file
+func dedupeKey(job Job) string {
+	switch {
+	case job.Hashes.Sha512 != "":
+		return "sha512:" + strings.ToLower(job.Hashes.Sha512)
+	case job.Hashes.Sha1 != "":
+		return "sha1:" + strings.ToLower(job.Hashes.Sha1)
+	case job.URL != "":
+		return "url:" + job.URL
+	default:
+		return "dest:" + job.Dest
+	}
+}
+
+// Run downloads every job, deduplicating identical files, and returns one result per job in order.
+// the returned error is only non-nil when the run was cancelled
+func (m *Manager) Run(ctx context.Context, jobs []Job) ([]Result, error) {
+	results := make([]Result, len(jobs))
+	for i, job := range jobs {
+		results[i].Job = job
+	}
+	if len(jobs) == 0 {
+		return results, nil
+	}
+
+	// merge jobs that point at the same file
+	var groups []*group
+	byKey := make(map[string]*group)
+	for i, job := range jobs {
+		key := dedupeKey(job)
+		g, exists := byKey[key]
+		if !exists {
+			g = &group{key: key}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.jobs = append(g.jobs, i)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// the progress bar receives ctrl+c as a key instead of a signal, so it stops the command as well
+	rep := newReporter(len(groups), m.Concurrency, func() {
+		cancel()
+		if m.Interrupt != nil {
+			m.Interrupt()
+		}
+	})
+
+	work := make(chan *group)
+	var wg sync.WaitGroup
+	for w := 0; w < m.Concurrency; w++ {
+		wg.Add(1)
+		go func(workerID int) {
+			defer wg.Done()
+			for g := range work {
+				m.runGroup(ctx, workerID, g, jobs, results, rep)
+			}
+		}(w)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer func() {
+			close(work)
+			wg.Wait()
+			rep.finish()
+			close(done)
+		}()
+		for _, g := range groups {
+			select {
+			case work <- g:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	rep.wait()
+	<-done
+
+	if m.Cache != nil {
+		if err := m.Cache.Save(); err != nil {
+			fmt.Printf(util.FormatWarning("failed to save download cache index: %s\n"), err)
+		}
+	}
+
+	// anything that never ran was cancelled
+	if ctx.Err() != nil {
+		for i := range results {
+			if !results[i].done {
+				results[i].Err = ctx.Err()
+			}
+		}
+		return results, ctx.Err()
+	}
+
+	return results, nil
+}
+
+// runGroup obtains the file for a group once and places it at every destination
+func (m *Manager) runGroup(ctx context.Context, workerID int, g *group, jobs []Job, results []Result, rep reporter) {
+	primary := jobs[g.jobs[0]]
+	rep.update(event{worker: workerID, name: primary.Name})
+
+	src, cached, err := m.obtain(ctx, workerID, g, jobs, rep)
+
+	// content without sha1 or sha512 hashes, which the cache and exports rely on, gets them
+	// worked out from the first download
+	var computed project.Hashes
+	if err == nil && src != "" && primary.Hashes.Sha512 == "" && primary.Hashes.Sha1 == "" {
+		computed, err = HashFile(src)
+	}
+
+	for _, idx := range g.jobs {
+		job := jobs[idx]
+		results[idx].done = true
+		results[idx].Hashes = computed
+		switch {
+		case err != nil:
+			results[idx].Err = err
+		case job.Dest == "":
+			results[idx].Cached = cached
+		case job.Dest == src || existingMatches(job):
+			results[idx].Skipped = true
+		case m.LinkFiles:
+			results[idx].Err = cache.LinkOrCopy(src, job.Dest)
+			results[idx].Cached = cached
+		default:
+			results[idx].Err = cache.Copy(src, job.Dest)
+			results[idx].Cached = cached
+		}
+	}
+
+	rep.update(event{worker: workerID, name: primary.Name, progress: 1.0, done: true, err: err})
+}
+
+// obtain returns a path holding the file for a group, preferring an existing destination,
+// then the cache, and only then the network. the bool reports whether the cache was used
+func (m *Manager) obtain(ctx context.Context, workerID int, g *group, jobs []Job, rep reporter) (string, bool, error) {
+	primary := jobs[g.jobs[0]]
+
+	for _, idx := range g.jobs {
+		if jobs[idx].Dest != "" && hasHashes(jobs[idx].Hashes) && existingMatches(jobs[idx]) {
+			return jobs[idx].Dest, false, nil
+		}
+	}
+
+	if m.Cache != nil {
+		if entry, ok := m.Cache.Lookup(primary.Hashes, primary.URL); ok {
+			return m.Cache.Use(entry, m.Project), true, nil
+		}
+	}
+
+	if m.Cache == nil {
+		if primary.Dest == "" {
+			return "", false, fmt.Errorf("no destination for %s", primary.Name)
+		}
+		if existingMatches(primary) {
+			return primary.Dest, false, nil
+		}
+		if core.IsOffline() {
+			return "", false, fmt.Errorf("%s is not downloaded yet: %w", primary.Name, core.ErrOffline)
+		}
+		partPath := primary.Dest + ".part"
+		if err := m.fetch(ctx, workerID, primary, partPath, rep); err != nil {
+			return "", false, err
+		}
+		if err := os.Rename(partPath, primary.Dest); err != nil {
+			os.Remove(partPath)
+			return "", false, fmt.Errorf("failed to move %s into place: %w", primary.Name, err)
+		}
+		return primary.Dest, false, nil
+	}
+
+	if core.IsOffline() {
+		return "", false, fmt.Errorf("%s is not in the download cache: %w", primary.Name, core.ErrOffline)
+	}
+
+	tmp, err := m.Cache.TempFile()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to create cache file: %w", err)
+	}
+	partPath := tmp.Name()
+	tmp.Close()
+
+	if err := m.fetch(ctx, workerID, primary, partPath, rep); err != nil {
+		return "", false, err
+	}
+	entry, err := m.Cache.Add(partPath, filenameOf(primary), primary.URL, m.Project)
+	if err != nil {
+		return "", false, err
+	}
+	return m.Cache.Path(entry), false, nil
+}
+
+// fetch downloads a job to partPath and verifies it, removing the file on failure
+func (m *Manager) fetch(ctx context.Context, workerID int, job Job, partPath string, rep reporter) error {
+	if job.URL == "" {
+		return fmt.Errorf("no download URL available for %s", job.Name)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(partPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", job.URL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", core.UserAgent())
+	config.Authorize(req)
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", job.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download of %s failed with status: %s", job.Name, resp.Status)
+	}
+
+	total := resp.ContentLength
+	if total <= 0 {
+		total = job.Size
+	}
+
+	// write to a temporary file first so an interrupted download never looks complete
+	out, err := os.Create(partPath)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	pw := &progressWriter{worker: workerID, name: job.Name, total: total, rep: rep}
+	_, err = io.Copy(out, io.TeeReader(resp.Body, pw))
+	closeErr := out.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(partPath)
+		return fmt.Errorf("failed to write %s: %w", job.Name, err)
+	}
+
+	if err := VerifyFile(partPath, job.Hashes); err != nil {
+		os.Remove(partPath)
+		return fmt.Errorf("%s: %w", job.Name, err)
+	}
+
+	return nil
+}
+
+// filenameOf picks a human readable name for a job's file, used in cache listings
+func filenameOf(job Job) string {
+	if job.Dest != "" {
+		return filepath.Base(job.Dest)
+	}
+	if job.URL != "" {
+		name := job.URL[strings.LastIndex(job.URL, "/")+1:]
+		if i := strings.IndexAny(name, "?#"); i >= 0 {
+			name = name[:i]
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return job.Name
+}
+
+func hasHashes(hashes project.Hashes) bool {
+	return hashes.Sha512 != "" || hashes.Sha256 != "" || hashes.Sha1 != "" || hashes.Md5 != ""
+}
+
+// progressWriter forwards byte counts from a download to the reporter
+type progressWriter struct {
+	worker     int
+	name       string
+	total      int64
+	downloaded int64
+	rep        reporter
+}
+
+func (pw *progressWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	pw.downloaded += int64(n)
+	if pw.total > 0 {
+		pw.rep.update(event{
+			worker:   pw.worker,
+			name:     pw.name,
+			progress: float64(pw.downloaded) / float64(pw.total),
+		})
+	}
+	return n, nil
+}
+
+// ErrHashMismatch is returned when a downloaded file does not match its expected hash
+var ErrHashMismatch = errors.New("hash mismatch")
+
+// VerifyFile checks a file against the strongest hash available, doing nothing if none are known
+func VerifyFile(path string, hashes project.Hashes) error {
+	var h hash.Hash
+	var expected string
+	switch {
+	case hashes.Sha512 != "":
+		h, expected = sha512.New(), hashes.Sha512
+	case hashes.Sha256 != "":
+		h, expected = sha256.New(), hashes.Sha256
+	case hashes.Sha1 != "":
+		h, expected = sha1.New(), hashes.Sha1
+	case hashes.Md5 != "":
+		h, expected = md5.New(), hashes.Md5
+	default:
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(h, file); err != nil {
+		return err
+	}
+
+	actual := hex.EncodeToString(h.Sum(nil))
+	if !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("%w: expected %s, got %s", ErrHashMismatch, expected, actual)
+	}
+	return nil
+}
+
+// HashFile computes every hash minepack stores for a file
+func HashFile(path string) (project.Hashes, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return project.Hashes{}, err
+	}
+	defer file.Close()
+
+	h1, h256, h512, hmd5 := sha1.New(), sha256.New(), sha512.New(), md5.New()
+	if _, err := io.Copy(io.MultiWriter(h1, h256, h512, hmd5), file); err != nil {
+		return project.Hashes{}, fmt.Errorf("failed to hash %s: %w", filepath.Base(path), err)
+	}
+
+	return project.Hashes{
+		Sha1:   hex.EncodeToString(h1.Sum(nil)),
+		Sha256: hex.EncodeToString(h256.Sum(nil)),
+		Sha512: hex.EncodeToString(h512.Sum(nil)),
+		Md5:    hex.EncodeToString(hmd5.Sum(nil)),
+	}, nil
+}
+
+// existingMatches reports whether the destination already holds the expected file
+func existingMatches(job Job) bool {
+	if !fileExists(job.Dest) {
+		return false
+	}
+	return VerifyFile(job.Dest, job.Hashes) == nil
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// Failed returns only the results that ended in an error
+func Failed(results []Result) []Result {
+	var failed []Result
+	for _, r := range results {
+		if r.Err != nil {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
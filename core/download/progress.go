@@ -0,0 +1,226 @@
+package download
+
+import (
+	"context"
+	"fmt"
+	"minepack/util"
+	"os"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
+)
+
+// event is a progress update from a worker
+type event struct {
+	worker   int
+	name     string
+	progress float64 // 0.0 to 1.0
+	done     bool
+	err      error
+}
+
+// reporter displays download progress
+type reporter interface {
+	update(ev event)
+	finish()
+	wait()
+}
+
+// newReporter picks a bubbletea progress display on a terminal and plain lines otherwise
+func newReporter(total int, workers int, cancel context.CancelFunc) reporter {
+	if term.IsTerminal(int(os.Stdout.Fd())) {
+		return newTeaReporter(total, workers, cancel)
+	}
+	return &plainReporter{total: total, finished: make(chan struct{})}
+}
+
+// plainReporter prints one line per finished file, suitable for logs and pipes
+type plainReporter struct {
+	mu       sync.Mutex
+	total    int
+	current  int
+	finished chan struct{}
+}
+
+func (r *plainReporter) update(ev event) {
+	if !ev.done {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.current++
+	if ev.err != nil {
+		fmt.Printf(util.FormatError("[%d/%d] failed %s: %s\n"), r.current, r.total, ev.name, ev.err)
+		return
+	}
+	fmt.Printf("[%d/%d] downloaded %s\n", r.current, r.total, ev.name)
+}
+
+func (r *plainReporter) finish() {
+	close(r.finished)
+}
+
+func (r *plainReporter) wait() {
+	<-r.finished
+}
+
+// teaReporter drives a bubbletea program with an overall bar and one bar per worker
+type teaReporter struct {
+	program  *tea.Program
+	mu       sync.Mutex
+	lastSent map[int]int // worker -> last percentage sent, to avoid flooding the program
+}
+
+type finishMsg struct{}
+
+func newTeaReporter(total int, workers int, cancel context.CancelFunc) *teaReporter {
+	return &teaReporter{
+		program:  tea.NewProgram(newProgressModel(total, workers, cancel)),
+		lastSent: make(map[int]int),
+	}
+}
+
+func (r *teaReporter) update(ev event) {
+	if !ev.done {
+		percent := int(ev.progress * 100)
+		r.mu.Lock()
+		last, seen := r.lastSent[ev.worker]
+		if seen && last == percent {
+			r.mu.Unlock()
+			return
+		}
+		r.lastSent[ev.worker] = percent
+		r.mu.Unlock()
+	}
+	r.program.Send(ev)
+}
+
+func (r *teaReporter) finish() {
+	r.program.Send(finishMsg{})
+}
+
+func (r *teaReporter) wait() {
+	if _, err := r.program.Run(); err != nil {
+		fmt.Printf(util.FormatError("progress bar error: %s\n"), err)
+	}
+}
+
+// progressModel is the bubbletea model shared by every download
+type progressModel struct {
+	globalProgress   progress.Model
+	workerProgresses []progress.Model
+	workerNames      []string
+	workerActive     []bool
+	workerProgress   []float64
+	current          int
+	total            int
+	failed           int
+	done             bool
+	cancelled        bool
+	cancel           context.CancelFunc
+}
+
+func newProgressModel(total int, workers int, cancel context.CancelFunc) progressModel {
+	globalProg := progress.New(progress.WithDefaultGradient())
+	globalProg.Width = 60
+
+	workerProgs := make([]progress.Model, workers)
+	for i := range workerProgs {
+		workerProgs[i] = progress.New(progress.WithDefaultGradient())
+		workerProgs[i].Width = 40
+	}
+
+	return progressModel{
+		globalProgress:   globalProg,
+		workerProgresses: workerProgs,
+		workerNames:      make([]string, workers),
+		workerActive:     make([]bool, workers),
+		workerProgress:   make([]float64, workers),
+		total:            total,
+		cancel:           cancel,
+	}
+}
+
+func (m progressModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m progressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case event:
+		if msg.worker < 0 || msg.worker >= len(m.workerProgresses) {
+			return m, nil
+		}
+		if msg.done {
+			m.workerActive[msg.worker] = false
+			m.workerNames[msg.worker] = ""
+			m.workerProgress[msg.worker] = 1.0
+			m.current++
+			if msg.err != nil {
+				m.failed++
+			}
+		} else {
+			m.workerActive[msg.worker] = true
+			m.workerNames[msg.worker] = msg.name
+			m.workerProgress[msg.worker] = msg.progress
+		}
+		return m, nil
+	case finishMsg:
+		m.done = true
+		return m, tea.Quit
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			m.cancelled = true
+			m.cancel()
+			return m, tea.Quit
+		}
+		return m, nil
+	default:
+		var cmd tea.Cmd
+		globalModel, cmd := m.globalProgress.Update(msg)
+		m.globalProgress = globalModel.(progress.Model)
+
+		for i := range m.workerProgresses {
+			workerModel, _ := m.workerProgresses[i].Update(msg)
+			m.workerProgresses[i] = workerModel.(progress.Model)
+		}
+		return m, cmd
+	}
+}
+
+func (m progressModel) View() string {
+	if m.cancelled {
+		return util.FormatWarning("download cancelled\n")
+	}
+
+	if m.done {
+		if m.failed > 0 {
+			return fmt.Sprintf(util.FormatWarning("downloaded %d/%d files (%d failed)\n"), m.current-m.failed, m.total, m.failed)
+		}
+		return fmt.Sprintf(util.FormatSuccess("downloaded all %d files\n"), m.total)
+	}
+
+	// global progress
+	globalPercent := 0.0
+	if m.total > 0 {
+		globalPercent = float64(m.current) / float64(m.total)
+	}
+	view := fmt.Sprintf("overall progress: %d/%d\n%s\n\n",
+		m.current, m.total, m.globalProgress.ViewAs(globalPercent))
+
+	// individual worker progress bars
+	view += "downloading:\n"
+	for i := range m.workerProgresses {
+		if m.workerActive[i] {
+			view += fmt.Sprintf("worker %d: %s\n%s\n",
+				i+1, m.workerNames[i], m.workerProgresses[i].ViewAs(m.workerProgress[i]))
+		} else {
+			view += fmt.Sprintf("worker %d: waiting...\n%s\n",
+				i+1, m.workerProgresses[i].ViewAs(m.workerProgress[i]))
+		}
+	}
+
+	return view
+}
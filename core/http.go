@@ -0,0 +1,290 @@
+package core
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"minepack/core/config"
+	"minepack/util/version"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// every request minepack makes goes through sharedTransport, wrapped by Transport for caching,
+// offline mode and rate limits
+
+// DefaultAPITimeout bounds API and metadata requests that don't configure their own timeout.
+// downloads have no overall timeout, only the connection and header timeouts of the transport
+const DefaultAPITimeout = 60 * time.Second
+
+// maxRetryDelay is the longest a rate limited request waits before trying again, anything
+// longer is reported as an error instead
+const maxRetryDelay = time.Minute
+
+// maxRetries is how many times a rate limited request is retried
+const maxRetries = 3
+
+// UserAgent identifies minepack and its version to the servers it talks to
+func UserAgent() string {
+	return "minepack/" + version.Version + " (+https://github.com/ayeuhugyu/minepack)"
+}
+
+var sharedTransport = newBaseTransport()
+
+func newBaseTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   16,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 60 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+// ConfigureNetwork applies the user's network settings: a proxy that overrides HTTP(S)_PROXY and
+// extra CA certificates, e.g. for a corporate proxy that intercepts TLS. MINEPACK_CA_BUNDLE
+// overrides the configured bundle
+func ConfigureNetwork(settings config.NetworkSettings) error {
+	if settings.Proxy != "" {
+		proxyURL, err := url.Parse(settings.Proxy)
+		if err != nil {
+			return fmt.Errorf("invalid proxy %s: %w", settings.Proxy, err)
+		}
+		sharedTransport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	bundle := settings.CABundle
+	if env := os.Getenv("MINEPACK_CA_BUNDLE"); env != "" {
+		bundle = env
+	}
+	if bundle != "" {
+		pem, err := os.ReadFile(bundle)
+		if err != nil {
+			return fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in CA bundle %s", bundle)
+		}
+		sharedTransport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+	return nil
+}
+
+// RateLimiter spaces out requests to one API and pauses them while the server says the limit is used up
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration // minimum time between requests, 0 for none
+	next     time.Time     // earliest time the next request may start
+}
+
+// NewRateLimiter creates a limiter that allows one request per interval
+func NewRateLimiter(interval time.Duration) *RateLimiter {
+	return &RateLimiter{interval: interval}
+}
+
+// wait blocks until the next request may be sent
+func (l *RateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(start)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pause holds back every request until d has passed
+func (l *RateLimiter) pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := time.Now().Add(d); until.After(l.next) {
+		l.next = until
+	}
+}
+
+// observe reads the X-Ratelimit-* headers modrinth and github send, pausing once the remaining
+// requests run out until the window resets
+func (l *RateLimiter) observe(resp *http.Response) {
+	if resp.Header.Get("X-Ratelimit-Remaining") != "0" {
+		return
+	}
+	if reset, ok := parseReset(resp.Header.Get("X-Ratelimit-Reset")); ok {
+		l.pause(min(reset, maxRetryDelay))
+	}
+}
+
+var (
+	hostLimitersMu sync.Mutex
+	hostLimiters   = make(map[string]*RateLimiter)
+)
+
+// hostLimiter returns the limiter shared by every request to a host without its own limiter
+func hostLimiter(host string) *RateLimiter {
+	hostLimitersMu.Lock()
+	defer hostLimitersMu.Unlock()
+	l, ok := hostLimiters[host]
+	if !ok {
+		l = NewRateLimiter(0)
+		hostLimiters[host] = l
+	}
+	return l
+}
+
+// parseReset reads X-Ratelimit-Reset, which is seconds until the reset on modrinth and a unix
+// timestamp on github
+func parseReset(value string) (time.Duration, bool) {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	if n > 1_000_000_000 {
+		return time.Until(time.Unix(n, 0)), true
+	}
+	return time.Duration(n) * time.Second, true
+}
+
+// retryDelay reports how long to wait before retrying a rate limited or temporarily unavailable
+// response, following Retry-After and then X-Ratelimit-Reset
+func retryDelay(resp *http.Response, attempt int) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	if after := resp.Header.Get("Retry-After"); after != "" {
+		if seconds, err := strconv.Atoi(after); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if at, err := http.ParseTime(after); err == nil {
+			return time.Until(at), true
+		}
+	}
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		return 0, false
+	}
+	if reset, ok := parseReset(resp.Header.Get("X-Ratelimit-Reset")); ok {
+		return reset, true
+	}
+	return time.Duration(1<<attempt) * time.Second, true
+}
+
+// send performs a request through the shared transport, waiting for the rate limiter and retrying
+// rate limited responses while the wait is reasonable
+func (t *Transport) send(req *http.Request) (*http.Response, error) {
+	limiter := t.Limiter
+	if limiter == nil {
+		limiter = hostLimiter(req.URL.Host)
+	}
+	if req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", UserAgent())
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := limiter.wait(req.Context()); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base().RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		limiter.observe(resp)
+
+		delay, retry := retryDelay(resp, attempt)
+		replayable := req.Body == nil || req.GetBody != nil
+		if !retry || attempt >= maxRetries || delay > maxRetryDelay || !replayable {
+			return resp, nil
+		}
+		resp.Body.Close()
+		limiter.pause(delay)
+
+		if req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// WithContext returns a copy of client whose requests are also cancelled with ctx, for libraries
+// like go-modrinth that build their requests without one
+func WithContext(ctx context.Context, client *http.Client) *http.Client {
+	bound := *client
+	bound.Transport = &contextTransport{ctx: ctx, base: client.Transport}
+	return &bound
+}
+
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if err := t.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	stop := context.AfterFunc(t.ctx, cancel)
+	resp, err := base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		stop()
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, release: func() {
+		stop()
+		cancel()
+	}}
+	return resp, nil
+}
+
+// cancelBody releases a request's context once its response has been read
+type cancelBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
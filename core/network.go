@@ -0,0 +1,359 @@
+package core
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"minepack/core/cache"
+	"minepack/core/project"
+	"minepack/util"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// ErrOffline is returned for network requests that can't be answered from the cache in offline mode
+var ErrOffline = errors.New("network access unavailable in offline mode")
+
+var offline atomic.Bool
+var offlineWarning sync.Once
+
+// SetOffline enables or disables offline mode for the rest of the run
+func SetOffline(enabled bool) {
+	offline.Store(enabled)
+}
+
+// IsOffline reports whether network access is disabled
+func IsOffline() bool {
+	return offline.Load()
+}
+
+// goOffline switches to offline mode after a connection failure so later requests fail fast
+func goOffline(host string, cause error) {
+	offline.Store(true)
+	offlineWarning.Do(func() {
+		fmt.Printf(util.FormatWarning("network unreachable, %s failed (%s), continuing in offline mode\n"), host, cause)
+	})
+}
+
+// offlineSources are the APIs nearly every command needs, failing to reach one of them means the
+// network is down as far as minepack is concerned
+var offlineSources = map[string]bool{"modrinth": true, "curseforge": true}
+
+// offlineAfterHosts is how many different hosts have to be unreachable before any other host
+// failing, e.g. a maven mirror or a download server, switches the run to offline mode
+const offlineAfterHosts = 3
+
+var (
+	unreachableMu    sync.Mutex
+	unreachableHosts = make(map[string]bool)
+)
+
+// connectionFailed records that a host couldn't be reached and reports whether the whole run
+// should go offline because of it. a single mistyped mirror or dead host only fails its own requests
+func connectionFailed(source string, host string) bool {
+	unreachableMu.Lock()
+	defer unreachableMu.Unlock()
+	unreachableHosts[host] = true
+	return offlineSources[source] || len(unreachableHosts) >= offlineAfterHosts
+}
+
+// isConnectivityError reports whether an error means the network itself is unreachable,
+// as opposed to a single server being down or answering badly
+func isConnectivityError(err error) bool {
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return false
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// errors for responses that mean the request will keep failing until the user does something
+var (
+	ErrUnauthorized = errors.New("credentials missing or rejected")
+	ErrForbidden    = errors.New("access denied")
+	ErrRateLimited  = errors.New("rate limit reached")
+)
+
+// statusError turns authentication and rate limit responses into errors that say what to do about
+// them, source names the API in messages and login hints, falling back to the host
+func statusError(source string, resp *http.Response) error {
+	name := source
+	if name == "" {
+		name = resp.Request.URL.Host
+	}
+	login := "check the credentials for it in credentials.mp.yaml"
+	if source != "" {
+		login = fmt.Sprintf("check them with \"minepack auth status\" or log in with \"minepack auth login %s\"", source)
+	}
+
+	rateLimited := resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-Ratelimit-Remaining") == "0")
+
+	switch {
+	case rateLimited:
+		wait := resp.Header.Get("Retry-After")
+		if wait == "" {
+			wait = resp.Header.Get("X-Ratelimit-Reset")
+		}
+		if wait != "" {
+			return fmt.Errorf("%s: %w, try again in %ss", name, ErrRateLimited, wait)
+		}
+		return fmt.Errorf("%s: %w, try again later", name, ErrRateLimited)
+	case resp.StatusCode == http.StatusUnauthorized:
+		return fmt.Errorf("%s: %w (401), %s", name, ErrUnauthorized, login)
+	case resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("%s: %w (403), the credentials may not allow this or the project is private; %s", name, ErrForbidden, login)
+	}
+	return nil
+}
+
+// DefaultMetadataTTL is how long a stored response is used without asking the server again
+const DefaultMetadataTTL = 15 * time.Minute
+
+var refresh atomic.Bool
+
+// SetRefresh makes every request revalidate with the server instead of trusting fresh cached responses
+func SetRefresh(enabled bool) {
+	refresh.Store(enabled)
+}
+
+// Transport caches successful GET responses and lookups made with POST, see storeKey, revalidating
+// them with ETag/If-Modified-Since once they are older than TTL, refuses requests in offline mode
+// and replays stored responses when the network is unavailable
+type Transport struct {
+	Base    http.RoundTripper
+	Store   *cache.MetadataStore // optional, nothing is cached or replayed without it
+	TTL     time.Duration
+	Header  http.Header  // extra headers sent with every request that doesn't set them itself
+	Source  string       // name of the API in error messages, e.g. "curseforge"
+	Limiter *RateLimiter // optional, requests share a per-host limiter without one
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return sharedTransport
+}
+
+// credentialHeaders are the request headers that carry credentials
+var credentialHeaders = []string{"Authorization", "X-Api-Key", "Cookie"}
+
+// storeKey identifies a request in the metadata store. GETs are stored by URL, POSTs by URL and a
+// hash of their body, which is how curseforge looks up many projects or files at once, so those
+// lookups can be replayed offline too. other requests are never stored. requests made with
+// credentials are keyed by a hash of them, so a response is never replayed for another
+// credential or once the user logged out
+func storeKey(req *http.Request) string {
+	var key string
+	switch req.Method {
+	case http.MethodGet:
+		key = req.URL.String()
+	case http.MethodPost:
+		if req.GetBody == nil {
+			return ""
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return ""
+		}
+		sum := sha256.New()
+		_, err = io.Copy(sum, body)
+		body.Close()
+		if err != nil {
+			return ""
+		}
+		key = "POST " + req.URL.String() + " " + hex.EncodeToString(sum.Sum(nil))
+	default:
+		return ""
+	}
+	key += " " + req.Header.Get("Accept")
+
+	credentials := sha256.New()
+	authenticated := false
+	for _, name := range credentialHeaders {
+		for _, value := range req.Header.Values(name) {
+			fmt.Fprintf(credentials, "%s: %s\n", name, value)
+			authenticated = true
+		}
+	}
+	if authenticated {
+		key += " " + hex.EncodeToString(credentials.Sum(nil))
+	}
+	return key
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(t.Header) > 0 {
+		req = req.Clone(req.Context())
+		for name, values := range t.Header {
+			if req.Header.Get(name) == "" {
+				req.Header[name] = values
+			}
+		}
+	}
+
+	key := storeKey(req)
+	if IsOffline() {
+		return t.replay(req, key)
+	}
+
+	var stored *cache.Response
+	if t.Store != nil && key != "" {
+		stored, _ = t.Store.Get(key)
+	}
+
+	if stored != nil && !refresh.Load() && time.Since(stored.Stored) < t.TTL {
+		return stored.HTTPResponse(req), nil
+	}
+
+	// ask the server whether our copy is still current
+	if stored != nil {
+		req = req.Clone(req.Context())
+		if etag := stored.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if modified := stored.Header.Get("Last-Modified"); modified != "" {
+			req.Header.Set("If-Modified-Since", modified)
+		}
+	}
+
+	resp, err := t.send(req)
+	if err != nil {
+		if req.Context().Err() == nil && isConnectivityError(err) {
+			if connectionFailed(t.Source, req.URL.Host) {
+				goOffline(req.URL.Host, err)
+				return t.replay(req, key)
+			}
+			// only this host is unreachable, a stored answer still beats failing
+			if stored != nil {
+				return stored.HTTPResponse(req), nil
+			}
+		}
+		return nil, err
+	}
+
+	if err := statusError(t.Source, resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	if stored != nil && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		stored.Stored = time.Now()
+		t.Store.Put(key, stored)
+		return stored.HTTPResponse(req), nil
+	}
+
+	if t.Store == nil || key == "" || resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	t.Store.Put(key, &cache.Response{
+		URL:        req.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+		Stored:     time.Now(),
+	})
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// replay answers a request from the metadata store
+func (t *Transport) replay(req *http.Request, key string) (*http.Response, error) {
+	if t.Store != nil && key != "" {
+		if stored, ok := t.Store.Get(key); ok {
+			return stored.HTTPResponse(req), nil
+		}
+	}
+	return nil, fmt.Errorf("%s has not been cached yet: %w", req.URL.Host, ErrOffline)
+}
+
+var (
+	metadataStore     *cache.MetadataStore
+	metadataStoreOnce sync.Once
+)
+
+// MetadataStore returns the shared store for API responses, or nil if it can't be opened
+func MetadataStore() *cache.MetadataStore {
+	metadataStoreOnce.Do(func() {
+		store, err := cache.OpenMetadata()
+		if err != nil {
+			fmt.Printf(util.FormatWarning("warning: metadata cache unavailable: %s\n"), err)
+			return
+		}
+		metadataStore = store
+	})
+	return metadataStore
+}
+
+// NewHTTPClient creates a client for API and metadata requests that works with offline mode.
+// a zero timeout uses DefaultAPITimeout
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return NewSourceClient("", timeout)
+}
+
+// NewSourceClient creates an API client like NewHTTPClient whose errors name the source it talks to
+func NewSourceClient(source string, timeout time.Duration) *http.Client {
+	if timeout == 0 {
+		timeout = DefaultAPITimeout
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &Transport{Store: MetadataStore(), TTL: DefaultMetadataTTL, Source: source},
+	}
+}
+
+// NewDownloadClient creates a client for file downloads, which are never stored as metadata and
+// have no overall timeout since large files can take a while
+func NewDownloadClient() *http.Client {
+	return &http.Client{Transport: &Transport{}}
+}
+
+// Throttle makes a client made by NewSourceClient send at most one request per interval
+func Throttle(client *http.Client, interval time.Duration) {
+	if transport, ok := client.Transport.(*Transport); ok {
+		transport.Limiter = NewRateLimiter(interval)
+	}
+}
+
+// UseStore makes a client made by NewSourceClient store and replay its responses in store instead
+// of the shared metadata store, nil stores nothing
+func UseStore(client *http.Client, store *cache.MetadataStore) {
+	if transport, ok := client.Transport.(*Transport); ok {
+		transport.Store = store
+	}
+}
+
+// ApplySettings changes the timeout and extra headers of a client made by NewHTTPClient
+func ApplySettings(client *http.Client, settings project.SourceSettings) {
+	if settings.Timeout != 0 {
+		client.Timeout = settings.Timeout
+	}
+	transport, ok := client.Transport.(*Transport)
+	if !ok || len(settings.Headers) == 0 {
+		return
+	}
+	header := make(http.Header, len(settings.Headers))
+	for name, value := range settings.Headers {
+		header.Set(name, value)
+	}
+	transport.Header = header
+}
@@ -0,0 +1,143 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Batch groups the content changes of one command into a single auto-commit. if it is left
+// unfinished, e.g. because the user pressed ctrl+c part way through, the content files and
+// project.mp.yaml are put back the way they were when it began
+type Batch struct {
+	ctx     context.Context
+	project *Project
+	files   map[string][]byte // content, sum and project files as they were when the batch began, by path
+	done    bool
+}
+
+// Begin starts a batch. until it is committed or rolled back, changes made through the project
+// aren't committed one by one, and fail once ctx is cancelled
+func (p *Project) Begin(ctx context.Context) (*Batch, error) {
+	if p.batch.active() {
+		return nil, errors.New("a batch of changes is already in progress")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	files, err := snapshotContent(p.Root)
+	if err != nil {
+		return nil, err
+	}
+	b := &Batch{ctx: ctx, project: p, files: files}
+	p.batch = b
+	return b, nil
+}
+
+// active reports whether changes are currently being grouped, copies of a project share its batch
+func (b *Batch) active() bool {
+	return b != nil && !b.done
+}
+
+// check reports whether changes may still be made, they always may outside a batch
+func (b *Batch) check() error {
+	if !b.active() {
+		return nil
+	}
+	return b.ctx.Err()
+}
+
+// Commit ends the batch with one commit for all of its changes. a cancelled batch is rolled
+// back instead, returning why it was cancelled
+func (b *Batch) Commit(message string) error {
+	if b.done {
+		return nil
+	}
+	if err := b.ctx.Err(); err != nil {
+		if rollbackErr := b.Rollback(); rollbackErr != nil {
+			return rollbackErr
+		}
+		return err
+	}
+	b.finish()
+	return AutoCommit(b.project.Root, message)
+}
+
+// Rollback restores the content, sum and project files the batch began with and drops every change made
+// since. it does nothing after Commit, so it can be deferred right after Begin
+func (b *Batch) Rollback() error {
+	if b.done {
+		return nil
+	}
+	b.finish()
+
+	current, err := snapshotContent(b.project.Root)
+	if err != nil {
+		return err
+	}
+	for path := range current {
+		if _, ok := b.files[path]; !ok {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+	}
+	for path, data := range b.files {
+		if string(current[path]) == string(data) {
+			continue
+		}
+		if err := writeFileAtomic(path, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *Batch) finish() {
+	b.done = true
+	if b.project.batch == b {
+		b.project.batch = nil
+	}
+}
+
+// autoCommit commits a single change, unless it is part of a batch
+func (p *Project) autoCommit(message string) {
+	if p.batch.active() {
+		return
+	}
+	_ = AutoCommit(p.Root, message)
+}
+
+// snapshotContent reads every file a content change or Save can touch
+func snapshotContent(root string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	paths := []string{
+		filepath.Join(root, "project.mp.yaml"),
+		filepath.Join(root, "content.mp.sum.yaml"),
+		filepath.Join(root, "incompat.mp.sum.yaml"),
+	}
+	entries, err := os.ReadDir(filepath.Join(root, "content"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".mp.yaml") {
+			paths = append(paths, filepath.Join(root, "content", entry.Name()))
+		}
+	}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		files[path] = data
+	}
+	return files, nil
+}
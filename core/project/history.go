@@ -0,0 +1,144 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"gopkg.in/yaml.v3"
+)
+
+// CommittedState is the project as one of its commits recorded it
+type CommittedState struct {
+	Commit  string
+	Project Project
+	Content map[string]ContentData   // by slug
+	Files   map[string]plumbing.Hash // git blob hashes of override files, by slash separated path, e.g. overrides/config/sodium.json
+}
+
+// UpstreamBase finds the commit that based the project on its current upstream version, i.e. the
+// import, "upstream set" or sync that every commit since kept the upstream of, and reads the project
+// as it was then. what changed in the project since is what the project changed itself
+func UpstreamBase(projPath string, upstream Upstream) (*CommittedState, error) {
+	repo, err := git.PlainOpen(projPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open git repository: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit %s: %w", head.Hash(), err)
+	}
+
+	var base *object.Commit
+	var baseProject Project
+	for commit != nil {
+		proj, err := committedProject(commit)
+		if err != nil {
+			return nil, err
+		}
+		if proj == nil || proj.Upstream == nil || !sameUpstreamVersion(*proj.Upstream, upstream) {
+			break
+		}
+		base, baseProject = commit, *proj
+
+		if commit.NumParents() == 0 {
+			break
+		}
+		commit, err = commit.Parent(0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent of %s: %w", base.Hash, err)
+		}
+	}
+	if base == nil {
+		return nil, fmt.Errorf("no commit bases the project on %s %s, commit the upstream first", upstream.Source, upstream.VersionId)
+	}
+
+	return readCommittedState(base, baseProject)
+}
+
+func sameUpstreamVersion(a, b Upstream) bool {
+	return a.Source == b.Source && a.Id == b.Id && a.VersionId == b.VersionId
+}
+
+// committedProject reads project.mp.yaml as a commit recorded it, nil when it has none
+func committedProject(commit *object.Commit) (*Project, error) {
+	file, err := commit.File("project.mp.yaml")
+	if errors.Is(err, object.ErrFileNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project.mp.yaml at %s: %w", commit.Hash, err)
+	}
+	reader, err := file.Reader()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project.mp.yaml at %s: %w", commit.Hash, err)
+	}
+	defer reader.Close()
+
+	var proj Project
+	if err := yaml.NewDecoder(reader).Decode(&proj); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse project.mp.yaml at %s: %w", commit.Hash, err)
+	}
+	return &proj, nil
+}
+
+// readCommittedState reads the content files and override files of a commit
+func readCommittedState(commit *object.Commit, proj Project) (*CommittedState, error) {
+	state := &CommittedState{
+		Commit:  commit.Hash.String(),
+		Project: proj,
+		Content: make(map[string]ContentData),
+		Files:   make(map[string]plumbing.Hash),
+	}
+
+	files, err := commit.Files()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files at %s: %w", commit.Hash, err)
+	}
+	err = files.ForEach(func(file *object.File) error {
+		folder, _, _ := strings.Cut(file.Name, "/")
+		switch {
+		case folder == OverridesFolder || folder == ClientOverridesFolder || folder == ServerOverridesFolder:
+			state.Files[file.Name] = file.Hash
+		case folder == "content" && strings.HasSuffix(file.Name, ".mp.yaml"):
+			data, err := file.Contents()
+			if err != nil {
+				return err
+			}
+			var content ContentData
+			if err := yaml.Unmarshal([]byte(data), &content); err != nil {
+				return fmt.Errorf("failed to parse %s: %w", file.Name, err)
+			}
+			state.Content[content.Slug] = content
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read files at %s: %w", commit.Hash, err)
+	}
+	return state, nil
+}
+
+// FileChanged reports whether an override file in the project differs from the committed one,
+// including being added or removed since
+func (s *CommittedState) FileChanged(projPath string, filePath string) (bool, error) {
+	committed, wasCommitted := s.Files[filePath]
+	data, err := os.ReadFile(filepath.Join(projPath, filepath.FromSlash(filePath)))
+	if os.IsNotExist(err) {
+		return wasCommitted, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !wasCommitted || plumbing.ComputeHash(plumbing.BlobObject, data) != committed, nil
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -28,7 +29,61 @@ type Project struct {
 	Author        string
 	Root          string
 	Versions      ProjectVersions
-	DefaultSource string // "modrinth" or "curseforge"
+	DefaultSource string                    // "modrinth" or "curseforge"
+	Sources       map[string]SourceSettings `yaml:",omitempty"` // per source API settings, override the user config
+	Upstream      *Upstream                 `yaml:",omitempty"` // the published modpack the project was imported from
+
+	batch *Batch // set while a command groups its changes, see Begin
+}
+
+// SourceSettings configures how minepack talks to a source's API, e.g. to use a mirror or a
+// local stand-in server. empty fields keep the source's defaults
+type SourceSettings struct {
+	BaseURL   string            `yaml:"base_url,omitempty"`
+	UserAgent string            `yaml:"user_agent,omitempty"`
+	Timeout   time.Duration     `yaml:"timeout,omitempty"`
+	Headers   map[string]string `yaml:"headers,omitempty"` // sent with every API request
+}
+
+// Upstream is a modpack published on modrinth or curseforge that a project is based on, kept to
+// compare the project with the pack's later versions
+type Upstream struct {
+	Source    string `yaml:"source"`            // "modrinth" or "curseforge"
+	Id        string `yaml:"id"`                // the modpack's project id
+	Slug      string `yaml:"slug,omitempty"`    // shown instead of the id where it exists
+	VersionId string `yaml:"version_id"`        // the modrinth version or curseforge file the project is based on
+	Version   string `yaml:"version,omitempty"` // that version's name, for display
+}
+
+// Merge returns the settings with every field set in override replacing its own
+func (s SourceSettings) Merge(override SourceSettings) SourceSettings {
+	if override.BaseURL != "" {
+		s.BaseURL = override.BaseURL
+	}
+	if override.UserAgent != "" {
+		s.UserAgent = override.UserAgent
+	}
+	if override.Timeout != 0 {
+		s.Timeout = override.Timeout
+	}
+	if len(override.Headers) > 0 {
+		headers := make(map[string]string, len(s.Headers)+len(override.Headers))
+		for k, v := range s.Headers {
+			headers[k] = v
+		}
+		for k, v := range override.Headers {
+			headers[k] = v
+		}
+		s.Headers = headers
+	}
+	return s
+}
+
+// IsEmpty reports whether no pack data was filled in, e.g. when searching outside a project
+func (p Project) IsEmpty() bool {
+	return p.Name == "" && p.Description == "" && p.Author == "" && p.Root == "" &&
+		p.Versions == (ProjectVersions{}) && p.DefaultSource == "" && len(p.Sources) == 0 &&
+		p.Upstream == nil
 }
 
 func (p *Project) HasMod(idOrSlug string) bool {
@@ -47,6 +102,9 @@ func (p *Project) HasMod(idOrSlug string) bool {
 
 // add the shorthand version to the sums file and the full file to root/content/slug.mp.yaml
 func (p *Project) AddContent(content ContentData) error {
+	if err := p.batch.check(); err != nil {
+		return err
+	}
 	var sums *[]SummaryObject
 	sums, err := ParseSum(p.Root)
 	if err != nil {
@@ -64,35 +122,29 @@ func (p *Project) AddContent(content ContentData) error {
 		ContentType: content.ContentType,
 		Source:      content.Source,
 	})
-	// write the new sums file
-	err = WriteSum(*sums, p.Root)
-	if err != nil {
-		return err
-	}
-	// add the full file to root/content/slug.mp.yaml
+	// add the full file to root/content/slug.mp.yaml first, so the sums never list a missing file
 	fullPath := filepath.Join(p.Root, "content", fmt.Sprintf("%s.mp.yaml", content.Slug))
 
-	contentFile, err := os.Create(fullPath)
+	err = writeYAML(fullPath, content)
 	if err != nil {
 		return err
 	}
-	defer contentFile.Close()
-
-	encoder := yaml.NewEncoder(contentFile)
-	defer encoder.Close()
-
-	err = encoder.Encode(content)
+	// write the new sums file
+	err = WriteSum(*sums, p.Root)
 	if err != nil {
 		return err
 	}
 
-	// Auto-commit the changes
-	_ = AutoCommit(p.Root, fmt.Sprintf("Add content: %s", content.Slug))
+	// Auto-commit the changes, unless they are part of a batch
+	p.autoCommit(fmt.Sprintf("Add content: %s", content.Slug))
 
 	return nil
 }
 
 func (p *Project) UpdateContent(content ContentData) error {
+	if err := p.batch.check(); err != nil {
+		return err
+	}
 	var sums *[]SummaryObject
 	sums, err := ParseSum(p.Root)
 	if err != nil {
@@ -116,27 +168,21 @@ func (p *Project) UpdateContent(content ContentData) error {
 	}
 	// update the full file at root/content/slug.mp.yaml
 	fullPath := filepath.Join(p.Root, "content", fmt.Sprintf("%s.mp.yaml", content.Slug))
-	contentFile, err := os.Create(fullPath)
-	if err != nil {
-		return err
-	}
-	defer contentFile.Close()
-
-	encoder := yaml.NewEncoder(contentFile)
-	defer encoder.Close()
-
-	err = encoder.Encode(content)
+	err = writeYAML(fullPath, content)
 	if err != nil {
 		return err
 	}
 
-	// Auto-commit the changes
-	_ = AutoCommit(p.Root, fmt.Sprintf("Update content: %s", content.Slug))
+	// Auto-commit the changes, unless they are part of a batch
+	p.autoCommit(fmt.Sprintf("Update content: %s", content.Slug))
 
 	return nil
 }
 
 func (p *Project) RemoveContent(idOrSlug string) error {
+	if err := p.batch.check(); err != nil {
+		return err
+	}
 	var sums *[]SummaryObject
 	sums, err := ParseSum(p.Root)
 	if err != nil {
@@ -160,8 +206,8 @@ func (p *Project) RemoveContent(idOrSlug string) error {
 		return err
 	}
 
-	// Auto-commit the changes
-	_ = AutoCommit(p.Root, fmt.Sprintf("Remove content: %s", idOrSlug))
+	// Auto-commit the changes, unless they are part of a batch
+	p.autoCommit(fmt.Sprintf("Remove content: %s", idOrSlug))
 
 	return nil
 }
@@ -243,6 +289,27 @@ func (p *Project) GetAllContent() ([]ContentData, error) {
 	return contentList, nil
 }
 
+// override folders, laid out like a .mrpack. files in a side's folder are only used on that side
+// and replace the shared override at the same path
+const (
+	OverridesFolder       = "overrides"
+	ClientOverridesFolder = "client-overrides"
+	ServerOverridesFolder = "server-overrides"
+)
+
+// OverrideFolders returns the override folders used on a side ("client" or "server"), in the order
+// they are applied. any other side only gets the shared overrides
+func OverrideFolders(side string) []string {
+	switch side {
+	case "client":
+		return []string{OverridesFolder, ClientOverridesFolder}
+	case "server":
+		return []string{OverridesFolder, ServerOverridesFolder}
+	default:
+		return []string{OverridesFolder}
+	}
+}
+
 // summary file
 
 type SummaryObject struct {
@@ -304,6 +371,7 @@ const (
 	SHA256
 	SHA512
 	MD5
+	Murmur2 // curseforge's fingerprint, see curseforge.Fingerprint
 )
 
 func HashFormatToString(hf HashFormat) string {
@@ -316,6 +384,8 @@ func HashFormatToString(hf HashFormat) string {
 		return "sha512"
 	case MD5:
 		return "md5"
+	case Murmur2:
+		return "murmur2"
 	default:
 		return "unknown"
 	}
@@ -330,6 +400,8 @@ func StringToHashFormat(s string) HashFormat {
 		return SHA512
 	case "md5":
 		return MD5
+	case "murmur2":
+		return Murmur2
 	default:
 		return -1
 	}
@@ -351,6 +423,8 @@ const (
 	Modrinth Source = iota
 	Curseforge
 	Custom
+	GitHub
+	Maven
 )
 
 func SourceToString(s Source) string {
@@ -361,6 +435,10 @@ func SourceToString(s Source) string {
 		return "curseforge"
 	case Custom:
 		return "custom"
+	case GitHub:
+		return "github"
+	case Maven:
+		return "maven"
 	default:
 		return "unknown"
 	}
@@ -373,6 +451,10 @@ func StringToSource(s string) Source {
 		return Curseforge
 	case "custom":
 		return Custom
+	case "github":
+		return GitHub
+	case "maven":
+		return Maven
 	default:
 		return -1
 	}
@@ -420,6 +502,7 @@ type Dependency struct {
 	Name           string
 	Slug           string
 	Id             string
+	VersionId      string `yaml:",omitempty"` // the exact version depended on, when the dependency names one
 	DependencyType DependencyType
 }
 
@@ -448,6 +531,14 @@ type ContentData struct {
 	Dependencies      []Dependency
 	RequiredBy        []RequiredBy
 	AddedAsDependency bool
+	Pinned            bool              `yaml:",omitempty"` // kept at its version by update
+	SourceOptions     map[string]string `yaml:",omitempty"` // source specific settings, e.g. the asset pattern for github or a pinned maven version
+}
+
+// InOverrides reports whether the content's file is kept in the overrides folder instead of being
+// downloaded, which is the case for custom content without a download url
+func (c ContentData) InOverrides() bool {
+	return c.Source == Custom && c.DownloadUrl == ""
 }
 
 type Manifest struct {
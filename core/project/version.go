@@ -150,6 +150,16 @@ func AutoCommit(projPath string, message string) error {
 	return err
 }
 
+// CommitChanges commits whatever changed since the last commit, e.g. files edited by hand, so the
+// change that follows gets a commit of its own. it does nothing when nothing changed
+func CommitChanges(projPath string, message string) error {
+	err := AutoCommit(projPath, message)
+	if errors.Is(err, git.ErrEmptyCommit) {
+		return nil
+	}
+	return err
+}
+
 // ParseSemVer parses a semantic version string
 func ParseSemVer(version string) (major, minor, patch int, err error) {
 	version = strings.TrimPrefix(version, "v")
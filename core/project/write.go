@@ -1,6 +1,7 @@
 package project
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"os"
@@ -25,17 +26,9 @@ func WriteProject(proj *Project) error {
 		return err
 	}
 
-	configPath := filepath.Join(projDir, "project.mp.yaml")
-
-	configFile, err := os.Create(configPath)
-	if err != nil {
+	if err := writeProjectFile(projDir, proj); err != nil {
 		return err
 	}
-	defer configFile.Close()
-
-	encoder := yaml.NewEncoder(configFile)
-	encoder.SetIndent(2)
-	encoder.Encode(proj)
 
 	// if there is not a content folder, create it
 
@@ -53,8 +46,10 @@ func WriteProject(proj *Project) error {
 	// overrides/config
 	// overrides/resourcepacks
 	// overrides/shaderpacks
+	// client-overrides
+	// server-overrides
 
-	overridesDir := filepath.Join(projDir, "overrides")
+	overridesDir := filepath.Join(projDir, OverridesFolder)
 	if _, err := os.Stat(overridesDir); os.IsNotExist(err) {
 		err = os.MkdirAll(overridesDir, os.ModePerm)
 		if err != nil {
@@ -71,6 +66,11 @@ func WriteProject(proj *Project) error {
 			}
 		}
 	}
+	for _, sideDir := range []string{ClientOverridesFolder, ServerOverridesFolder} {
+		if err := os.MkdirAll(filepath.Join(projDir, sideDir), os.ModePerm); err != nil {
+			return err
+		}
+	}
 
 	// if there is not a content.mp.sum.yaml file, create it
 	sumPath := filepath.Join(projDir, "content.mp.sum.yaml")
@@ -121,21 +121,77 @@ func WriteProject(proj *Project) error {
 	return nil
 }
 
-func WriteSumFormat(sums []SummaryObject, sumPath string) error {
-	if sumPath == "" {
-		return errors.New("summary path is empty")
+// Save writes the project's settings to project.mp.yaml after they changed, e.g. its upstream,
+// and commits them with message unless they are part of a batch
+func (p *Project) Save(message string) error {
+	if err := p.batch.check(); err != nil {
+		return err
 	}
+	if err := writeProjectFile(p.Root, p); err != nil {
+		return err
+	}
+
+	// Auto-commit the changes, unless they are part of a batch
+	p.autoCommit(message)
+
+	return nil
+}
 
-	sumFile, err := os.Create(sumPath)
+// writeProjectFile writes project.mp.yaml into projDir
+func writeProjectFile(projDir string, proj *Project) error {
+	var config bytes.Buffer
+	encoder := yaml.NewEncoder(&config)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(proj); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(projDir, "project.mp.yaml"), config.Bytes())
+}
+
+// writeYAML encodes v to a file without ever leaving it half written, see writeFileAtomic
+func writeYAML(path string, v interface{}) error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, buf.Bytes())
+}
+
+// writeFileAtomic writes a file next to its destination and renames it into place, so an
+// interrupted write leaves the old file untouched
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
 	if err != nil {
-		return fmt.Errorf("failed to create sum file: %w", err)
+		return err
 	}
-	defer sumFile.Close()
+	defer os.Remove(tmp.Name())
 
-	encoder := yaml.NewEncoder(sumFile)
-	defer encoder.Close()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func WriteSumFormat(sums []SummaryObject, sumPath string) error {
+	if sumPath == "" {
+		return errors.New("summary path is empty")
+	}
 
-	err = encoder.Encode(sums)
+	err := writeYAML(sumPath, sums)
 	if err != nil {
 		return fmt.Errorf("failed to write YAML sum file: %w", err)
 	}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"minepack/core/project"
+	"strings"
+)
+
+// ServerLoader describes how to get a mod loader running on a dedicated server
+type ServerLoader struct {
+	Name        string   // friendly loader name, e.g. "Fabric"
+	Jar         string   // file name the jar is saved as in a server pack
+	URL         string   // where the jar is downloaded from
+	InstallArgs []string // arguments to run Jar with once to install the server, empty when it is started directly
+	LaunchJar   string   // jar that starts the server, empty when the installer writes its own run scripts
+}
+
+// GetServerLoader works out which jar starts or installs a server for a pack's loader
+func GetServerLoader(ctx context.Context, loader project.ModloaderVersion, mcVersion string) (*ServerLoader, error) {
+	switch loader.Name {
+	case "", "vanilla":
+		url, err := fetchVanillaServerURL(ctx, mcVersion)
+		if err != nil {
+			return nil, err
+		}
+		return &ServerLoader{Name: "Minecraft", Jar: "server.jar", URL: url, LaunchJar: "server.jar"}, nil
+	case "fabric":
+		installer, err := FetchMavenMetadata(ctx, "https://maven.fabricmc.net/net/fabricmc/fabric-installer/maven-metadata.xml")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the latest fabric installer: %w", err)
+		}
+		return &ServerLoader{
+			Name:      "Fabric",
+			Jar:       "fabric-server-launch.jar",
+			URL:       fmt.Sprintf("https://meta.fabricmc.net/v2/versions/loader/%s/%s/%s/server/jar", mcVersion, loader.Version, installer.Versioning.Release),
+			LaunchJar: "fabric-server-launch.jar",
+		}, nil
+	case "quilt":
+		installer, err := FetchMavenMetadata(ctx, "https://maven.quiltmc.org/repository/release/org/quiltmc/quilt-installer/maven-metadata.xml")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the latest quilt installer: %w", err)
+		}
+		version := installer.Versioning.Release
+		return &ServerLoader{
+			Name:        "Quilt",
+			Jar:         "quilt-installer.jar",
+			URL:         fmt.Sprintf("https://maven.quiltmc.org/repository/release/org/quiltmc/quilt-installer/%s/quilt-installer-%s.jar", version, version),
+			InstallArgs: []string{"install", "server", mcVersion, loader.Version, "--download-server", "--install-dir=."},
+			LaunchJar:   "quilt-server-launch.jar",
+		}, nil
+	case "forge":
+		// forge versions are stored without the minecraft version, but its maven needs both
+		version := loader.Version
+		if !strings.HasPrefix(version, mcVersion+"-") {
+			version = mcVersion + "-" + version
+		}
+		return &ServerLoader{
+			Name:        "Forge",
+			Jar:         "forge-installer.jar",
+			URL:         fmt.Sprintf("https://maven.minecraftforge.net/net/minecraftforge/forge/%s/forge-%s-installer.jar", version, version),
+			InstallArgs: []string{"--installServer"},
+		}, nil
+	case "neoforge":
+		// NeoForge for 1.20.1 still used forge's coordinates
+		url := fmt.Sprintf("https://maven.neoforged.net/releases/net/neoforged/neoforge/%s/neoforge-%s-installer.jar", loader.Version, loader.Version)
+		if mcVersion == "1.20.1" {
+			version := loader.Version
+			if !strings.HasPrefix(version, mcVersion+"-") {
+				version = mcVersion + "-" + version
+			}
+			url = fmt.Sprintf("https://maven.neoforged.net/releases/net/neoforged/forge/%s/forge-%s-installer.jar", version, version)
+		}
+		return &ServerLoader{
+			Name:        "NeoForge",
+			Jar:         "neoforge-installer.jar",
+			URL:         url,
+			InstallArgs: []string{"--installServer"},
+		}, nil
+	default:
+		return nil, fmt.Errorf("%s can't run on a dedicated server", ComponentToFriendlyName(loader.Name))
+	}
+}
+
+// fetchVanillaServerURL finds the server jar of a Minecraft version in Mojang's version manifest
+func fetchVanillaServerURL(ctx context.Context, mcVersion string) (string, error) {
+	manifest, err := FetchMinecraftVersions()
+	if err != nil {
+		return "", fmt.Errorf("failed to get minecraft versions: %w", err)
+	}
+
+	for _, version := range manifest.Versions {
+		if version.ID != mcVersion {
+			continue
+		}
+		res, err := GetWithContext(ctx, version.URL, "application/json")
+		if err != nil {
+			return "", fmt.Errorf("failed to get minecraft %s: %w", mcVersion, err)
+		}
+		defer res.Body.Close()
+
+		var details struct {
+			Downloads struct {
+				Server struct {
+					URL string `json:"url"`
+				} `json:"server"`
+			} `json:"downloads"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&details); err != nil {
+			return "", fmt.Errorf("failed to parse minecraft %s: %w", mcVersion, err)
+		}
+		if details.Downloads.Server.URL == "" {
+			return "", fmt.Errorf("minecraft %s has no server jar", mcVersion)
+		}
+		return details.Downloads.Server.URL, nil
+	}
+	return "", fmt.Errorf("unknown minecraft version %s", mcVersion)
+}
@@ -1,10 +1,12 @@
 package core
 
 import (
+	"context"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"minepack/core/config"
 	"net/http"
 	"strings"
 	"time"
@@ -89,19 +91,23 @@ var ModLoaders = map[string]ModLoaderComponent{
 
 // GetWithUA makes an HTTP GET request with a user agent
 func GetWithUA(url string, accept string) (*http.Response, error) {
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
+	return GetWithContext(context.Background(), url, accept)
+}
+
+// GetWithContext makes an HTTP GET request with a user agent that stops when ctx is cancelled
+func GetWithContext(ctx context.Context, url string, accept string) (*http.Response, error) {
+	client := NewHTTPClient(30 * time.Second)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("User-Agent", "minepack/1.0.0")
+	req.Header.Set("User-Agent", UserAgent())
 	if accept != "" {
 		req.Header.Set("Accept", accept)
 	}
+	config.Authorize(req)
 
 	return client.Do(req)
 }
@@ -140,19 +146,31 @@ func GetLatestMinecraftSnapshot() (string, error) {
 	return manifest.Latest.Snapshot, nil
 }
 
+// FetchMavenMetadata reads a maven-metadata.xml file
+func FetchMavenMetadata(ctx context.Context, url string) (*MavenMetadata, error) {
+	res, err := GetWithContext(ctx, url, "application/xml")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("failed to fetch %s: %s", url, res.Status)
+	}
+
+	var metadata MavenMetadata
+	if err := xml.NewDecoder(res.Body).Decode(&metadata); err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
+
 // FetchMavenVersionList creates a version fetcher for simple Maven repositories
 func FetchMavenVersionList(url string) func(mcVersion string) ([]string, string, error) {
 	return func(mcVersion string) ([]string, string, error) {
-		res, err := GetWithUA(url, "application/xml")
+		metadata, err := FetchMavenMetadata(context.Background(), url)
 		if err != nil {
 			return []string{}, "", err
 		}
-		defer res.Body.Close()
-
-		var metadata MavenMetadata
-		if err := xml.NewDecoder(res.Body).Decode(&metadata); err != nil {
-			return []string{}, "", err
-		}
 
 		return metadata.Versioning.Versions.Version, metadata.Versioning.Release, nil
 	}
@@ -161,16 +179,10 @@ func FetchMavenVersionList(url string) func(mcVersion string) ([]string, string,
 // FetchMavenVersionFiltered creates a version fetcher with custom filtering
 func FetchMavenVersionFiltered(url string, friendlyName string, filter func(version string, mcVersion string) bool) func(mcVersion string) ([]string, string, error) {
 	return func(mcVersion string) ([]string, string, error) {
-		res, err := GetWithUA(url, "application/xml")
+		metadata, err := FetchMavenMetadata(context.Background(), url)
 		if err != nil {
 			return []string{}, "", err
 		}
-		defer res.Body.Close()
-
-		var metadata MavenMetadata
-		if err := xml.NewDecoder(res.Body).Decode(&metadata); err != nil {
-			return []string{}, "", err
-		}
 
 		allowedVersions := make([]string, 0, len(metadata.Versioning.Versions.Version))
 		for _, v := range metadata.Versioning.Versions.Version {
@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"minepack/cmd"
 	"minepack/util/version"
@@ -11,8 +13,18 @@ import (
 )
 
 func main() {
+	// the first ctrl+c cancels the context so commands can stop their requests and leave the
+	// project as it was, a second one exits right away
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	context.AfterFunc(ctx, stop)
+
 	// Use fang.Execute with the root command from cmd package
-	if err := fang.Execute(context.Background(), cmd.GetRootCmd(), fang.WithVersion(version.Version)); err != nil {
+	err := fang.Execute(ctx, cmd.GetRootCmd(), fang.WithVersion(version.Version))
+	if ctx.Err() != nil {
+		os.Exit(130)
+	}
+	stop()
+	if err != nil {
 		os.Exit(1)
 	}
 }
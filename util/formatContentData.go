@@ -16,6 +16,8 @@ var boldStyle = lipgloss.NewStyle().Bold(true)
 var grayStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
 var curseforgeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#ef7d4fff"))
 var modrinthStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#48cf7aff"))
+var githubStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#b08ef0ff"))
+var mavenStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#c4566bff"))
 
 func joinLinesByNewline(lines []string) string {
 	result := ""
@@ -50,6 +52,10 @@ func FormatContentData(data project.ContentData) string {
 		lines = append(lines, modrinthStyle.Render(data.PageUrl))
 	case project.Curseforge:
 		lines = append(lines, curseforgeStyle.Render(data.PageUrl))
+	case project.GitHub:
+		lines = append(lines, githubStyle.Render(data.PageUrl))
+	case project.Maven:
+		lines = append(lines, mavenStyle.Render(data.PageUrl))
 	default:
 		lines = append(lines, grayStyle.Render("(no page)"))
 	}